@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"wb_logistic_assistant/internal/app"
+	"wb_logistic_assistant/internal/config"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
+)
+
+// runBackfill Usage: [--headless] backfill finance-daily --from 2026-09-01 --to 2026-09-30 [--output telegram|sheets|csv]
+func runBackfill(appConfig *config.Config, args []string, isHeadless bool) {
+	params, err := parseBackfillParams(args)
+	if err != nil {
+		logger.Logf(logger.FATAL, "Main.runBackfill()", "Invalid backfill arguments: %v", err)
+		return
+	}
+
+	backfill := app.NewBackfill(appConfig, params)
+	backfill.SetHeadless(isHeadless)
+	defer backfill.Stop()
+
+	err = backfill.Init()
+	if err != nil {
+		logger.Logf(logger.FATAL, "Main.runBackfill()", "Failed to initialize backfill: %v", err)
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	err = backfill.Run(ctx)
+	if err != nil {
+		logger.Logf(logger.FATAL, "Main.runBackfill()", "Failed to run backfill: %v", err)
+		return
+	}
+	fmt.Println("Backfill finished")
+}
+
+func parseBackfillParams(args []string) (*app.BackfillParams, error) {
+	if len(args) == 0 {
+		return nil, errors.Newf("Main.parseBackfillParams()", "report is required, supported: %s", app.BackfillReportFinanceDaily)
+	}
+
+	params := &app.BackfillParams{Report: args[0]}
+	var from, to string
+
+	flags := flag.NewFlagSet("backfill "+args[0], flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+	flags.StringVar(&from, "from", "", "first day of the period, YYYY-MM-DD")
+	flags.StringVar(&to, "to", "", "last day of the period, YYYY-MM-DD")
+	flags.StringVar(&params.Output, "output", app.BackfillOutputTelegramBot, "where to send reports: telegram, sheets or csv")
+	flags.Int64Var(&params.ChatID, "chat-id", 0, "Telegram chat id, the report chat from the configuration by default")
+	flags.StringVar(&params.SpreadsheetID, "spreadsheet-id", "", "Google Sheets spreadsheet id")
+	flags.StringVar(&params.SheetName, "sheet-name", "", "Google Sheets sheet name")
+	flags.StringVar(&params.CSVPath, "csv", "", "CSV file path")
+	if err := flags.Parse(args[1:]); err != nil {
+		return nil, errors.Wrap(err, "Main.parseBackfillParams()", "")
+	}
+
+	var err error
+	if params.From, err = time.ParseInLocation(time.DateOnly, from, time.UTC); err != nil {
+		return nil, errors.Wrapf(err, "Main.parseBackfillParams()", "invalid 'from' date '%s'", from)
+	}
+	if params.To, err = time.ParseInLocation(time.DateOnly, to, time.UTC); err != nil {
+		return nil, errors.Wrapf(err, "Main.parseBackfillParams()", "invalid 'to' date '%s'", to)
+	}
+	if params.Output == app.BackfillOutputCSV && params.CSVPath == "" {
+		params.CSVPath = fmt.Sprintf("%s_%s_%s.csv", params.Report, from, to)
+	}
+
+	return params, nil
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 	"wb_logistic_assistant/internal/app"
@@ -12,7 +13,11 @@ import (
 	"wb_logistic_assistant/internal/logger"
 )
 
-const configPath = ".cfg"
+const (
+	configPath  = ".cfg"
+	headlessArg = "--headless"
+	headlessEnv = "WB_ASSISTANT_HEADLESS"
+)
 
 func main() {
 	defer func() {
@@ -27,26 +32,30 @@ func main() {
 		log.Fatalf("Failed load application configuration: %v", err)
 	}
 
-	if appConfig.Debug().Path() != "" {
-		file, err := os.OpenFile(appConfig.Debug().Path(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
-		if err != nil {
-			panic(err)
-		}
-		logger.AddOutput(file)
-		logger.AddOutputErr(file)
+	logFile, err := setupLogger(appConfig.Debug())
+	if err != nil {
+		log.Fatalf("Failed setup logger: %v", err)
+	}
+	if logFile != nil {
+		defer logFile.Close()
 	}
 
-	if appConfig.Debug().Enabled() {
-		logger.AddOutput(os.Stdout)
-		logger.AddOutputErr(os.Stdout)
+	isHeadless, args := parseHeadless(os.Args[1:])
+	if !isHeadless {
+		// nobody reads the console in the headless mode, so the app exits at once
+		logger.SetFatalHandler(func() {
+			fmt.Println("\nApp will be exit in 1 minute...")
+			time.Sleep(1 * time.Minute)
+		})
 	}
 
-	logger.SetFatalHandler(func() {
-		fmt.Println("\nApp will be exit in 1 minute...")
-		time.Sleep(1 * time.Minute)
-	})
+	if len(args) > 0 && args[0] == "backfill" {
+		runBackfill(appConfig, args[1:], isHeadless)
+		return
+	}
 
 	application := app.NewApp(appConfig)
+	application.SetHeadless(isHeadless)
 
 	err = application.Init()
 	if err != nil {
@@ -63,3 +72,58 @@ func main() {
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 	<-stop
 }
+
+// parseHeadless The headless mode is enabled by the '--headless' argument or the env variable, the argument is removed
+func parseHeadless(args []string) (bool, []string) {
+	isHeadless, _ := strconv.ParseBool(os.Getenv(headlessEnv))
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == headlessArg {
+			isHeadless = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return isHeadless, rest
+}
+
+// setupLogger Adds the rotating debug file and stdout if debug is enabled, returns the file to close
+func setupLogger(cfg *config.Debug) (*logger.RotatingFile, error) {
+	encoder, err := logger.NewEncoder(cfg.Format())
+	if err != nil {
+		return nil, err
+	}
+
+	var file *logger.RotatingFile
+	if cfg.Path() != "" {
+		level, err := logger.ParseLevel(cfg.Level())
+		if err != nil {
+			return nil, err
+		}
+		rotation := cfg.Rotation()
+		file, err = logger.NewRotatingFile(cfg.Path(), logger.RotationOptions{
+			MaxSize:    int64(rotation.MaxSizeMB()) << 20,
+			Interval:   rotation.Interval(),
+			MaxBackups: rotation.MaxBackups(),
+			MaxAge:     time.Duration(rotation.MaxAgeDays()) * 24 * time.Hour,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err = logger.AddSink(logger.NewSink(file, level, encoder)); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.Enabled() {
+		level, err := logger.ParseLevel(cfg.ConsoleLevel())
+		if err != nil {
+			return file, err
+		}
+		if err = logger.AddSink(logger.NewSink(os.Stdout, level, encoder)); err != nil {
+			return file, err
+		}
+	}
+	return file, nil
+}
@@ -3,6 +3,7 @@ package wb_logistic_api
 import (
 	"context"
 	"fmt"
+	"wb_logistic_assistant/external/wb_logistic_api/errors"
 	"wb_logistic_assistant/external/wb_logistic_api/models"
 	"wb_logistic_assistant/external/wb_logistic_api/request"
 	"wb_logistic_assistant/external/wb_logistic_api/response"
@@ -11,29 +12,39 @@ import (
 )
 
 type Client struct {
-	client transport.HTTPClient
-	auth   *session.AuthService
+	client    transport.HTTPClient
+	endpoints *request.Endpoints
+	auth      *session.AuthService
 }
 
+// NewClient Client of the production hosts, the base URLs of the services are changed by Endpoints
 func NewClient(client transport.HTTPClient) *Client {
+	endpoints := request.NewEndpoints()
 	return &Client{
-		client: client,
-		auth:   session.NewAuthService(client),
+		client:    client,
+		endpoints: endpoints,
+		auth:      session.NewAuthService(client, endpoints),
 	}
 }
 
+// Endpoints Registry of the base URLs of the services, the changes apply to the next requests
+func (c *Client) Endpoints() *request.Endpoints {
+	return c.endpoints
+}
+
 func (c *Client) getSessionToken(ctx context.Context, s *session.Session) (string, error) {
 	if s == nil {
 		return "", fmt.Errorf("session is nil")
 	}
 	if !s.IsAuth() {
-		return "", fmt.Errorf("session is not auth")
+		return "", fmt.Errorf("session is not auth: %w", errors.ErrUnauthorized)
 	}
 
 	if s.SessionTokenExpired() {
+		// the failed refresh keeps its own kind, it is unauthorized only if the refresh is rejected by 401
 		err := c.RefreshSession(ctx, s)
 		if err != nil {
-			return "", err
+			return "", fmt.Errorf("session token is expired: %w", err)
 		}
 	}
 
@@ -86,16 +97,25 @@ func (c *Client) RefreshAccessToken(ctx context.Context, refreshToken string) (*
 	return c.auth.RefreshAccessToken(ctx, refreshToken)
 }
 
+// RefreshSession The access token is usually issued indefinitely, so the new session token is obtained by it
+// while it is valid and the access token is refreshed only after it expires
 func (c *Client) RefreshSession(ctx context.Context, session *session.Session) error {
 	if session == nil {
 		return fmt.Errorf("session is nil")
 	}
-	if session.Login() == "" || session.RefreshToken() == "" {
+	if session.Login() == "" {
 		return fmt.Errorf("invalid session")
 	}
-	accessToken, err := c.auth.RefreshAccessToken(ctx, session.RefreshToken())
-	if err != nil {
-		return fmt.Errorf("failed refresh session: %w", err)
+	accessToken := session.AccessToken()
+	if accessToken == nil || accessToken.AccessToken == "" || session.AccessTokenExpired() {
+		if session.RefreshToken() == "" {
+			return fmt.Errorf("invalid session")
+		}
+		var err error
+		accessToken, err = c.auth.RefreshAccessToken(ctx, session.RefreshToken())
+		if err != nil {
+			return fmt.Errorf("failed refresh session: %w", err)
+		}
 	}
 
 	sessionToken, userInfo, err := c.GetSessionToken(ctx, session.Login(), accessToken.AccessToken)
@@ -130,15 +150,16 @@ func (c *Client) GetUserInfo(ctx context.Context, s *session.Session) (*response
 		return nil, fmt.Errorf("user info is missing in session")
 	}
 
-	req := request.NewUserGetInfoRequest(c.client, token).
+	req := request.NewUserGetInfoRequest(c.client, c.endpoints, token).
 		ClientID(userInfo.ID)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -148,7 +169,7 @@ func (c *Client) GetUserInfo(ctx context.Context, s *session.Session) (*response
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get user info: %w", res.Error)
+		return nil, fmt.Errorf("failed to get user info: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -160,13 +181,14 @@ func (c *Client) GetRemainsLastMileReports(ctx context.Context, s *session.Sessi
 		return nil, err
 	}
 
-	req := request.NewGetRemainsLastMileReportsRequest(c.client, token)
+	req := request.NewGetRemainsLastMileReportsRequest(c.client, c.endpoints, token)
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -176,7 +198,7 @@ func (c *Client) GetRemainsLastMileReports(ctx context.Context, s *session.Sessi
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get route reports: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get route reports: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -188,15 +210,16 @@ func (c *Client) GetRemainsLastMileReportsRouteInfo(ctx context.Context, s *sess
 		return nil, err
 	}
 
-	req := request.NewGetRemainsLastMileReportsInfoRequest(c.client, token).
+	req := request.NewGetRemainsLastMileReportsInfoRequest(c.client, c.endpoints, token).
 		RouteID(routeID)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -206,7 +229,7 @@ func (c *Client) GetRemainsLastMileReportsRouteInfo(ctx context.Context, s *sess
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get route reports: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get route reports: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -230,15 +253,16 @@ func (c *Client) GetJobsScheduling(ctx context.Context, s *session.Session) (*re
 		return nil, fmt.Errorf("user info is missing in session")
 	}
 
-	req := request.NewGetJobsSchedulingRequest(c.client, token).
+	req := request.NewGetJobsSchedulingRequest(c.client, c.endpoints, token).
 		SupplierID(userInfo.UserDetails.SupplierID)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -248,7 +272,7 @@ func (c *Client) GetJobsScheduling(ctx context.Context, s *session.Session) (*re
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get jobs scheduling: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get jobs scheduling: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -263,16 +287,17 @@ func (c *Client) GetShipments(ctx context.Context, s *session.Session, params *m
 		return nil, fmt.Errorf("user info is missing in session")
 	}
 
-	req := request.NewGetShipmentsRequest(c.client, token).
+	req := request.NewGetShipmentsRequest(c.client, c.endpoints, token).
 		SupplierID(s.UserInfo().UserDetails.SupplierID).
 		FromParams(params)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -282,7 +307,7 @@ func (c *Client) GetShipments(ctx context.Context, s *session.Session, params *m
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get shipments: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get shipments: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -294,15 +319,16 @@ func (c *Client) GetShipmentInfo(ctx context.Context, s *session.Session, shipme
 		return nil, err
 	}
 
-	req := request.NewGetShipmentInfoRequest(c.client, token).
+	req := request.NewGetShipmentInfoRequest(c.client, c.endpoints, token).
 		ShipmentID(shipmentID)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -312,7 +338,7 @@ func (c *Client) GetShipmentInfo(ctx context.Context, s *session.Session, shipme
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get shipments: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get shipments: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -324,15 +350,16 @@ func (c *Client) GetShipmentTransfers(ctx context.Context, s *session.Session, s
 		return nil, err
 	}
 
-	req := request.NewGetShipmentTransfersRequest(c.client, token).
+	req := request.NewGetShipmentTransfersRequest(c.client, c.endpoints, token).
 		ShipmentID(shipmentID)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -342,7 +369,7 @@ func (c *Client) GetShipmentTransfers(ctx context.Context, s *session.Session, s
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get shipments: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get shipments: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -354,7 +381,7 @@ func (c *Client) GetTaresForOffices(ctx context.Context, s *session.Session, off
 		return nil, err
 	}
 
-	req := request.NewGetTaresForOffices(c.client, token).
+	req := request.NewGetTaresForOffices(c.client, c.endpoints, token).
 		SourceOfficeID(officeID).
 		DestinationOfficeIDs(destinationOfficeIDs).
 		IsDrive(isDrive)
@@ -362,9 +389,10 @@ func (c *Client) GetTaresForOffices(ctx context.Context, s *session.Session, off
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -374,7 +402,7 @@ func (c *Client) GetTaresForOffices(ctx context.Context, s *session.Session, off
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get shipments: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get shipments: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -386,16 +414,17 @@ func (c *Client) GetAssociationOfficesInfoByName(ctx context.Context, s *session
 		return nil, err
 	}
 
-	req := request.NewGetAssociationOfficesInfoByNameRequest(c.client, token).
+	req := request.NewGetAssociationOfficesInfoByNameRequest(c.client, c.endpoints, token).
 		Param(param).
 		IsDc(isDc)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -405,7 +434,7 @@ func (c *Client) GetAssociationOfficesInfoByName(ctx context.Context, s *session
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get association offices info by name: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get association offices info by name: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -417,15 +446,16 @@ func (c *Client) GetAssociationRoutesInfoByName(ctx context.Context, s *session.
 		return nil, err
 	}
 
-	req := request.NewGetAssociationRoutesInfoByNameRequest(c.client, token).
+	req := request.NewGetAssociationRoutesInfoByNameRequest(c.client, c.endpoints, token).
 		Param(param)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -435,7 +465,7 @@ func (c *Client) GetAssociationRoutesInfoByName(ctx context.Context, s *session.
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get association routes info by name: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get association routes info by name: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -447,15 +477,16 @@ func (c *Client) GetWaySheets(ctx context.Context, s *session.Session, params *m
 		return nil, err
 	}
 
-	req := request.NewGetWaySheetsRequestRequest(c.client, token).
+	req := request.NewGetWaySheetsRequestRequest(c.client, c.endpoints, token).
 		FromParams(params)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -465,7 +496,7 @@ func (c *Client) GetWaySheets(ctx context.Context, s *session.Session, params *m
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get way sheets: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get way sheets: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
@@ -477,15 +508,16 @@ func (c *Client) GetWaySheetInfo(ctx context.Context, s *session.Session, wayShe
 		return nil, err
 	}
 
-	req := request.NewGetWaySheetInfoRequest(c.client, token).
+	req := request.NewGetWaySheetInfoRequest(c.client, c.endpoints, token).
 		WaySheetID(waySheetID)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -495,7 +527,7 @@ func (c *Client) GetWaySheetInfo(ctx context.Context, s *session.Session, wayShe
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get way sheet info: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get way sheet info: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 	if res.Data.WaySheet == nil {
 		return nil, fmt.Errorf("failed to get way sheet info, data way sheet info is empty")
@@ -510,15 +542,16 @@ func (c *Client) GetWaySheetFinanceDetails(ctx context.Context, s *session.Sessi
 		return nil, err
 	}
 
-	req := request.NewGetWaySheetFinanceDetailsRequest(c.client, token).
+	req := request.NewGetWaySheetFinanceDetailsRequest(c.client, c.endpoints, token).
 		WaySheetID(waySheetID)
 
 	res, err := req.Do(ctx)
 	if err != nil {
 		if req.IsUnauthorized() {
-			if err = c.RefreshSession(ctx, s); err != nil {
-				return nil, fmt.Errorf("failed refresh unauthorized session %w", err)
+			if refreshErr := c.RefreshSession(ctx, s); refreshErr != nil {
+				return nil, fmt.Errorf("failed refresh unauthorized session: %w: %w", refreshErr, err)
 			}
+			req.SetAccessToken(s.SessionTokenString())
 			res, err = req.Do(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed retry request after refresh session: %w", err)
@@ -528,7 +561,7 @@ func (c *Client) GetWaySheetFinanceDetails(ctx context.Context, s *session.Sessi
 		}
 	}
 	if res.Error != nil {
-		return nil, fmt.Errorf("failed to get shipments: %s", res.Error.Error())
+		return nil, fmt.Errorf("failed to get shipments: %w", errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return &res, nil
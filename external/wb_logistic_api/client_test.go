@@ -0,0 +1,87 @@
+package wb_logistic_api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"wb_logistic_assistant/external/wb_logistic_api/errors"
+	"wb_logistic_assistant/external/wb_logistic_api/fake"
+	"wb_logistic_assistant/external/wb_logistic_api/models"
+	"wb_logistic_assistant/external/wb_logistic_api/session"
+)
+
+// expireSessionToken The session token of the session is expired by time, the access token is still valid
+func expireSessionToken(s *session.Session) {
+	token := *s.SessionToken()
+	token.Token.ExpiresIn = time.Now().Add(-time.Minute).Unix()
+	s.SetSessionToken(&token)
+}
+
+func TestClientRefreshExpiredSessionToken(t *testing.T) {
+	ctx := context.Background()
+	server := fake.NewServer(nil)
+	defer server.Close()
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := server.NewSession(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expireSessionToken(s)
+	if _, err = client.GetRemainsLastMileReports(ctx, s); err != nil {
+		t.Fatalf("failed request with the refreshed session: %v", err)
+	}
+	if s.SessionTokenExpired() {
+		t.Fatal("session token is not refreshed")
+	}
+
+	// the refresh rejected by 401 is unauthorized, the data request is not sent
+	expireSessionToken(s)
+	server.FailNext(fake.RouteAuthMerge, http.StatusUnauthorized, 1)
+	_, err = client.GetRemainsLastMileReports(ctx, s)
+	if !errors.IsUnauthorized(err) {
+		t.Fatalf("rejected refresh is not unauthorized: %v", err)
+	}
+	if n := server.Requests(fake.RouteReports); n != 1 {
+		t.Fatalf("served %d report requests, want 1", n)
+	}
+
+	// the refresh without the response keeps its own kind, it may succeed later
+	server.Close()
+	_, err = client.GetRemainsLastMileReports(ctx, s)
+	if err == nil || errors.IsUnauthorized(err) || !errors.IsRetryable(err) {
+		t.Fatalf("refresh without the response is unauthorized or not retryable: %v", err)
+	}
+}
+
+func TestClientRefreshWithoutRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	server := fake.NewServer(nil)
+	defer server.Close()
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the expired access token can not be refreshed, no request is sent
+	s := session.NewSessionFromToken(
+		"79991112233",
+		&models.AuthAccessToken{AccessToken: "access", ExpiresIn: time.Now().Add(-time.Minute).Unix()},
+		&models.AuthSessionToken{Token: models.AuthSessionTokenData{
+			AccessToken: "session",
+			ExpiresIn:   time.Now().Add(-time.Minute).Unix(),
+		}},
+		&models.UserInfo{},
+	)
+	_, err = client.GetRemainsLastMileReports(ctx, s)
+	if err == nil || errors.IsUnauthorized(err) {
+		t.Fatalf("failed refresh is unauthorized: %v", err)
+	}
+	if n := server.Requests(fake.RouteAuthMerge) + server.Requests(fake.RouteReports); n != 0 {
+		t.Fatalf("server got %d requests, want 0", n)
+	}
+}
@@ -0,0 +1,138 @@
+package errors
+
+import (
+	std_errors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Kinds of the failed requests, RequestError unwraps to one of them
+var (
+	ErrUnauthorized = std_errors.New("WB logistic API: unauthorized")
+	ErrRateLimited  = std_errors.New("WB logistic API: rate limited")
+	ErrNotFound     = std_errors.New("WB logistic API: not found")
+	ErrServer       = std_errors.New("WB logistic API: server error")
+	ErrDecode       = std_errors.New("WB logistic API: decode error")
+)
+
+// RequestError Failed request of the WB logistic API. It unwraps to the kind and to the cause,
+// so it is checked by errors.Is with the Err* kinds and by errors.As with *APIError or *AuthAPIError
+type RequestError struct {
+	Endpoint  string // URL of the request without the query
+	Status    int    // HTTP status, 0 if there is no response
+	Code      string // code of APIError or AuthAPIError
+	Retryable bool   // the same request may succeed later
+	Kind      error  // one of the Err* kinds, nil if the failure is not classified
+	Err       error  // cause, transport error, APIError or AuthAPIError
+}
+
+func (e *RequestError) Error() string {
+	msg := "WB logistic request " + e.Endpoint
+	if e.Status != 0 {
+		msg += " status " + strconv.Itoa(e.Status)
+	}
+	if e.Code != "" {
+		msg += " code " + e.Code
+	}
+	switch {
+	case e.Err != nil:
+		msg += ": " + e.Err.Error()
+	case e.Kind != nil:
+		msg += ": " + e.Kind.Error()
+	}
+	return msg
+}
+
+func (e *RequestError) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// NewRequestError Classifies the failure by the HTTP status, status 0 is the network failure which is retryable
+func NewRequestError(rawURL string, status int, err error) *RequestError {
+	e := &RequestError{Endpoint: endpoint(rawURL), Status: status, Err: err}
+	e.Kind, e.Retryable = classifyStatus(status)
+	if status == 0 {
+		e.Retryable = true
+	}
+	return e
+}
+
+// NewDecodeError Response of the successful status is not JSON or does not match the model
+func NewDecodeError(rawURL string, status int, err error) *RequestError {
+	return &RequestError{Endpoint: endpoint(rawURL), Status: status, Kind: ErrDecode, Err: err}
+}
+
+// NewAPIRequestError Error returned in the response body, classified by the HTTP status or by the code
+// if the status is successful
+func NewAPIRequestError(rawURL string, status int, apiErr *APIError) *RequestError {
+	e := &RequestError{Endpoint: endpoint(rawURL), Status: status, Err: apiErr}
+	if apiErr != nil {
+		e.Code = apiErr.Code
+	}
+	e.Kind, e.Retryable = classifyStatus(status)
+	if e.Kind == nil && apiErr != nil {
+		if code, err := strconv.Atoi(apiErr.Code); err == nil {
+			e.Kind, e.Retryable = classifyStatus(code)
+		}
+	}
+	return e
+}
+
+// NewAuthRequestError Error of the auth API, the request limit of the auth code is ErrRateLimited
+func NewAuthRequestError(rawURL string, status int, authErr *AuthAPIError) *RequestError {
+	e := &RequestError{Endpoint: endpoint(rawURL), Status: status, Err: authErr}
+	e.Kind, e.Retryable = classifyStatus(status)
+	if authErr != nil {
+		e.Code = string(authErr.Code)
+		if authErr.Code == ErrorTypeAuthRequestLimit {
+			e.Kind, e.Retryable = ErrRateLimited, true
+		}
+	}
+	return e
+}
+
+// IsUnauthorized The session is rejected by the server and must be restored before the next request
+func IsUnauthorized(err error) bool {
+	return std_errors.Is(err, ErrUnauthorized)
+}
+
+// IsRetryable Errors without RequestError in the chain are not known to the client and are considered retryable
+func IsRetryable(err error) bool {
+	var reqErr *RequestError
+	if std_errors.As(err, &reqErr) {
+		return reqErr.Retryable
+	}
+	return true
+}
+
+func classifyStatus(status int) (kind error, retryable bool) {
+	switch {
+	case status == http.StatusUnauthorized:
+		return ErrUnauthorized, false
+	case status == http.StatusNotFound:
+		return ErrNotFound, false
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited, true
+	case status >= http.StatusInternalServerError:
+		return ErrServer, true
+	}
+	return nil, false
+}
+
+// endpoint Query is dropped since it may carry the personal data
+func endpoint(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path)
+}
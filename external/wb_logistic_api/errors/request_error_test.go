@@ -0,0 +1,101 @@
+package errors
+
+import (
+	std_errors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestClassifyStatus(t *testing.T) {
+	tests := []struct {
+		status    int
+		kind      error
+		retryable bool
+	}{
+		{0, nil, false},
+		{http.StatusOK, nil, false},
+		{http.StatusBadRequest, nil, false},
+		{http.StatusUnauthorized, ErrUnauthorized, false},
+		{http.StatusForbidden, nil, false},
+		{http.StatusNotFound, ErrNotFound, false},
+		{http.StatusTooManyRequests, ErrRateLimited, true},
+		{http.StatusInternalServerError, ErrServer, true},
+		{http.StatusBadGateway, ErrServer, true},
+		{http.StatusGatewayTimeout, ErrServer, true},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			kind, retryable := classifyStatus(tt.status)
+			if kind != tt.kind || retryable != tt.retryable {
+				t.Fatalf("classifyStatus(%d) = %v, %v, want %v, %v", tt.status, kind, retryable, tt.kind, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestNewAPIRequestError(t *testing.T) {
+	const rawURL = "https://logistic.wb.ru/shipments-service/api/v1/shipments?phone=79991112233"
+	tests := []struct {
+		name      string
+		status    int
+		apiErr    *APIError
+		kind      error
+		retryable bool
+		code      string
+	}{
+		{"status classifies", http.StatusNotFound, &APIError{Code: "500"}, ErrNotFound, false, "500"},
+		{"code classifies the successful status", http.StatusOK, &APIError{Code: "401"}, ErrUnauthorized, false, "401"},
+		{"code of server error", http.StatusOK, &APIError{Code: "503"}, ErrServer, true, "503"},
+		{"text code", http.StatusOK, &APIError{Code: "shipment_not_found"}, nil, false, "shipment_not_found"},
+		{"unknown status", http.StatusBadRequest, &APIError{Code: "400"}, nil, false, "400"},
+		{"nil api error", http.StatusTooManyRequests, nil, ErrRateLimited, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewAPIRequestError(rawURL, tt.status, tt.apiErr)
+			if e.Kind != tt.kind || e.Retryable != tt.retryable || e.Code != tt.code {
+				t.Fatalf("kind %v, retryable %v, code %q, want %v, %v, %q", e.Kind, e.Retryable, e.Code, tt.kind, tt.retryable, tt.code)
+			}
+			if e.Endpoint != "https://logistic.wb.ru/shipments-service/api/v1/shipments" {
+				t.Fatalf("endpoint %s keeps the query", e.Endpoint)
+			}
+			var apiErr *APIError
+			if tt.apiErr != nil && (!std_errors.As(e, &apiErr) || apiErr != tt.apiErr) {
+				t.Fatalf("APIError is not unwrapped from %v", e)
+			}
+		})
+	}
+}
+
+func TestNewAuthRequestError(t *testing.T) {
+	e := NewAuthRequestError("https://auth", http.StatusBadRequest, &AuthAPIError{Code: ErrorTypeAuthRequestLimit})
+	if !std_errors.Is(e, ErrRateLimited) || !e.Retryable {
+		t.Fatalf("request limit of the auth code is not retryable rate limit: %v", e)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cause := std_errors.New("connection reset")
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"unknown error", cause, true},
+		{"network failure", NewRequestError("https://wb", 0, cause), true},
+		{"wrapped rate limit", fmt.Errorf("failed get shipments: %w", NewRequestError("https://wb", http.StatusTooManyRequests, nil)), true},
+		{"wrapped unauthorized", fmt.Errorf("failed get shipments: %w", NewRequestError("https://wb", http.StatusUnauthorized, nil)), false},
+		{"not found", NewRequestError("https://wb", http.StatusNotFound, nil), false},
+		{"bad request", NewRequestError("https://wb", http.StatusBadRequest, cause), false},
+		{"decode", NewDecodeError("https://wb", http.StatusOK, cause), false},
+		{"joined", std_errors.Join(cause, NewDecodeError("https://wb", http.StatusOK, cause)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.retryable {
+				t.Fatalf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.retryable)
+			}
+		})
+	}
+}
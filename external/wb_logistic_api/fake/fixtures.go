@@ -0,0 +1,210 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+	"wb_logistic_assistant/external/wb_logistic_api/models"
+)
+
+// Fixtures Data served by the fake server. The maps are keyed by the id from the request path or query
+type Fixtures struct {
+	Login          string                                            `json:"login"`       // phone number which receives the code
+	Code           int                                               `json:"code"`        // the only accepted auth code
+	SessionTTL     int64                                             `json:"session_ttl"` // seconds, lifetime of the issued session tokens
+	User           *models.UserInfo                                  `json:"user"`
+	Reports        []*models.RemainsLastMileReport                   `json:"reports"`
+	RouteInfos     map[int][]*models.RemainsLastMileReportsRouteInfo `json:"route_infos"` // route car id -> info
+	JobsScheduling *models.JobsScheduling                            `json:"jobs_scheduling"`
+	Shipments      []*models.Shipment                                `json:"shipments"`
+	ShipmentInfos  map[int]*models.ShipmentInfo                      `json:"shipment_infos"`     // shipment id -> info
+	Transfers      map[int]*models.ShipmentTransfers                 `json:"shipment_transfers"` // shipment id -> transfers
+	Tares          []*models.TareForOffice                           `json:"tares"`
+	Offices        []*models.AssociationOfficeInfoByName             `json:"offices"`
+	Routes         []*models.AssociationRouteInfoByName              `json:"routes"`
+	WaySheets      []*models.WaySheet                                `json:"way_sheets"`
+	WaySheetInfos  map[int]*models.WaySheetInfo                      `json:"way_sheet_infos"` // way sheet id -> info
+	FinanceDetails map[int]*models.WaySheetFinanceDetails            `json:"finance_details"` // way sheet id -> details
+}
+
+// LoadFixtures Reads the fixtures from the JSON file, the missing auth fields are taken from DefaultFixtures
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed read fixtures %s: %w", path, err)
+	}
+	fixtures := &Fixtures{}
+	if err = json.Unmarshal(data, fixtures); err != nil {
+		return nil, fmt.Errorf("failed unmarshal fixtures %s: %w", path, err)
+	}
+	fixtures.setDefaults()
+	return fixtures, nil
+}
+
+// DefaultFixtures One office with one route, its open shipment, way sheet and finance details
+func DefaultFixtures() *Fixtures {
+	now := time.Now().UTC().Truncate(time.Second)
+	fixtures := &Fixtures{
+		Reports: []*models.RemainsLastMileReport{{
+			OfficeID:      100,
+			OfficeName:    "СЦ Тестовый",
+			CountBarcodes: 120,
+			CountTares:    4,
+			TotalVolumeMl: 250000,
+			Routes: []*models.Route{{
+				Name:               "Тестовый маршрут",
+				CarID:              200,
+				CarType:            "last-mile",
+				Distance:           42,
+				CountTares:         4,
+				CountShk:           120,
+				PlanCountDeparture: 1,
+				Parking:            []int{7},
+				Suppliers: []*models.RouteSupplierInfo{{
+					ID:             300,
+					Name:           "ИП Тестовый",
+					CountDeparture: 1,
+				}},
+				NormativeInLiters: 250,
+				VolumeMlByContent: 250000,
+			}},
+		}},
+		RouteInfos: map[int][]*models.RemainsLastMileReportsRouteInfo{
+			200: {{
+				CountBarcodes:         120,
+				CountTare:             4,
+				DestinationOfficeID:   101,
+				DestinationOfficeName: "ПВЗ Тестовый",
+				TotalVolumeMl:         250000,
+				Tares: []*models.RemainsLastMileReportsRouteInfoTare{{
+					ID:            900,
+					PrepareDate:   now.Add(-time.Hour),
+					CountBarcodes: 30,
+					SpName:        "ИП Тестовый",
+					VolumeMl:      62500,
+				}},
+			}},
+		},
+		JobsScheduling: &models.JobsScheduling{},
+		Shipments: []*models.Shipment{{
+			ShipmentID:         400,
+			CreateDt:           now.Add(-2 * time.Hour),
+			RouteCarId:         200,
+			SupplierId:         300,
+			SupplierName:       "ИП Тестовый",
+			SrcOfficeId:        100,
+			OfficeName:         "СЦ Тестовый",
+			DriverName:         "Иванов Иван",
+			DstOfficeName:      []string{"ПВЗ Тестовый"},
+			VehicleNumberPlate: "А001АА77",
+			IsLastMile:         true,
+		}},
+		ShipmentInfos: map[int]*models.ShipmentInfo{
+			400: {
+				ID:                 400,
+				CreateDt:           now.Add(-2 * time.Hour),
+				RouteID:            200,
+				WaySheetID:         500,
+				DriverName:         "Иванов Иван",
+				IsLastMile:         true,
+				SrcOfficeID:        100,
+				SrcOfficeName:      "СЦ Тестовый",
+				SupplierID:         300,
+				SupplierName:       "ИП Тестовый",
+				VehicleNumberPlate: "А001АА77",
+				DestinationOfficesInfo: []*models.ShipmentInfoDestinationOfficeInfo{{
+					ID:            600,
+					DstOfficeID:   101,
+					DstOfficeName: "ПВЗ Тестовый",
+				}},
+			},
+		},
+		Transfers: map[int]*models.ShipmentTransfers{
+			400: {
+				TransferBoxes: []*models.ShipmentTransferBox{{
+					CreateDt:      now.Add(-2 * time.Hour),
+					DstOfficeID:   101,
+					DstOfficeName: "ПВЗ Тестовый",
+					CountBarcodes: 120,
+					SrcOfficeName: "СЦ Тестовый",
+					TtnID:         600,
+					VolumeMl:      250000,
+				}},
+			},
+		},
+		Tares: []*models.TareForOffice{{
+			ID:              900,
+			Type:            "box",
+			CountBarcodes:   30,
+			DstOfficeID:     101,
+			DstOfficeName:   "ПВЗ Тестовый",
+			LastOperationDt: now.Add(-time.Hour),
+			SpName:          "ИП Тестовый",
+		}},
+		Offices: []*models.AssociationOfficeInfoByName{
+			{Id: 100, Name: "СЦ Тестовый", IsSc: true},
+			{Id: 101, Name: "ПВЗ Тестовый"},
+		},
+		Routes: []*models.AssociationRouteInfoByName{{
+			ID:            200,
+			SrcOfficeName: "СЦ Тестовый",
+			Distance:      42,
+			OfficeName:    "ПВЗ Тестовый",
+		}},
+		WaySheets: []*models.WaySheet{{
+			WaySheetID:         "500",
+			OpenDt:             now.Add(-2 * time.Hour),
+			SrcOfficeID:        "100",
+			RouteCarID:         "200",
+			RouteCarName:       "Тестовый маршрут",
+			DriverName:         "Иванов Иван",
+			VehicleNumberPlate: "А001АА77",
+			SupplierID:         "300",
+			SupplierName:       "ИП Тестовый",
+			CountBarcodes:      "120",
+			TotalPrice:         5000,
+			SrcOfficeName:      "СЦ Тестовый",
+		}},
+		WaySheetInfos: map[int]*models.WaySheetInfo{
+			500: {
+				ID:                 "500",
+				TotalBarcodesCount: 120,
+				DateOpen:           now.Add(-2 * time.Hour),
+				SrcOffice:          &models.WaySheetSourceOffice{ID: "100", Name: "СЦ Тестовый"},
+				DstOffices:         []*models.WaySheetDestinationOffice{{ID: "101", Name: "ПВЗ Тестовый"}},
+				Route:              &models.WaySheetRoute{RouteCarID: "200", RouteCarName: "Тестовый маршрут"},
+				Drivers:            []*models.WaySheetDriver{{DriverName: "Иванов Иван"}},
+				Vehicles:           &models.WaySheetVehicles{ShippingCarNumber: "А001АА77"},
+			},
+		},
+		FinanceDetails: map[int]*models.WaySheetFinanceDetails{
+			500: {Currency: "RUB", WaySheetID: "500"},
+		},
+	}
+	fixtures.setDefaults()
+	return fixtures
+}
+
+func (f *Fixtures) setDefaults() {
+	if f.Login == "" {
+		f.Login = "79991112233"
+	}
+	if f.Code == 0 {
+		f.Code = 123456
+	}
+	if f.SessionTTL <= 0 {
+		f.SessionTTL = 3600
+	}
+	if f.User == nil {
+		f.User = &models.UserInfo{
+			ID:       1000,
+			Verified: true,
+			UserDetails: &models.UserInfoDetails{
+				Name:        "Тестовый пользователь",
+				PhoneNumber: f.Login,
+				SupplierID:  300,
+			},
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package fake
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Routes of the fake server, the scenarios are scripted per route
+const (
+	RouteAny               = "*" // faults of any route
+	RouteAuthCode          = "auth_code"
+	RouteAuthExchange      = "auth_exchange"
+	RouteAuthMerge         = "auth_merge"
+	RouteUserInfo          = "user_info"
+	RouteReports           = "reports"
+	RouteReportInfo        = "report_info"
+	RouteJobsScheduling    = "jobs_scheduling"
+	RouteShipments         = "shipments"
+	RouteShipmentInfo      = "shipment_info"
+	RouteShipmentTransfers = "shipment_transfers"
+	RouteTares             = "tares"
+	RouteOffices           = "offices"
+	RouteRoutes            = "routes"
+	RouteWaySheets         = "way_sheets"
+	RouteWaySheetInfo      = "way_sheet_info"
+	RouteFinanceDetails    = "finance_details"
+)
+
+// fault Response which replaces the next requests of the route
+type fault struct {
+	status int
+	times  int
+}
+
+// FailNext Next 'times' requests of the route are answered by the status with the plain text body,
+// like the gateway of WB does
+func (s *Server) FailNext(route string, status, times int) {
+	if times <= 0 {
+		return
+	}
+	s.mtx.Lock()
+	s.faults[route] = append(s.faults[route], &fault{status: status, times: times})
+	s.mtx.Unlock()
+}
+
+// UnauthorizedNext Next request of the route is answered by 401, the client refreshes the session and retries
+func (s *Server) UnauthorizedNext(route string) {
+	s.FailNext(route, http.StatusUnauthorized, 1)
+}
+
+// ServerErrorBurst Next 'times' requests of the route are answered by 502
+func (s *Server) ServerErrorBurst(route string, times int) {
+	s.FailNext(route, http.StatusBadGateway, times)
+}
+
+// ExpireSessions Revokes the issued session tokens, all the next data requests are answered by 401 until the client
+// obtains the new session token
+func (s *Server) ExpireSessions() {
+	s.mtx.Lock()
+	s.sessionTokens = make(map[string]int64)
+	s.mtx.Unlock()
+}
+
+// SetCycleRoute Every request of the route starts the next cycle, e.g. RouteReports for GeneralRoutesReporter
+// or RouteShipments for ShipmentCloseReporter. Empty route disables it, the cycles are advanced by NextCycle
+func (s *Server) SetCycleRoute(route string) {
+	s.mtx.Lock()
+	s.cycleRoute = route
+	s.mtx.Unlock()
+}
+
+// NextCycle Starts the next cycle and applies its scheduled changes, returns the number of the cycle
+func (s *Server) NextCycle() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.nextCycle()
+	return s.cycle
+}
+
+// Cycle Number of the current cycle, 0 before the first one
+func (s *Server) Cycle() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.cycle
+}
+
+// CloseShipmentAfter Closes the shipment and its way sheet when the 'cycles'-th cycle from the current one starts
+func (s *Server) CloseShipmentAfter(shipmentID, cycles int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if cycles <= 0 {
+		s.closeShipment(shipmentID)
+		return
+	}
+	s.closes[shipmentID] = s.cycle + cycles
+}
+
+// Requests Number of the served requests of the route, including the failed ones
+func (s *Server) Requests(route string) int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.requests[route]
+}
+
+// nextCycle Must be called under the lock
+func (s *Server) nextCycle() {
+	s.cycle++
+	for shipmentID, cycle := range s.closes {
+		if cycle <= s.cycle {
+			s.closeShipment(shipmentID)
+			delete(s.closes, shipmentID)
+		}
+	}
+}
+
+// closeShipment Must be called under the lock
+func (s *Server) closeShipment(shipmentID int) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, shipment := range s.fixtures.Shipments {
+		if shipment.ShipmentID == shipmentID && shipment.CloseDt.IsZero() {
+			shipment.CloseDt = now
+		}
+	}
+	info, ok := s.fixtures.ShipmentInfos[shipmentID]
+	if !ok {
+		return
+	}
+	if info.CloseDt.IsZero() {
+		info.CloseDt = now
+	}
+	if waySheet, ok := s.fixtures.WaySheetInfos[info.WaySheetID]; ok && waySheet.DateClose.IsZero() {
+		waySheet.DateClose = now
+	}
+	for _, waySheet := range s.fixtures.WaySheets {
+		if waySheet.WaySheetID == strconv.Itoa(info.WaySheetID) && waySheet.CloseDt.IsZero() {
+			waySheet.CloseDt = now
+		}
+	}
+}
+
+// takeFault Must be called under the lock
+func (s *Server) takeFault(route string) *fault {
+	for _, key := range []string{route, RouteAny} {
+		faults := s.faults[key]
+		if len(faults) == 0 {
+			continue
+		}
+		f := faults[0]
+		f.times--
+		if f.times <= 0 {
+			s.faults[key] = faults[1:]
+		}
+		return f
+	}
+	return nil
+}
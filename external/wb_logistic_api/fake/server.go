@@ -0,0 +1,471 @@
+// Package fake Local WB logistic API for the integration tests: httptest server which implements the endpoints
+// used by wb_logistic_api.Client, serves the fixtures and plays the scripted scenarios
+package fake
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	wb_logistic_api "wb_logistic_assistant/external/wb_logistic_api"
+	"wb_logistic_assistant/external/wb_logistic_api/models"
+	"wb_logistic_assistant/external/wb_logistic_api/session"
+	"wb_logistic_assistant/external/wb_logistic_api/transport"
+)
+
+type Server struct {
+	server *httptest.Server
+
+	mtx           sync.Mutex
+	fixtures      *Fixtures // owned by the server, the scenarios change it
+	seq           int
+	stickers      map[string]bool
+	accessTokens  map[string]bool
+	sessionTokens map[string]int64 // token -> expiration, unix
+	faults        map[string][]*fault
+	requests      map[string]int
+	cycle         int
+	cycleRoute    string
+	closes        map[int]int // shipment id -> cycle of the close
+}
+
+// NewServer Starts the server, if fixtures is nil DefaultFixtures are served
+func NewServer(fixtures *Fixtures) *Server {
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+	fixtures.setDefaults()
+
+	s := &Server{
+		fixtures:      fixtures,
+		stickers:      make(map[string]bool),
+		accessTokens:  make(map[string]bool),
+		sessionTokens: make(map[string]int64),
+		faults:        make(map[string][]*fault),
+		requests:      make(map[string]int),
+		closes:        make(map[int]int),
+	}
+
+	mux := http.NewServeMux()
+	s.handle(mux, "POST /user-management/api/v1/public/registration/code", RouteAuthCode, false, s.handleAuthCode)
+	s.handle(mux, "POST /user-management/api/v1/public/registration/auth", RouteAuthExchange, false, s.handleAuthExchange)
+	s.handle(mux, "POST /user-management/api/v1/public/token/merge", RouteAuthMerge, false, s.handleAuthMerge)
+	s.handle(mux, "GET /api/v1/public/iam/accounts/{id}", RouteUserInfo, true, s.handleUserInfo)
+	s.handle(mux, "GET /reports-service/api/v1/last-mile", RouteReports, true, s.handleReports)
+	s.handle(mux, "GET /reports-service/api/v1/last-mile/{id}", RouteReportInfo, true, s.handleReportInfo)
+	s.handle(mux, "GET /transport-planning-service/api/v1/planning/last-mile", RouteJobsScheduling, true, s.handleJobsScheduling)
+	s.handle(mux, "GET /shipments-service/api/v1/shipments", RouteShipments, true, s.handleShipments)
+	s.handle(mux, "GET /shipments-service/api/v1/shipments/{id}/info", RouteShipmentInfo, true, s.handleShipmentInfo)
+	s.handle(mux, "GET /shipments-service/api/v1/shipments/{id}/transfers", RouteShipmentTransfers, true, s.handleShipmentTransfers)
+	s.handle(mux, "POST /tares/api/v2/public/tares/tares-for-offices", RouteTares, true, s.handleTares)
+	s.handle(mux, "GET /routes-netcore-service/api/v1/office", RouteOffices, true, s.handleOffices)
+	s.handle(mux, "GET /routes-netcore-service/api/v1/route/by-param/false/true/{param}", RouteRoutes, true, s.handleRoutes)
+	s.handle(mux, "POST /client-gateway/api/waysheets/v1/waysheets", RouteWaySheets, true, s.handleWaySheets)
+	s.handle(mux, "GET /client-gateway/api/waysheets/v1/waysheets/{id}", RouteWaySheetInfo, true, s.handleWaySheetInfo)
+	s.handle(mux, "GET /client-gateway/api/finance/credeber/v1/payment/details", RouteFinanceDetails, true, s.handleFinanceDetails)
+
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// URL Base URL of all the services
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// NewClient Client which requests all the services from the server
+func (s *Server) NewClient() (*wb_logistic_api.Client, error) {
+	client := wb_logistic_api.NewClient(transport.NewBaseHTTPClient())
+	if err := client.Endpoints().SetAll(s.URL()); err != nil {
+		return nil, fmt.Errorf("failed point client to fake server: %w", err)
+	}
+	return client, nil
+}
+
+// NewSession Passes the auth flow of the server by the client like the initializer does
+func (s *Server) NewSession(ctx context.Context, client *wb_logistic_api.Client) (*session.Session, error) {
+	login, code := s.Login()
+	authCode, err := client.RequestAuthCode(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed request auth code: %w", err)
+	}
+	accessToken, err := client.ExchangeAuthCode(ctx, code, authCode.Sticker)
+	if err != nil {
+		return nil, fmt.Errorf("failed exchange auth code: %w", err)
+	}
+	sessionToken, userInfo, err := client.GetSessionToken(ctx, login, accessToken.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed get session token: %w", err)
+	}
+	return session.NewSessionFromToken(login, accessToken, sessionToken, userInfo), nil
+}
+
+// Login Phone number and the auth code accepted by the server
+func (s *Server) Login() (string, int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.fixtures.Login, s.fixtures.Code
+}
+
+// handle Counts the request, plays the scripted faults and checks the session token of the data routes.
+// The handler is called under the lock
+func (s *Server) handle(mux *http.ServeMux, pattern, route string, isAuthRequired bool, handler func(w http.ResponseWriter, r *http.Request)) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+
+		s.requests[route]++
+		if s.cycleRoute == route {
+			s.nextCycle()
+		}
+		if f := s.takeFault(route); f != nil {
+			http.Error(w, http.StatusText(f.status), f.status)
+			return
+		}
+		if isAuthRequired && !s.isAuthorized(r) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// isAuthorized Must be called under the lock
+func (s *Server) isAuthorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	expiresIn, ok := s.sessionTokens[token]
+	return ok && time.Now().Unix() < expiresIn
+}
+
+//// Auth
+
+func (s *Server) handleAuthCode(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		PhoneNumber string `json:"phone_number"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PhoneNumber == "" {
+		writeAuthError(w, "um:1", "invalid request body")
+		return
+	}
+	if body.PhoneNumber != s.fixtures.Login {
+		writeAuthError(w, "um:1", "unknown phone number")
+		return
+	}
+
+	s.seq++
+	sticker := fmt.Sprintf("fake-sticker-%d", s.seq)
+	s.stickers[sticker] = true
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": &models.AuthCode{AuthMethod: "sms", Sticker: sticker, Ttl: 60},
+	})
+}
+
+func (s *Server) handleAuthExchange(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Code    int    `json:"code"`
+		Sticker string `json:"sticker"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeAuthError(w, "um:1", "invalid request body")
+		return
+	}
+	if !s.stickers[body.Sticker] {
+		writeAuthError(w, "um:wb_30", "code is expired")
+		return
+	}
+	if body.Code != s.fixtures.Code {
+		writeAuthError(w, "um:wb_6", "invalid code")
+		return
+	}
+	delete(s.stickers, body.Sticker)
+
+	s.seq++
+	accessToken := fmt.Sprintf("fake-access-%d", s.seq)
+	s.accessTokens[accessToken] = true
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": &models.AuthAccessToken{
+			TokenType:    "Bearer",
+			AccessToken:  accessToken,
+			RefreshToken: fmt.Sprintf("fake-refresh-%d", s.seq),
+			ExpiresIn:    time.Now().Add(24 * time.Hour).Unix(),
+		},
+	})
+}
+
+func (s *Server) handleAuthMerge(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Phone string `json:"phone"`
+		Token string `json:"authv3_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeAuthError(w, "um:1", "invalid request body")
+		return
+	}
+	if body.Phone != s.fixtures.Login || !s.accessTokens[body.Token] {
+		writeAuthError(w, "um:2", "failed merge token")
+		return
+	}
+
+	s.seq++
+	expiresIn := time.Now().Unix() + s.fixtures.SessionTTL
+	token := sessionJWT(&models.AuthSessionTokenJWTDecode{
+		Exp:          int(expiresIn),
+		ID:           s.fixtures.User.ID,
+		FreelancerID: s.fixtures.User.ID,
+		SessionID:    strconv.Itoa(s.seq),
+	})
+	s.sessionTokens[token] = expiresIn
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": &models.AuthSessionToken{
+			Source: "fake",
+			Token: models.AuthSessionTokenData{
+				TokenType:   "Bearer",
+				AccessToken: token,
+				ExpiresIn:   expiresIn,
+			},
+		},
+	})
+}
+
+func (s *Server) handleUserInfo(w http.ResponseWriter, r *http.Request) {
+	if pathID(r) != s.fixtures.User.ID {
+		writeNotFound(w, "account")
+		return
+	}
+	writeData(w, s.fixtures.User)
+}
+
+//// Reports
+
+func (s *Server) handleReports(w http.ResponseWriter, _ *http.Request) {
+	writeData(w, s.fixtures.Reports)
+}
+
+func (s *Server) handleReportInfo(w http.ResponseWriter, r *http.Request) {
+	info := s.fixtures.RouteInfos[pathID(r)]
+	if info == nil {
+		info = []*models.RemainsLastMileReportsRouteInfo{}
+	}
+	writeData(w, info)
+}
+
+func (s *Server) handleJobsScheduling(w http.ResponseWriter, _ *http.Request) {
+	writeData(w, s.fixtures.JobsScheduling)
+}
+
+//// Shipments
+
+// handleShipments Filters by the shipment, the office and the open state, the page is cut by page_index and limit
+func (s *Server) handleShipments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	shipmentID, _ := strconv.Atoi(query.Get("shipment_id"))
+	srcOfficeID, _ := strconv.Atoi(query.Get("src_office_id"))
+	isOnlyOpen := query.Get("show_only_open") == "true"
+
+	shipments := make([]*models.Shipment, 0, len(s.fixtures.Shipments))
+	for _, shipment := range s.fixtures.Shipments {
+		if shipmentID != 0 && shipment.ShipmentID != shipmentID {
+			continue
+		}
+		if srcOfficeID != 0 && shipment.SrcOfficeId != srcOfficeID {
+			continue
+		}
+		if isOnlyOpen && !shipment.CloseDt.IsZero() {
+			continue
+		}
+		shipments = append(shipments, shipment)
+	}
+	total := len(shipments)
+
+	pageIndex, _ := strconv.Atoi(query.Get("page_index"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	shipments = page(shipments, pageIndex*limit, limit)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"meta": &models.ShipmentsMeta{TotalCount: total},
+		"data": shipments,
+	})
+}
+
+func (s *Server) handleShipmentInfo(w http.ResponseWriter, r *http.Request) {
+	info, ok := s.fixtures.ShipmentInfos[pathID(r)]
+	if !ok {
+		writeNotFound(w, "shipment")
+		return
+	}
+	writeData(w, info)
+}
+
+func (s *Server) handleShipmentTransfers(w http.ResponseWriter, r *http.Request) {
+	transfers, ok := s.fixtures.Transfers[pathID(r)]
+	if !ok {
+		transfers = &models.ShipmentTransfers{}
+	}
+	writeData(w, transfers)
+}
+
+func (s *Server) handleTares(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		DstOfficeIDs []int `json:"dst_office_ids"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	tares := make([]*models.TareForOffice, 0, len(s.fixtures.Tares))
+	for _, tare := range s.fixtures.Tares {
+		if len(body.DstOfficeIDs) == 0 || containsInt(body.DstOfficeIDs, tare.DstOfficeID) {
+			tares = append(tares, tare)
+		}
+	}
+	writeData(w, tares)
+}
+
+//// Routes and offices, the list is returned without the envelope
+
+func (s *Server) handleOffices(w http.ResponseWriter, r *http.Request) {
+	param := strings.ToLower(r.URL.Query().Get("param"))
+	offices := make([]*models.AssociationOfficeInfoByName, 0, len(s.fixtures.Offices))
+	for _, office := range s.fixtures.Offices {
+		if strings.Contains(strings.ToLower(office.Name), param) || strconv.Itoa(office.Id) == param {
+			offices = append(offices, office)
+		}
+	}
+	writeJSON(w, http.StatusOK, offices)
+}
+
+func (s *Server) handleRoutes(w http.ResponseWriter, r *http.Request) {
+	param := strings.ToLower(r.PathValue("param"))
+	routes := make([]*models.AssociationRouteInfoByName, 0, len(s.fixtures.Routes))
+	for _, route := range s.fixtures.Routes {
+		if strconv.Itoa(route.ID) == param || strings.Contains(strings.ToLower(route.OfficeName), param) {
+			routes = append(routes, route)
+		}
+	}
+	writeJSON(w, http.StatusOK, routes)
+}
+
+//// Way sheets
+
+func (s *Server) handleWaySheets(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Limit       int `json:"limit"`
+		Offset      int `json:"offset"`
+		SrcOfficeID int `json:"src_office_id"`
+		RouteCarID  int `json:"routecar_id"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	waySheets := make([]*models.WaySheet, 0, len(s.fixtures.WaySheets))
+	for _, waySheet := range s.fixtures.WaySheets {
+		if body.SrcOfficeID != 0 && waySheet.SrcOfficeID != strconv.Itoa(body.SrcOfficeID) {
+			continue
+		}
+		if body.RouteCarID != 0 && waySheet.RouteCarID != strconv.Itoa(body.RouteCarID) {
+			continue
+		}
+		waySheets = append(waySheets, waySheet)
+	}
+	total := len(waySheets)
+	waySheets = page(waySheets, body.Offset, body.Limit)
+
+	pages, pageIndex := 1, 1
+	if body.Limit > 0 {
+		pages = (total + body.Limit - 1) / body.Limit
+		pageIndex = body.Offset/body.Limit + 1
+	}
+	writeData(w, &models.WaySheetsPage{
+		TotalWaySheets: total,
+		Page:           pageIndex,
+		Pages:          pages,
+		WaySheets:      waySheets,
+	})
+}
+
+func (s *Server) handleWaySheetInfo(w http.ResponseWriter, r *http.Request) {
+	info, ok := s.fixtures.WaySheetInfos[pathID(r)]
+	if !ok {
+		writeNotFound(w, "way sheet")
+		return
+	}
+	writeData(w, map[string]interface{}{"waysheet": info})
+}
+
+func (s *Server) handleFinanceDetails(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(r.URL.Query().Get("waysheet"))
+	details, ok := s.fixtures.FinanceDetails[id]
+	if !ok {
+		writeNotFound(w, "way sheet finance details")
+		return
+	}
+	writeData(w, details)
+}
+
+//// Utils
+
+// sessionJWT Unsigned token with the payload decoded by the client
+func sessionJWT(payload *models.AuthSessionTokenJWTDecode) string {
+	header, _ := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+	body, _ := json.Marshal(payload)
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body) + ".fake"
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data, "error": nil})
+}
+
+func writeAuthError(w http.ResponseWriter, code, message string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"error": map[string]string{"error": "request failed", "code": code, "message": message},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, entity string) {
+	writeJSON(w, http.StatusNotFound, map[string]interface{}{
+		"code":    http.StatusNotFound,
+		"error":   "not found",
+		"message": entity + " is not found",
+	})
+}
+
+func pathID(r *http.Request) int {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	return id
+}
+
+func page[T any](items []T, offset, limit int) []T {
+	if offset < 0 || offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	wb_logistic_api "wb_logistic_assistant/external/wb_logistic_api"
+	wb_errors "wb_logistic_assistant/external/wb_logistic_api/errors"
+	"wb_logistic_assistant/external/wb_logistic_api/session"
+)
+
+func newTestServer(t *testing.T) (*Server, *wb_logistic_api.Client, *session.Session) {
+	t.Helper()
+	s := NewServer(nil)
+	t.Cleanup(s.Close)
+	client, err := s.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := s.NewSession(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, client, sess
+}
+
+func TestClientReports(t *testing.T) {
+	s, client, sess := newTestServer(t)
+
+	res, err := client.GetRemainsLastMileReports(context.Background(), sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != len(DefaultFixtures().Reports) {
+		t.Fatalf("got %d reports, want %d", len(res.Data), len(DefaultFixtures().Reports))
+	}
+	if n := s.Requests(RouteReports); n != 1 {
+		t.Fatalf("served %d requests, want 1", n)
+	}
+}
+
+func TestClientUnauthorized(t *testing.T) {
+	s, client, sess := newTestServer(t)
+	ctx := context.Background()
+
+	// the rejected session token is replaced by the new one of the same access token and the request is retried
+	token := sess.SessionTokenString()
+	s.UnauthorizedNext(RouteReports)
+	if _, err := client.GetRemainsLastMileReports(ctx, sess); err != nil {
+		t.Fatalf("failed request after 401: %v", err)
+	}
+	if n := s.Requests(RouteReports); n != 2 {
+		t.Fatalf("served %d requests, want 2", n)
+	}
+	if sess.SessionTokenString() == token {
+		t.Fatal("session token is not refreshed after 401")
+	}
+
+	s.ExpireSessions()
+	if _, err := client.GetRemainsLastMileReports(ctx, sess); err != nil {
+		t.Fatalf("failed request after the sessions are revoked: %v", err)
+	}
+
+	// the refresh is rejected too, so the session must be restored by the new login
+	s.UnauthorizedNext(RouteReports)
+	s.UnauthorizedNext(RouteAuthMerge)
+	_, err := client.GetRemainsLastMileReports(ctx, sess)
+	if !wb_errors.IsUnauthorized(err) {
+		t.Fatalf("error is not unauthorized: %v", err)
+	}
+	if sess, err = s.NewSession(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetRemainsLastMileReports(ctx, sess); err != nil {
+		t.Fatalf("failed request after the new login: %v", err)
+	}
+}
+
+func TestClientRateLimited(t *testing.T) {
+	s, client, sess := newTestServer(t)
+	ctx := context.Background()
+
+	s.FailNext(RouteReports, http.StatusTooManyRequests, 1)
+	_, err := client.GetRemainsLastMileReports(ctx, sess)
+	if !errors.Is(err, wb_errors.ErrRateLimited) {
+		t.Fatalf("error is not rate limited: %v", err)
+	}
+	if !wb_errors.IsRetryable(err) || wb_errors.IsUnauthorized(err) {
+		t.Fatalf("rate limited error is not retryable or is unauthorized: %v", err)
+	}
+
+	if _, err = client.GetRemainsLastMileReports(ctx, sess); err != nil {
+		t.Fatalf("failed retry after 429: %v", err)
+	}
+	if n := s.Requests(RouteReports); n != 2 {
+		t.Fatalf("served %d requests, want 2", n)
+	}
+}
@@ -14,8 +14,8 @@ type AuthGetCodeRequest struct {
 	BaseRequest
 }
 
-func NewAuthGetCodeRequest(client transport.HTTPClient) *AuthGetCodeRequest {
-	req := &AuthGetCodeRequest{BaseRequest: *NewRequest(client, "https://drive.wb.ru/user-management/api/v1/public/registration/code")}
+func NewAuthGetCodeRequest(client transport.HTTPClient, endpoints *Endpoints) *AuthGetCodeRequest {
+	req := &AuthGetCodeRequest{BaseRequest: *NewRequest(client, endpoints.URL(ServiceAuth, "/user-management/api/v1/public/registration/code"))}
 	req.header.Set("X-App-Type", "web")
 	req.header.Set("X-Auth-Provider", "wb")
 	return req
@@ -24,7 +24,7 @@ func NewAuthGetCodeRequest(client transport.HTTPClient) *AuthGetCodeRequest {
 func (r *AuthGetCodeRequest) Do(ctx context.Context) (response response.AuthCodeResponse, err error) {
 	err = r.PostUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("AuthGetCodeRequest.Do: %s", err)
+		err = fmt.Errorf("AuthGetCodeRequest.Do: %w", err)
 	}
 	return
 }
@@ -47,8 +47,8 @@ type AuthRequest struct {
 	BaseRequest
 }
 
-func NewAuthRequest(client transport.HTTPClient) *AuthRequest {
-	req := &AuthRequest{BaseRequest: *NewRequest(client, "https://drive.wb.ru/user-management/api/v1/public/registration/auth")}
+func NewAuthRequest(client transport.HTTPClient, endpoints *Endpoints) *AuthRequest {
+	req := &AuthRequest{BaseRequest: *NewRequest(client, endpoints.URL(ServiceAuth, "/user-management/api/v1/public/registration/auth"))}
 	req.header.Set("X-App-Type", "web")
 	req.header.Set("X-Auth-Provider", "wb")
 	req.TokenType("employee")
@@ -58,7 +58,7 @@ func NewAuthRequest(client transport.HTTPClient) *AuthRequest {
 func (r *AuthRequest) Do(ctx context.Context) (response response.AuthResponse, err error) {
 	err = r.PostUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("AuthGetCodeRequest.Do: %s", err)
+		err = fmt.Errorf("AuthGetCodeRequest.Do: %w", err)
 	}
 	return
 }
@@ -85,15 +85,15 @@ type AuthMergeRequest struct {
 	BaseRequest
 }
 
-func NewAuthMergeRequest(client transport.HTTPClient) *AuthMergeRequest {
-	req := &AuthMergeRequest{BaseRequest: *NewRequest(client, "https://drive.wb.ru/user-management/api/v1/public/token/merge")}
+func NewAuthMergeRequest(client transport.HTTPClient, endpoints *Endpoints) *AuthMergeRequest {
+	req := &AuthMergeRequest{BaseRequest: *NewRequest(client, endpoints.URL(ServiceAuth, "/user-management/api/v1/public/token/merge"))}
 	return req
 }
 
 func (r *AuthMergeRequest) Do(ctx context.Context) (response response.AuthMergeResponse, err error) {
 	err = r.PostUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("AuthMergeRequest.Do: %s", err)
+		err = fmt.Errorf("AuthMergeRequest.Do: %w", err)
 	}
 	return
 }
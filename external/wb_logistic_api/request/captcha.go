@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"wb_logistic_assistant/external/wb_logistic_api/errors"
 	"wb_logistic_assistant/external/wb_logistic_api/models"
 	"wb_logistic_assistant/external/wb_logistic_api/response"
 	"wb_logistic_assistant/external/wb_logistic_api/transport"
@@ -18,21 +19,21 @@ type CaptchaGetTaskRequest struct {
 	infoJWTToken string
 }
 
-func NewCaptchaGetTaskRequest(client *transport.BaseHTTPClient) *CaptchaGetTaskRequest {
-	return &CaptchaGetTaskRequest{BaseRequest: *NewRequest(client, "https://pow.wildberries.ru/api/v1/short/get-task")}
+func NewCaptchaGetTaskRequest(client *transport.BaseHTTPClient, endpoints *Endpoints) *CaptchaGetTaskRequest {
+	return &CaptchaGetTaskRequest{BaseRequest: *NewRequest(client, endpoints.URL(ServiceCaptcha, "/api/v1/short/get-task"))}
 }
 
 func (r *CaptchaGetTaskRequest) Do(ctx context.Context) (response response.CaptchaGetTask, err error) {
 	res, err := r.PostData(ctx, bytes.NewBuffer([]byte(r.infoJWTToken)))
 	if err != nil {
-		err = fmt.Errorf("CaptchaGetTaskRequest.Do: %s", err)
+		err = fmt.Errorf("CaptchaGetTaskRequest.Do: %w", err)
 		return
 	}
 	defer res.Body.Close()
 
 	err = json.NewDecoder(res.Body).Decode(&response)
 	if err != nil {
-		err = fmt.Errorf("CaptchaGetTaskRequest.Do: error decoding JSON %s", err)
+		err = fmt.Errorf("CaptchaGetTaskRequest.Do: error decoding JSON %w", errors.NewDecodeError(r.URL(), res.StatusCode, err))
 	}
 
 	return
@@ -54,14 +55,14 @@ type CaptchaVerifyAnswerRequest struct {
 	BaseRequest
 }
 
-func NewCaptchaVerifyAnswerRequest(client *transport.BaseHTTPClient) *CaptchaVerifyAnswerRequest {
-	return &CaptchaVerifyAnswerRequest{BaseRequest: *NewRequest(client, "https://pow.wildberries.ru/api/v1/short/verify-answer")}
+func NewCaptchaVerifyAnswerRequest(client *transport.BaseHTTPClient, endpoints *Endpoints) *CaptchaVerifyAnswerRequest {
+	return &CaptchaVerifyAnswerRequest{BaseRequest: *NewRequest(client, endpoints.URL(ServiceCaptcha, "/api/v1/short/verify-answer"))}
 }
 
 func (r *CaptchaVerifyAnswerRequest) Do(ctx context.Context) (response response.CaptchaGetTask, err error) {
 	err = r.PostUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("CaptchaVerifyAnswerRequest.Do: %s", err)
+		err = fmt.Errorf("CaptchaVerifyAnswerRequest.Do: %w", err)
 	}
 	return
 }
@@ -0,0 +1,134 @@
+package request
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Services of the WB logistic API, the base URL is set per service and the request paths are appended to it
+const (
+	ServiceAuth      = "auth"      // user-management, login by the code
+	ServiceUsers     = "users"     // accounts of the users
+	ServiceShipments = "shipments" // shipments-service
+	ServiceWaySheets = "waysheets" // client-gateway waysheets
+	ServiceReports   = "reports"   // reports-service
+	ServiceFinance   = "finance"   // client-gateway finance
+	ServiceRoutes    = "routes"    // routes-netcore-service, routes and offices
+	ServicePlanning  = "planning"  // transport-planning-service
+	ServiceTares     = "tares"     // tares
+	ServiceCaptcha   = "captcha"   // proof of work
+)
+
+var defaultBaseURLs = map[string]string{
+	ServiceAuth:      "https://drive.wb.ru",
+	ServiceUsers:     "https://logistics.wb.ru",
+	ServiceShipments: "https://logistics.wb.ru",
+	ServiceWaySheets: "https://drive.wb.ru",
+	ServiceReports:   "https://logistics.wb.ru",
+	ServiceFinance:   "https://drive.wb.ru",
+	ServiceRoutes:    "https://logistics.wb.ru",
+	ServicePlanning:  "https://logistics.wb.ru",
+	ServiceTares:     "https://logistics.wb.ru",
+	ServiceCaptcha:   "https://pow.wildberries.ru",
+}
+
+// Path prefixes of the requests of the services, the services may share the host
+var servicePathPrefixes = map[string]string{
+	ServiceAuth:      "/user-management/",
+	ServiceUsers:     "/api/v1/public/iam/",
+	ServiceShipments: "/shipments-service/",
+	ServiceWaySheets: "/client-gateway/api/waysheets/",
+	ServiceReports:   "/reports-service/",
+	ServiceFinance:   "/client-gateway/api/finance/",
+	ServiceRoutes:    "/routes-netcore-service/",
+	ServicePlanning:  "/transport-planning-service/",
+	ServiceTares:     "/tares/",
+	ServiceCaptcha:   "/api/v1/short/",
+}
+
+// ServiceByPath Service of the request by its URL path, empty if unknown. The base URL prefix is not counted
+func ServiceByPath(path string) string {
+	service, length := "", 0
+	for name, prefix := range servicePathPrefixes {
+		if i := strings.Index(path, prefix); i >= 0 && len(prefix) > length {
+			service, length = name, len(prefix)
+		}
+	}
+	return service
+}
+
+// Endpoints Registry of the base URLs of the services, e.g. to point the client at the staging host or the mock server.
+// The nil registry resolves the production URLs
+type Endpoints struct {
+	mtx  sync.RWMutex
+	urls map[string]string // service -> base URL without the trailing slash
+}
+
+func NewEndpoints() *Endpoints {
+	urls := make(map[string]string, len(defaultBaseURLs))
+	for service, baseURL := range defaultBaseURLs {
+		urls[service] = baseURL
+	}
+	return &Endpoints{urls: urls}
+}
+
+// Services Names of the known services
+func Services() []string {
+	services := make([]string, 0, len(defaultBaseURLs))
+	for service := range defaultBaseURLs {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+func IsService(service string) bool {
+	_, ok := defaultBaseURLs[service]
+	return ok
+}
+
+// Set Base URL of the service in the 'scheme://host[:port][/prefix]' format
+func (e *Endpoints) Set(service, baseURL string) error {
+	if _, ok := defaultBaseURLs[service]; !ok {
+		return fmt.Errorf("unknown service '%s', supported: %s", service, strings.Join(Services(), ", "))
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL '%s' of service '%s': %w", baseURL, service, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid base URL '%s' of service '%s', it must be 'http(s)://host'", baseURL, service)
+	}
+
+	e.mtx.Lock()
+	e.urls[service] = strings.TrimSuffix(baseURL, "/")
+	e.mtx.Unlock()
+	return nil
+}
+
+// SetAll Sets the same base URL to all services, e.g. the mock server
+func (e *Endpoints) SetAll(baseURL string) error {
+	for service := range defaultBaseURLs {
+		if err := e.Set(service, baseURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Endpoints) BaseURL(service string) string {
+	if e == nil {
+		return defaultBaseURLs[service]
+	}
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	return e.urls[service]
+}
+
+// URL Base URL of the service joined with the path which starts with '/'
+func (e *Endpoints) URL(service, path string) string {
+	return e.BaseURL(service) + path
+}
@@ -15,15 +15,15 @@ type GetWaySheetFinanceDetailsRequest struct {
 	BaseRequest
 }
 
-func NewGetWaySheetFinanceDetailsRequest(client transport.HTTPClient, token string) *GetWaySheetFinanceDetailsRequest {
-	r := &GetWaySheetFinanceDetailsRequest{BaseRequest: *NewRequestToken(client, "https://drive.wb.ru/client-gateway/api/finance/credeber/v1/payment/details", token)}
+func NewGetWaySheetFinanceDetailsRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetWaySheetFinanceDetailsRequest {
+	r := &GetWaySheetFinanceDetailsRequest{BaseRequest: *NewRequestToken(client, endpoints.URL(ServiceFinance, "/client-gateway/api/finance/credeber/v1/payment/details"), token)}
 	return r
 }
 
 func (r *GetWaySheetFinanceDetailsRequest) Do(ctx context.Context) (response response.GetWaySheetFinanceDetailsResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetWaySheetFinanceDetailsRequest.Do: %s", err)
+		err = fmt.Errorf("GetWaySheetFinanceDetailsRequest.Do: %w", err)
 	}
 	return
 }
@@ -15,15 +15,15 @@ type GetJobSchedulingRequest struct {
 	BaseRequest
 }
 
-func NewGetJobsSchedulingRequest(client transport.HTTPClient, token string) *GetJobSchedulingRequest {
-	r := &GetJobSchedulingRequest{BaseRequest: *NewRequestToken(client, "https://logistics.wb.ru/transport-planning-service/api/v1/planning/last-mile", token)}
+func NewGetJobsSchedulingRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetJobSchedulingRequest {
+	r := &GetJobSchedulingRequest{BaseRequest: *NewRequestToken(client, endpoints.URL(ServicePlanning, "/transport-planning-service/api/v1/planning/last-mile"), token)}
 	return r
 }
 
 func (r *GetJobSchedulingRequest) Do(ctx context.Context) (response response.GetJobsSchedulingResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetJobSchedulingRequest.Do: %s", err)
+		err = fmt.Errorf("GetJobSchedulingRequest.Do: %w", err)
 	}
 	return
 }
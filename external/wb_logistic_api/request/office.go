@@ -14,8 +14,8 @@ type GetAssociationOfficesInfoByNameRequest struct {
 	BaseRequest
 }
 
-func NewGetAssociationOfficesInfoByNameRequest(client transport.HTTPClient, token string) *GetAssociationOfficesInfoByNameRequest {
-	r := &GetAssociationOfficesInfoByNameRequest{BaseRequest: *NewRequestToken(client, "https://logistics.wb.ru/routes-netcore-service/api/v1/office", token)}
+func NewGetAssociationOfficesInfoByNameRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetAssociationOfficesInfoByNameRequest {
+	r := &GetAssociationOfficesInfoByNameRequest{BaseRequest: *NewRequestToken(client, endpoints.URL(ServiceRoutes, "/routes-netcore-service/api/v1/office"), token)}
 	r.IsDc(true)
 	return r
 }
@@ -23,7 +23,7 @@ func NewGetAssociationOfficesInfoByNameRequest(client transport.HTTPClient, toke
 func (r *GetAssociationOfficesInfoByNameRequest) Do(ctx context.Context) (response response.GetAssociationOfficesInfoByNameResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetAssociationOfficesInfoByNameRequest.Do: %s", err)
+		err = fmt.Errorf("GetAssociationOfficesInfoByNameRequest.Do: %w", err)
 	}
 	return
 }
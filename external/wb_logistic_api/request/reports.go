@@ -14,14 +14,14 @@ type GetRemainsLastMileReportsRequest struct {
 	BaseRequest
 }
 
-func NewGetRemainsLastMileReportsRequest(client transport.HTTPClient, token string) *GetRemainsLastMileReportsRequest {
-	return &GetRemainsLastMileReportsRequest{BaseRequest: *NewRequestToken(client, "https://logistics.wb.ru/reports-service/api/v1/last-mile", token)}
+func NewGetRemainsLastMileReportsRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetRemainsLastMileReportsRequest {
+	return &GetRemainsLastMileReportsRequest{BaseRequest: *NewRequestToken(client, endpoints.URL(ServiceReports, "/reports-service/api/v1/last-mile"), token)}
 }
 
 func (r *GetRemainsLastMileReportsRequest) Do(ctx context.Context) (response response.GetRemainsLastMileReportsResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetRemainsLastMileReportsRequest.Do: %s", err)
+		err = fmt.Errorf("GetRemainsLastMileReportsRequest.Do: %w", err)
 	}
 	return
 }
@@ -34,8 +34,8 @@ type GetRemainsLastMileReportInfoRequest struct {
 	originURL string
 }
 
-func NewGetRemainsLastMileReportsInfoRequest(client transport.HTTPClient, token string) *GetRemainsLastMileReportInfoRequest {
-	url := "https://logistics.wb.ru/reports-service/api/v1/last-mile/"
+func NewGetRemainsLastMileReportsInfoRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetRemainsLastMileReportInfoRequest {
+	url := endpoints.URL(ServiceReports, "/reports-service/api/v1/last-mile/")
 	r := &GetRemainsLastMileReportInfoRequest{
 		originURL:   url,
 		BaseRequest: *NewRequestToken(client, url, token),
@@ -46,7 +46,7 @@ func NewGetRemainsLastMileReportsInfoRequest(client transport.HTTPClient, token
 func (r *GetRemainsLastMileReportInfoRequest) Do(ctx context.Context) (response response.GetRemainsLastMileReportsRouteInfoResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetRemainsLastMileReportInfoRequest.Do: %s", err)
+		err = fmt.Errorf("GetRemainsLastMileReportInfoRequest.Do: %w", err)
 	}
 	return
 }
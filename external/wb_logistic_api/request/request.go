@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"wb_logistic_assistant/external/wb_logistic_api/errors"
 	"wb_logistic_assistant/external/wb_logistic_api/transport"
 )
 
@@ -34,6 +35,7 @@ type APIRequest[T any] interface {
 	SetContentType(t string)
 	SetAcceptEncoding(a string)
 	IsUnauthorized() bool
+	StatusCode() int
 	ClearParameters()
 }
 
@@ -45,6 +47,7 @@ type BaseRequest struct {
 	queryParameters *Parameters
 	parameters      *Parameters
 	isUnauthorized  bool
+	statusCode      int // HTTP status of the last response, 0 if there is no response
 }
 
 func NewRequest(client transport.HTTPClient, url string) *BaseRequest {
@@ -76,11 +79,9 @@ func (r *BaseRequest) Get(ctx context.Context) (*http.Response, error) {
 
 	httpResponse, err := r.client.Get(ctx, u, r.header)
 	if err != nil {
-		if httpResponse != nil && httpResponse.StatusCode == http.StatusUnauthorized {
-			r.isUnauthorized = true
-		}
-		return nil, fmt.Errorf("BaseRequest.Get(): Error GET request %s: %s", u, err)
+		return nil, fmt.Errorf("BaseRequest.Get(): Error GET request %s: %w", u, r.requestError(ctx, u, httpResponse, err))
 	}
+	r.statusCode = httpResponse.StatusCode
 
 	return httpResponse, nil
 }
@@ -92,12 +93,10 @@ func (r *BaseRequest) GetUnmarshal(ctx context.Context, target interface{}) erro
 	u := r.buildFullURL()
 
 	httpResponse, err := r.client.GetDecodeJSON(ctx, u, target, r.header)
-	if err != nil {
-		if httpResponse != nil && httpResponse.StatusCode == http.StatusUnauthorized {
-			r.isUnauthorized = true
-		}
-		return fmt.Errorf("BaseRequest.GetUnmarshal(): Error GET and unmarshal JSON request %s: %s", u, err)
+	if err != nil || isFailedStatus(httpResponse) {
+		return fmt.Errorf("BaseRequest.GetUnmarshal(): Error GET and unmarshal JSON request %s: %w", u, r.requestError(ctx, u, httpResponse, err))
 	}
+	r.statusCode = httpResponse.StatusCode
 	return nil
 }
 
@@ -109,16 +108,14 @@ func (r *BaseRequest) Post(ctx context.Context) (*http.Response, error) {
 
 	body, err := r.parameters.BuildJSON()
 	if err != nil {
-		return nil, fmt.Errorf("BaseRequest.Post(): Error building body request %s: %s", u, err)
+		return nil, fmt.Errorf("BaseRequest.Post(): Error building body request %s: %w", u, err)
 	}
 
 	httpResponse, err := r.client.Post(ctx, u, bytes.NewBuffer(body), r.header)
 	if err != nil {
-		if httpResponse != nil && httpResponse.StatusCode == http.StatusUnauthorized {
-			r.isUnauthorized = true
-		}
-		return nil, fmt.Errorf("BaseRequest.Post(): Error POST request %s: %s", u, err)
+		return nil, fmt.Errorf("BaseRequest.Post(): Error POST request %s: %w", u, r.requestError(ctx, u, httpResponse, err))
 	}
+	r.statusCode = httpResponse.StatusCode
 
 	return httpResponse, nil
 }
@@ -131,7 +128,7 @@ func (r *BaseRequest) PostUnmarshal(ctx context.Context, target interface{}) err
 
 	body, err := r.parameters.BuildJSON()
 	if err != nil {
-		return fmt.Errorf("BaseRequest.PostUnmarshal(): Error building body request %s: %s", u, err)
+		return fmt.Errorf("BaseRequest.PostUnmarshal(): Error building body request %s: %w", u, err)
 	}
 
 	httpResponse, err := r.client.PostDecodeJSON(
@@ -141,12 +138,10 @@ func (r *BaseRequest) PostUnmarshal(ctx context.Context, target interface{}) err
 		target,
 		r.header,
 	)
-	if err != nil {
-		if httpResponse != nil && httpResponse.StatusCode == http.StatusUnauthorized {
-			r.isUnauthorized = true
-		}
-		return fmt.Errorf("BaseRequest.PostUnmarshal(): Error POST and unmarshal JSON request %s: %s", u, err)
+	if err != nil || isFailedStatus(httpResponse) {
+		return fmt.Errorf("BaseRequest.PostUnmarshal(): Error POST and unmarshal JSON request %s: %w", u, r.requestError(ctx, u, httpResponse, err))
 	}
+	r.statusCode = httpResponse.StatusCode
 	return nil
 }
 
@@ -158,15 +153,45 @@ func (r *BaseRequest) PostData(ctx context.Context, data io.Reader) (*http.Respo
 
 	httpResponse, err := r.client.Post(ctx, u, data, r.header)
 	if err != nil {
-		if httpResponse != nil && httpResponse.StatusCode == http.StatusUnauthorized {
-			r.isUnauthorized = true
-		}
-		return nil, fmt.Errorf("BaseRequest.PostData(): Error POST request %s: %s", u, err)
+		return nil, fmt.Errorf("BaseRequest.PostData(): Error POST request %s: %w", u, r.requestError(ctx, u, httpResponse, err))
 	}
+	r.statusCode = httpResponse.StatusCode
 
 	return httpResponse, nil
 }
 
+// requestError Classifies the failed request, the cancelled one is not retryable
+func (r *BaseRequest) requestError(ctx context.Context, u string, httpResponse *http.Response, err error) error {
+	r.statusCode = 0
+	if httpResponse != nil {
+		r.statusCode = httpResponse.StatusCode
+	}
+	if r.statusCode == http.StatusUnauthorized {
+		r.isUnauthorized = true
+	}
+
+	var reqErr *errors.RequestError
+	if err != nil && r.statusCode != 0 && r.statusCode < http.StatusBadRequest {
+		reqErr = errors.NewDecodeError(u, r.statusCode, err)
+	} else {
+		reqErr = errors.NewRequestError(u, r.statusCode, err)
+	}
+	if ctx.Err() != nil {
+		reqErr.Retryable = false
+	}
+	return reqErr
+}
+
+// isFailedStatus Statuses which are failures even if the body is JSON, the other error statuses
+// carry APIError in the body
+func isFailedStatus(httpResponse *http.Response) bool {
+	if httpResponse == nil {
+		return false
+	}
+	status := httpResponse.StatusCode
+	return status == http.StatusUnauthorized || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
 func (r *BaseRequest) buildFullURL() string {
 	u := r.url
 	queryString := r.queryParameters.BuildURLValuesEncode()
@@ -298,6 +323,13 @@ func (r *BaseRequest) IsUnauthorized() bool {
 	return r.isUnauthorized
 }
 
+// StatusCode HTTP status of the last response, 0 if the request is not sent or there is no response
+func (r *BaseRequest) StatusCode() int {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.statusCode
+}
+
 func (r *BaseRequest) ClearParameters() {
 	r.mtx.Lock()
 	r.queryParameters.Clear()
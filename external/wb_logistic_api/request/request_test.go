@@ -0,0 +1,92 @@
+package request
+
+import (
+	"context"
+	std_errors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wb_logistic_assistant/external/wb_logistic_api/errors"
+	"wb_logistic_assistant/external/wb_logistic_api/transport"
+)
+
+func TestBaseRequestGetUnmarshalErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		case "/not_json":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html></html>`))
+		case "/bad_json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		case "/unauthorized":
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		case "/rate_limited":
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		case "/bad_gateway":
+			http.Error(w, "Bad Gateway", http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	client := transport.NewBaseHTTPClient()
+
+	tests := []struct {
+		path           string
+		kind           error // nil if there is no error
+		retryable      bool
+		status         int
+		isUnauthorized bool
+	}{
+		{"/ok", nil, false, http.StatusOK, false},
+		{"/not_json", errors.ErrDecode, false, http.StatusOK, false},
+		{"/bad_json", errors.ErrDecode, false, http.StatusOK, false},
+		{"/unauthorized", errors.ErrUnauthorized, false, http.StatusUnauthorized, true},
+		{"/rate_limited", errors.ErrRateLimited, true, http.StatusTooManyRequests, false},
+		{"/bad_gateway", errors.ErrServer, true, http.StatusBadGateway, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := NewRequest(client, server.URL+tt.path)
+			var target struct {
+				Data []int `json:"data"`
+			}
+			err := req.GetUnmarshal(context.Background(), &target)
+			if tt.kind == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else if !std_errors.Is(err, tt.kind) || errors.IsRetryable(err) != tt.retryable {
+				t.Fatalf("error %v, want kind %v, retryable %v", err, tt.kind, tt.retryable)
+			}
+			if req.StatusCode() != tt.status || req.IsUnauthorized() != tt.isUnauthorized {
+				t.Fatalf("status %d, unauthorized %v, want %d, %v", req.StatusCode(), req.IsUnauthorized(), tt.status, tt.isUnauthorized)
+			}
+		})
+	}
+}
+
+func TestBaseRequestNetworkErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+	client := transport.NewBaseHTTPClient()
+
+	// the network failure is retryable
+	req := NewRequest(client, url)
+	_, err := req.Get(context.Background())
+	var reqErr *errors.RequestError
+	if !std_errors.As(err, &reqErr) || reqErr.Status != 0 || !reqErr.Retryable {
+		t.Fatalf("network failure is not retryable request error: %v", err)
+	}
+
+	// but not after the context is done, the caller has gone
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewRequest(client, url).Get(ctx)
+	if err == nil || errors.IsRetryable(err) {
+		t.Fatalf("cancelled request is retryable: %v", err)
+	}
+}
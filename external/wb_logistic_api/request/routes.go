@@ -15,8 +15,8 @@ type GetAssociationRoutesInfoByNameRequest struct {
 	BaseRequest
 }
 
-func NewGetAssociationRoutesInfoByNameRequest(client transport.HTTPClient, token string) *GetAssociationRoutesInfoByNameRequest {
-	originURL := "https://logistics.wb.ru/routes-netcore-service/api/v1/route/by-param/false/true/"
+func NewGetAssociationRoutesInfoByNameRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetAssociationRoutesInfoByNameRequest {
+	originURL := endpoints.URL(ServiceRoutes, "/routes-netcore-service/api/v1/route/by-param/false/true/")
 	r := &GetAssociationRoutesInfoByNameRequest{BaseRequest: *NewRequestToken(client, originURL, token)}
 	r.originURL = originURL
 	return r
@@ -25,7 +25,7 @@ func NewGetAssociationRoutesInfoByNameRequest(client transport.HTTPClient, token
 func (r *GetAssociationRoutesInfoByNameRequest) Do(ctx context.Context) (response response.GetAssociationRoutesInfoByNameResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetAssociationRoutesInfoByNameRequest.Do: %s", err)
+		err = fmt.Errorf("GetAssociationRoutesInfoByNameRequest.Do: %w", err)
 	}
 	return
 }
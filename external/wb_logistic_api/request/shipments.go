@@ -16,8 +16,8 @@ type GetShipmentsRequest struct {
 	BaseRequest
 }
 
-func NewGetShipmentsRequest(client transport.HTTPClient, token string) *GetShipmentsRequest {
-	r := &GetShipmentsRequest{BaseRequest: *NewRequestToken(client, "https://logistics.wb.ru/shipments-service/api/v1/shipments", token)}
+func NewGetShipmentsRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetShipmentsRequest {
+	r := &GetShipmentsRequest{BaseRequest: *NewRequestToken(client, endpoints.URL(ServiceShipments, "/shipments-service/api/v1/shipments"), token)}
 	r.queryParameters.Set("dt_start", time.Now().Format("2006-01-02"))
 	r.queryParameters.Set("dt_end", time.Now().Format("2006-01-02"))
 	r.queryParameters.Set("page_index", 0)
@@ -30,7 +30,7 @@ func NewGetShipmentsRequest(client transport.HTTPClient, token string) *GetShipm
 func (r *GetShipmentsRequest) Do(ctx context.Context) (response response.GetShipmentsResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetShipmentsRequest.Do: %s", err)
+		err = fmt.Errorf("GetShipmentsRequest.Do: %w", err)
 	}
 	return
 }
@@ -170,8 +170,8 @@ type GetShipmentInfoRequest struct {
 	BaseRequest
 }
 
-func NewGetShipmentInfoRequest(client transport.HTTPClient, token string) *GetShipmentInfoRequest {
-	originURL := "https://logistics.wb.ru/shipments-service/api/v1/shipments/"
+func NewGetShipmentInfoRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetShipmentInfoRequest {
+	originURL := endpoints.URL(ServiceShipments, "/shipments-service/api/v1/shipments/")
 	r := &GetShipmentInfoRequest{BaseRequest: *NewRequestToken(client, originURL, token)}
 	r.originURL = originURL
 	return r
@@ -180,7 +180,7 @@ func NewGetShipmentInfoRequest(client transport.HTTPClient, token string) *GetSh
 func (r *GetShipmentInfoRequest) Do(ctx context.Context) (response response.GetShipmentInfoResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetShipmentInfoRequest.Do: %s", err)
+		err = fmt.Errorf("GetShipmentInfoRequest.Do: %w", err)
 	}
 	return
 }
@@ -199,8 +199,8 @@ type GetShipmentTransfersRequest struct {
 	BaseRequest
 }
 
-func NewGetShipmentTransfersRequest(client transport.HTTPClient, token string) *GetShipmentTransfersRequest {
-	originURL := "https://logistics.wb.ru/shipments-service/api/v1/shipments/"
+func NewGetShipmentTransfersRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetShipmentTransfersRequest {
+	originURL := endpoints.URL(ServiceShipments, "/shipments-service/api/v1/shipments/")
 	r := &GetShipmentTransfersRequest{BaseRequest: *NewRequestToken(client, originURL, token)}
 	r.originURL = originURL
 	return r
@@ -209,7 +209,7 @@ func NewGetShipmentTransfersRequest(client transport.HTTPClient, token string) *
 func (r *GetShipmentTransfersRequest) Do(ctx context.Context) (response response.GetShipmentTransfersResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetShipmentTransfersRequest.Do: %s", err)
+		err = fmt.Errorf("GetShipmentTransfersRequest.Do: %w", err)
 	}
 	return
 }
@@ -14,8 +14,8 @@ type GetTaresForOffices struct {
 	BaseRequest
 }
 
-func NewGetTaresForOffices(client transport.HTTPClient, token string) *GetTaresForOffices {
-	r := &GetTaresForOffices{BaseRequest: *NewRequestToken(client, "https://logistics.wb.ru/tares/api/v2/public/tares/tares-for-offices", token)}
+func NewGetTaresForOffices(client transport.HTTPClient, endpoints *Endpoints, token string) *GetTaresForOffices {
+	r := &GetTaresForOffices{BaseRequest: *NewRequestToken(client, endpoints.URL(ServiceTares, "/tares/api/v2/public/tares/tares-for-offices"), token)}
 	r.parameters.Set("is_drive", false)
 	return r
 }
@@ -23,7 +23,7 @@ func NewGetTaresForOffices(client transport.HTTPClient, token string) *GetTaresF
 func (r *GetTaresForOffices) Do(ctx context.Context) (response response.GetTaresForOfficesResponse, err error) {
 	err = r.PostUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetTaresForOffices.Do: %s", err)
+		err = fmt.Errorf("GetTaresForOffices.Do: %w", err)
 	}
 	return
 }
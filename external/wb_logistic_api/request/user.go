@@ -15,8 +15,8 @@ type UserGetInfoRequest struct {
 	BaseRequest
 }
 
-func NewUserGetInfoRequest(client transport.HTTPClient, token string) *UserGetInfoRequest {
-	url := "https://logistics.wb.ru/api/v1/public/iam/accounts/"
+func NewUserGetInfoRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *UserGetInfoRequest {
+	url := endpoints.URL(ServiceUsers, "/api/v1/public/iam/accounts/")
 	return &UserGetInfoRequest{
 		originURL:   url,
 		BaseRequest: *NewRequestToken(client, url, token),
@@ -26,7 +26,7 @@ func NewUserGetInfoRequest(client transport.HTTPClient, token string) *UserGetIn
 func (r *UserGetInfoRequest) Do(ctx context.Context) (response response.UserGetInfoResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("UserGetInfoRequest.Do: %s", err)
+		err = fmt.Errorf("UserGetInfoRequest.Do: %w", err)
 	}
 	return
 }
@@ -16,8 +16,8 @@ type GetWaySheetsRequest struct {
 	BaseRequest
 }
 
-func NewGetWaySheetsRequestRequest(client transport.HTTPClient, token string) *GetWaySheetsRequest {
-	r := &GetWaySheetsRequest{BaseRequest: *NewRequestToken(client, "https://drive.wb.ru/client-gateway/api/waysheets/v1/waysheets", token)}
+func NewGetWaySheetsRequestRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetWaySheetsRequest {
+	r := &GetWaySheetsRequest{BaseRequest: *NewRequestToken(client, endpoints.URL(ServiceWaySheets, "/client-gateway/api/waysheets/v1/waysheets"), token)}
 	r.Limit(10)
 	r.Offset(0)
 	r.WayTypeID(0)
@@ -27,7 +27,7 @@ func NewGetWaySheetsRequestRequest(client transport.HTTPClient, token string) *G
 func (r *GetWaySheetsRequest) Do(ctx context.Context) (response response.GetWaySheetsResponse, err error) {
 	err = r.PostUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetWaySheetsRequest.Do: %s", err)
+		err = fmt.Errorf("GetWaySheetsRequest.Do: %w", err)
 	}
 	return
 }
@@ -127,8 +127,8 @@ type GetWaySheetInfoRequest struct {
 	BaseRequest
 }
 
-func NewGetWaySheetInfoRequest(client transport.HTTPClient, token string) *GetWaySheetInfoRequest {
-	url := "https://drive.wb.ru/client-gateway/api/waysheets/v1/waysheets/"
+func NewGetWaySheetInfoRequest(client transport.HTTPClient, endpoints *Endpoints, token string) *GetWaySheetInfoRequest {
+	url := endpoints.URL(ServiceWaySheets, "/client-gateway/api/waysheets/v1/waysheets/")
 	return &GetWaySheetInfoRequest{
 		originURL:   url,
 		BaseRequest: *NewRequestToken(client, url, token),
@@ -138,7 +138,7 @@ func NewGetWaySheetInfoRequest(client transport.HTTPClient, token string) *GetWa
 func (r *GetWaySheetInfoRequest) Do(ctx context.Context) (response response.GetWaySheetInfoResponse, err error) {
 	err = r.GetUnmarshal(ctx, &response)
 	if err != nil {
-		err = fmt.Errorf("GetWaySheetInfoRequest.Do: %s", err)
+		err = fmt.Errorf("GetWaySheetInfoRequest.Do: %w", err)
 	}
 	return
 }
@@ -10,11 +10,12 @@ import (
 )
 
 type AuthService struct {
-	client transport.HTTPClient
+	client    transport.HTTPClient
+	endpoints *request.Endpoints
 }
 
-func NewAuthService(client transport.HTTPClient) *AuthService {
-	return &AuthService{client: client}
+func NewAuthService(client transport.HTTPClient, endpoints *request.Endpoints) *AuthService {
+	return &AuthService{client: client, endpoints: endpoints}
 }
 
 func (s *AuthService) RequestAuthCode(ctx context.Context, login string) (*models.AuthCode, error) {
@@ -23,22 +24,23 @@ func (s *AuthService) RequestAuthCode(ctx context.Context, login string) (*model
 		return nil, err
 	}
 
-	req := request.NewAuthGetCodeRequest(s.client).
+	req := request.NewAuthGetCodeRequest(s.client, s.endpoints).
 		PhoneNumber(login)
 
 	res, err := req.Do(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed get code by login '%s': %s", login, err)
+		return nil, fmt.Errorf("failed get code by login '%s': %w", login, err)
 	}
 	meta := res.Meta
 	apiError := res.Error
 
 	if meta != nil && meta.Code != errors.ErrorTypeNone && (meta.Code != "" || meta.Message != "") {
-		return nil, fmt.Errorf("failed get code by login '%s': [%s] %s", login, meta.Code, meta.Message)
+		authErr := &errors.AuthAPIError{Code: meta.Code, Message: meta.Message}
+		return nil, fmt.Errorf("failed get code by login '%s': %w", login, errors.NewAuthRequestError(req.URL(), req.StatusCode(), authErr))
 	}
 
 	if apiError != nil && apiError.Code != errors.ErrorTypeNone && (apiError.Code != "" || apiError.Message != "") {
-		return nil, fmt.Errorf("failed get code by login'%s': [%s] %s", login, apiError.Code, apiError.Message)
+		return nil, fmt.Errorf("failed get code by login '%s': %w", login, errors.NewAuthRequestError(req.URL(), req.StatusCode(), apiError))
 	}
 
 	return res.Data, err
@@ -53,23 +55,24 @@ func (s *AuthService) ExchangeCode(ctx context.Context, code int, sticker string
 		return nil, fmt.Errorf("sticker is empty")
 	}
 
-	req := request.NewAuthRequest(s.client).
+	req := request.NewAuthRequest(s.client, s.endpoints).
 		Code(code).
 		Sticker(sticker)
 
 	res, err := req.Do(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed exchange code by code '%d' and sticker '%s': %s", code, sticker, err)
+		return nil, fmt.Errorf("failed exchange code by code '%d' and sticker '%s': %w", code, sticker, err)
 	}
 
 	meta := res.Meta
 	apiError := res.Error
 	if meta != nil && meta.Code != errors.ErrorTypeNone && (meta.Code != "" || meta.Message != "") {
-		return nil, fmt.Errorf("failed exchange code by code '%d' and sticker '%s': [%s] %s", code, sticker, meta.Code, meta.Message)
+		authErr := &errors.AuthAPIError{Code: meta.Code, Message: meta.Message}
+		return nil, fmt.Errorf("failed exchange code by code '%d' and sticker '%s': %w", code, sticker, errors.NewAuthRequestError(req.URL(), req.StatusCode(), authErr))
 	}
 
 	if apiError != nil && apiError.Code != errors.ErrorTypeNone && (apiError.Code != "" || apiError.Message != "") {
-		return nil, fmt.Errorf("failed exchange code by code '%d' and sticker '%s': [%s] %s", code, sticker, apiError.Code, apiError.Message)
+		return nil, fmt.Errorf("failed exchange code by code '%d' and sticker '%s': %w", code, sticker, errors.NewAuthRequestError(req.URL(), req.StatusCode(), apiError))
 	}
 
 	return res.Data, nil
@@ -84,18 +87,18 @@ func (s *AuthService) GetSessionToken(ctx context.Context, login, accessToken st
 		return nil, err
 	}
 
-	req := request.NewAuthMergeRequest(s.client).
+	req := request.NewAuthMergeRequest(s.client, s.endpoints).
 		PhoneNumber(login).
 		Token(accessToken)
 
 	res, err := req.Do(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed merge token by login '%s': %s", login, err)
+		return nil, fmt.Errorf("failed merge token by login '%s': %w", login, err)
 	}
 
 	apiError := res.Error
 	if apiError != nil && apiError.Code != errors.ErrorTypeNone && (apiError.Code != "" || apiError.Message != "") {
-		return nil, fmt.Errorf("failed merge token by login '%s': [%s] %s", login, apiError.Code, apiError.Message)
+		return nil, fmt.Errorf("failed merge token by login '%s': %w", login, errors.NewAuthRequestError(req.URL(), req.StatusCode(), apiError))
 	}
 
 	return res.Data, nil
@@ -122,7 +125,7 @@ func (s *AuthService) GetUserInfo(ctx context.Context, sessionToken string) (*mo
 		return nil, fmt.Errorf("failed to decode JWT session token: %w", err)
 	}
 
-	req := request.NewUserGetInfoRequest(s.client, sessionToken).
+	req := request.NewUserGetInfoRequest(s.client, s.endpoints, sessionToken).
 		ClientID(decodedToken.FreelancerID)
 
 	res, err := req.Do(ctx)
@@ -130,7 +133,7 @@ func (s *AuthService) GetUserInfo(ctx context.Context, sessionToken string) (*mo
 		return nil, fmt.Errorf("failed get user info by user ID %d: %w", decodedToken.FreelancerID, err)
 	}
 	if res.Error != nil && (res.Error.Code != "" || res.Error.Err != "") {
-		return nil, fmt.Errorf("failed to get user info by user ID %d: %d %s", decodedToken.FreelancerID, res.Error.Code, res.Error.Error())
+		return nil, fmt.Errorf("failed to get user info by user ID %d: %w", decodedToken.FreelancerID, errors.NewAPIRequestError(req.URL(), req.StatusCode(), res.Error))
 	}
 
 	return res.Data, nil
@@ -0,0 +1,523 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type CassetteMode string
+
+const (
+	CassetteModeRecord CassetteMode = "record" // requests are sent by the origin client and saved
+	CassetteModeReplay CassetteMode = "replay" // saved responses are served without the network
+)
+
+const cassetteRedacted = "REDACTED"
+
+// Headers which are not saved
+var cassetteRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Device-Id"}
+
+// JSON fields of the bodies which values are replaced by cassetteRedacted at any depth
+var cassetteRedactedFields = map[string]struct{}{
+	"access_token":  {},
+	"refresh_token": {},
+	"authv3_token":  {},
+	"captcha_token": {},
+	"sticker":       {},
+	"phone_number":  {},
+	"phone":         {},
+	"telegram":      {},
+}
+
+// Requests which are not recorded: the authorization bodies are the credentials, in the replay mode
+// they fail unless the player has the origin client
+var cassetteSkippedPaths = []string{"/user-management/"}
+
+// CassetteInteraction Saved request and its response, the file of the cassette directory
+type CassetteInteraction struct {
+	Seq            int             `json:"seq"`
+	RecordedAt     time.Time       `json:"recorded_at"`
+	Duration       time.Duration   `json:"duration"`
+	Method         string          `json:"method"`
+	URL            string          `json:"url"`
+	RequestHeader  http.Header     `json:"request_header,omitempty"`
+	RequestBody    json.RawMessage `json:"request_body,omitempty"`
+	Status         int             `json:"status,omitempty"`
+	ResponseHeader http.Header     `json:"response_header,omitempty"`
+	ResponseBody   json.RawMessage `json:"response_body,omitempty"` // if the body is JSON
+	ResponseText   string          `json:"response_text,omitempty"` // if the body is not JSON
+	Error          string          `json:"error,omitempty"`         // transport error, there is no response
+
+	isUsed bool
+}
+
+// CassetteHTTPClient Records the traffic of the origin client to the cassette directory with the tokens redacted,
+// or replays it. In the replay mode the requests are matched by the method and the URL path with the query,
+// then by the path only, e.g. the shipments of the other day. The interactions of the same request are served
+// in the recorded order, the last one is repeated after that
+type CassetteHTTPClient struct {
+	mode   CassetteMode
+	dir    string
+	origin HTTPClient // in the replay mode serves the requests which are not recorded, if nil they fail
+
+	mtx          sync.Mutex
+	seq          int
+	interactions map[string][]*CassetteInteraction // replay: request key -> interactions
+	userAgent    string
+	platform     string
+	deviceID     string
+	isClose      bool
+}
+
+// NewCassetteRecorder Saves every request of the origin client to the directory, the numbering continues
+// after the existing files
+func NewCassetteRecorder(origin HTTPClient, dir string) (*CassetteHTTPClient, error) {
+	if origin == nil {
+		return nil, fmt.Errorf("Transport.NewCassetteRecorder(): origin client is nil")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("Transport.NewCassetteRecorder(): failed create cassette directory %s: %v", dir, err)
+	}
+	interactions, err := readCassette(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Transport.NewCassetteRecorder(): %v", err)
+	}
+
+	c := &CassetteHTTPClient{
+		mode:   CassetteModeRecord,
+		dir:    dir,
+		origin: origin,
+	}
+	for _, interaction := range interactions {
+		c.seq = max(c.seq, interaction.Seq)
+	}
+	return c, nil
+}
+
+// NewCassettePlayer Serves the responses saved in the directory, the origin may be nil
+func NewCassettePlayer(dir string, origin HTTPClient) (*CassetteHTTPClient, error) {
+	interactions, err := readCassette(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Transport.NewCassettePlayer(): %v", err)
+	}
+
+	c := &CassetteHTTPClient{
+		mode:         CassetteModeReplay,
+		dir:          dir,
+		origin:       origin,
+		interactions: make(map[string][]*CassetteInteraction),
+		userAgent:    userAgent,
+		platform:     platform,
+	}
+	for _, interaction := range interactions {
+		exactKey, pathKey := cassetteKeys(interaction.Method, interaction.URL)
+		c.interactions[exactKey] = append(c.interactions[exactKey], interaction)
+		if pathKey != exactKey {
+			c.interactions[pathKey] = append(c.interactions[pathKey], interaction)
+		}
+	}
+	return c, nil
+}
+
+func (c *CassetteHTTPClient) Mode() CassetteMode {
+	return c.mode
+}
+
+func (c *CassetteHTTPClient) Get(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	res, _, err := c.do(ctx, http.MethodGet, url, nil, header)
+	return res, err
+}
+
+func (c *CassetteHTTPClient) GetDecodeJSON(ctx context.Context, url string, target interface{}, header http.Header) (*http.Response, error) {
+	if target == nil {
+		return nil, fmt.Errorf("Transport.CassetteHTTPClient.GetDecodeJSON(): target cannot be nil")
+	}
+	res, body, err := c.do(ctx, http.MethodGet, url, nil, header)
+	if err != nil {
+		return res, err
+	}
+	return res, decodeCassetteJSON("Transport.CassetteHTTPClient.GetDecodeJSON()", res, body, target)
+}
+
+func (c *CassetteHTTPClient) Post(ctx context.Context, url string, body io.Reader, header http.Header) (*http.Response, error) {
+	data, err := readCassetteBody(body)
+	if err != nil {
+		return nil, fmt.Errorf("Transport.CassetteHTTPClient.Post(): error reading request body: %v", err)
+	}
+	res, _, err := c.do(ctx, http.MethodPost, url, data, header)
+	return res, err
+}
+
+func (c *CassetteHTTPClient) PostDecodeJSON(ctx context.Context, url string, body io.Reader, target interface{}, header http.Header) (*http.Response, error) {
+	if target == nil {
+		return nil, fmt.Errorf("Transport.CassetteHTTPClient.PostDecodeJSON(): target cannot be nil")
+	}
+	data, err := readCassetteBody(body)
+	if err != nil {
+		return nil, fmt.Errorf("Transport.CassetteHTTPClient.PostDecodeJSON(): error reading request body: %v", err)
+	}
+	res, resBody, err := c.do(ctx, http.MethodPost, url, data, header)
+	if err != nil {
+		return res, err
+	}
+	return res, decodeCassetteJSON("Transport.CassetteHTTPClient.PostDecodeJSON()", res, resBody, target)
+}
+
+// do Returns the response with the read body, the body of the response can be read again
+func (c *CassetteHTTPClient) do(ctx context.Context, method, url string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	if c.IsClose() {
+		return nil, nil, fmt.Errorf("Transport.CassetteHTTPClient.do(): client is closed")
+	}
+
+	if c.mode == CassetteModeReplay {
+		if interaction := c.next(method, url); interaction != nil {
+			return replayCassette(ctx, interaction, header)
+		}
+		if c.origin == nil {
+			return nil, nil, fmt.Errorf("Transport.CassetteHTTPClient.do(): no recorded interaction for %s %s", method, url)
+		}
+		return c.send(ctx, method, url, body, header)
+	}
+
+	start := time.Now()
+	res, resBody, err := c.send(ctx, method, url, body, header)
+	if ctx.Err() == nil && !isCassetteSkipped(url) {
+		if saveErr := c.save(start, method, url, body, header, res, resBody, err); saveErr != nil {
+			return res, resBody, fmt.Errorf("Transport.CassetteHTTPClient.do(): %v", saveErr)
+		}
+	}
+	return res, resBody, err
+}
+
+// send Request of the origin client, the body of the response is read
+func (c *CassetteHTTPClient) send(ctx context.Context, method, url string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	var res *http.Response
+	var err error
+	if method == http.MethodGet {
+		res, err = c.origin.Get(ctx, url, header)
+	} else {
+		res, err = c.origin.Post(ctx, url, bytes.NewReader(body), header)
+	}
+	if err != nil {
+		return res, nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res, nil, fmt.Errorf("Transport.CassetteHTTPClient.send(): error reading response body: %v", err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+	return res, resBody, nil
+}
+
+func (c *CassetteHTTPClient) save(start time.Time, method, url string, body []byte, header http.Header, res *http.Response, resBody []byte, resErr error) error {
+	interaction := &CassetteInteraction{
+		RecordedAt:    start,
+		Duration:      time.Since(start),
+		Method:        method,
+		URL:           url,
+		RequestHeader: redactCassetteHeader(header),
+		RequestBody:   redactCassetteJSON(body),
+	}
+	if resErr != nil {
+		interaction.Error = resErr.Error()
+	}
+	if res != nil {
+		interaction.Status = res.StatusCode
+		interaction.ResponseHeader = redactCassetteHeader(res.Header)
+		// the body is saved decoded
+		interaction.ResponseHeader.Del("Content-Encoding")
+		interaction.ResponseHeader.Del("Content-Length")
+		if data := redactCassetteJSON(resBody); data != nil {
+			interaction.ResponseBody = data
+		} else {
+			interaction.ResponseText = string(resBody)
+		}
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.seq++
+	interaction.Seq = c.seq
+
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed marshal interaction %s %s: %v", method, url, err)
+	}
+	path := filepath.Join(c.dir, fmt.Sprintf("%06d.json", interaction.Seq))
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed save interaction %s %s: %v", method, url, err)
+	}
+	return nil
+}
+
+// next Recorded interaction of the request, nil if there is none
+func (c *CassetteHTTPClient) next(method, url string) *CassetteInteraction {
+	exactKey, pathKey := cassetteKeys(method, url)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for _, key := range []string{exactKey, pathKey} {
+		for _, interaction := range c.interactions[key] {
+			if !interaction.isUsed {
+				interaction.isUsed = true
+				return interaction
+			}
+		}
+	}
+	for _, key := range []string{exactKey, pathKey} {
+		if interactions := c.interactions[key]; len(interactions) > 0 {
+			return interactions[len(interactions)-1]
+		}
+	}
+	return nil
+}
+
+func (c *CassetteHTTPClient) SetUserAgent(ua string) {
+	if c.origin != nil {
+		c.origin.SetUserAgent(ua)
+		return
+	}
+	c.mtx.Lock()
+	c.userAgent = ua
+	c.mtx.Unlock()
+}
+
+func (c *CassetteHTTPClient) GetUserAgent() string {
+	if c.origin != nil {
+		return c.origin.GetUserAgent()
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.userAgent
+}
+
+func (c *CassetteHTTPClient) SetPlatform(p string) {
+	if c.origin != nil {
+		c.origin.SetPlatform(p)
+		return
+	}
+	c.mtx.Lock()
+	c.platform = p
+	c.mtx.Unlock()
+}
+
+func (c *CassetteHTTPClient) GetPlatform() string {
+	if c.origin != nil {
+		return c.origin.GetPlatform()
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.platform
+}
+
+func (c *CassetteHTTPClient) SetDeviceID(id string) error {
+	if c.origin != nil {
+		return c.origin.SetDeviceID(id)
+	}
+	if len(id) <= 0 || len(id) > deviceIDLength {
+		return fmt.Errorf("device id must be > 0 and < %d chars", deviceIDLength)
+	}
+	c.mtx.Lock()
+	c.deviceID = id
+	c.mtx.Unlock()
+	return nil
+}
+
+func (c *CassetteHTTPClient) GetDeviceID() string {
+	if c.origin != nil {
+		return c.origin.GetDeviceID()
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.deviceID
+}
+
+func (c *CassetteHTTPClient) Close() error {
+	c.mtx.Lock()
+	if c.isClose {
+		c.mtx.Unlock()
+		return fmt.Errorf("client already closed")
+	}
+	c.isClose = true
+	c.mtx.Unlock()
+
+	if c.origin != nil && !c.origin.IsClose() {
+		return c.origin.Close()
+	}
+	return nil
+}
+
+func (c *CassetteHTTPClient) IsClose() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.isClose
+}
+
+// readCassette Interactions of the directory ordered by the number, the missing directory is empty
+func readCassette(dir string) ([]*CassetteInteraction, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed read cassette directory %s: %v", dir, err)
+	}
+
+	interactions := make([]*CassetteInteraction, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed read interaction %s: %v", path, err)
+		}
+		interaction := &CassetteInteraction{}
+		if err = json.Unmarshal(data, interaction); err != nil {
+			return nil, fmt.Errorf("failed unmarshal interaction %s: %v", path, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+	sort.Slice(interactions, func(i, j int) bool { return interactions[i].Seq < interactions[j].Seq })
+	return interactions, nil
+}
+
+func replayCassette(ctx context.Context, interaction *CassetteInteraction, header http.Header) (*http.Response, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, fmt.Errorf("Transport.CassetteHTTPClient.replay(): context canceled: %v", err)
+	}
+	if interaction.Error != "" {
+		return nil, nil, fmt.Errorf("Transport.CassetteHTTPClient.replay(): recorded error of interaction %d: %s", interaction.Seq, interaction.Error)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, interaction.Method, interaction.URL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Transport.CassetteHTTPClient.replay(): error create HTTP request of interaction %d: %v", interaction.Seq, err)
+	}
+	if header != nil {
+		req.Header = header
+	}
+
+	body := []byte(interaction.ResponseBody)
+	if interaction.ResponseBody == nil {
+		body = []byte(interaction.ResponseText)
+	}
+	res := &http.Response{
+		Status:        strconv.Itoa(interaction.Status) + " " + http.StatusText(interaction.Status),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.ResponseHeader.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	if res.Header == nil {
+		res.Header = http.Header{}
+	}
+	return res, body, nil
+}
+
+// cassetteKeys Keys of the request by the path with the query and by the path only, the host is not matched
+func cassetteKeys(method, rawURL string) (string, string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return method + " " + rawURL, method + " " + rawURL
+	}
+	pathKey := method + " " + u.Path
+	if u.RawQuery == "" {
+		return pathKey, pathKey
+	}
+	return pathKey + "?" + u.Query().Encode(), pathKey
+}
+
+func isCassetteSkipped(rawURL string) bool {
+	for _, path := range cassetteSkippedPaths {
+		if strings.Contains(rawURL, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func readCassetteBody(body io.Reader) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	return io.ReadAll(body)
+}
+
+func decodeCassetteJSON(loc string, res *http.Response, body []byte, target interface{}) error {
+	if !isLikelyJSON(res.Header.Get("Content-Type"), body) {
+		return fmt.Errorf("%s: response is not valid JSON. Content-Type: %s\nBody: %s", loc, res.Header.Get("Content-Type"), string(body))
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("%s: error decoding JSON: %v\nBody: %s", loc, err, string(body))
+	}
+	return nil
+}
+
+func redactCassetteHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := header.Clone()
+	for _, key := range cassetteRedactedHeaders {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, cassetteRedacted)
+		}
+	}
+	return redacted
+}
+
+// redactCassetteJSON nil if the body is not JSON
+func redactCassetteJSON(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil
+	}
+	data, err := json.Marshal(redactCassetteValue(value))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func redactCassetteValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if _, ok := cassetteRedactedFields[key]; ok && field != nil && field != "" {
+				v[key] = cassetteRedacted
+				continue
+			}
+			v[key] = redactCassetteValue(field)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactCassetteValue(v[i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCassetteReplayIsHermetic(t *testing.T) {
+	var served atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":[1,2]}`)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	recorder, err := NewCassetteRecorder(NewBaseHTTPClient(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := recorder.Get(ctx, server.URL+"/reports-service/api/v1/last-mile", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	// the authorization is not recorded
+	res, err = recorder.Post(ctx, server.URL+"/user-management/api/v1/public/registration/code", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	player, err := NewCassettePlayer(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replayed struct {
+		Data []int `json:"data"`
+	}
+	if _, err = player.GetDecodeJSON(ctx, server.URL+"/reports-service/api/v1/last-mile", &replayed, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.Data) != 2 || replayed.Data[1] != 2 {
+		t.Fatalf("replayed data %v", replayed.Data)
+	}
+
+	for _, path := range []string{"/user-management/api/v1/public/registration/code", "/shipments-service/api/v1/shipments"} {
+		if res, err = player.Post(ctx, server.URL+path, nil, nil); err == nil {
+			res.Body.Close()
+			t.Fatalf("request %s missing in the cassette is served", path)
+		}
+	}
+	if n := served.Load(); n != 2 {
+		t.Fatalf("server got %d requests, want 2 recorded ones", n)
+	}
+}
@@ -70,12 +70,17 @@ type HTTPClient interface {
 	IsClose() bool
 }
 
+// RequestObserver Called after every request with its result and duration, the response body must not be read
+type RequestObserver func(req *http.Request, res *http.Response, err error, duration time.Duration)
+
 type HTTPClientParameters struct {
 	UserAgent    string          `json:"user_agent,omitempty"`
 	SecUserAgent string          `json:"sec_user_agent,omitempty"`
 	Platform     string          `json:"platform,omitempty"`
 	DeviceID     string          `json:"device_id,omitempty"`
 	Transport    *http.Transport `json:"transport,omitempty"`
+	Observer     RequestObserver `json:"-"`
+	Limiter      *RateLimiter    `json:"-"`
 }
 
 type BaseHTTPClient struct {
@@ -87,6 +92,8 @@ type BaseHTTPClient struct {
 	platform     string
 	deviceID     string
 	isClose      bool
+	observer     RequestObserver
+	limiter      *RateLimiter
 
 	defaultHeaders http.Header
 }
@@ -150,6 +157,9 @@ func NewBaseHTTPClientWithParams(p *HTTPClientParameters) *BaseHTTPClient {
 		client.deviceID = p.DeviceID
 	}
 
+	client.observer = p.Observer
+	client.limiter = p.Limiter
+
 	return client
 }
 
@@ -163,6 +173,8 @@ func (c *BaseHTTPClient) request(ctx context.Context, req *http.Request) (*http.
 	secUA := c.secUserAgent
 	plat := c.platform
 	deviceID := c.deviceID
+	observer := c.observer
+	limiter := c.limiter
 	c.mtx.RUnlock()
 
 	if req.Header == nil {
@@ -195,12 +207,30 @@ func (c *BaseHTTPClient) request(ctx context.Context, req *http.Request) (*http.
 	}
 
 	if ctx.Err() != nil {
-		return nil, fmt.Errorf("Transport.HttpClient.request(): context canceled: %v", ctx.Err())
+		return nil, fmt.Errorf("Transport.HttpClient.request(): context canceled: %w", ctx.Err())
+	}
+
+	release := func() {}
+	if limiter != nil {
+		var err error
+		release, err = limiter.Acquire(ctx, req.URL)
+		if err != nil {
+			return nil, fmt.Errorf("Transport.HttpClient.request(): rate limit wait: %w", err)
+		}
 	}
 
+	start := time.Now()
 	res, err := c.Do(req)
+	if observer != nil {
+		observer(req, res, err, time.Since(start))
+	}
 	if err != nil {
-		return nil, fmt.Errorf("Transport.HttpClient.request(): %v", err)
+		release()
+		return nil, fmt.Errorf("Transport.HttpClient.request(): %w", err)
+	}
+	if limiter != nil {
+		limiter.Observe(req.URL, res)
+		res.Body = &rateLimitedBody{ReadCloser: res.Body, release: release}
 	}
 
 	if strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
@@ -317,6 +347,18 @@ func (c *BaseHTTPClient) PostDecodeJSON(ctx context.Context, url string, body io
 	return res, err
 }
 
+func (c *BaseHTTPClient) SetObserver(observer RequestObserver) {
+	c.mtx.Lock()
+	c.observer = observer
+	c.mtx.Unlock()
+}
+
+func (c *BaseHTTPClient) SetLimiter(limiter *RateLimiter) {
+	c.mtx.Lock()
+	c.limiter = limiter
+	c.mtx.Unlock()
+}
+
 func (c *BaseHTTPClient) SetUserAgent(ua string) {
 	c.mtx.Lock()
 	c.userAgent = ua
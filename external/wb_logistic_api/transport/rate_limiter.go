@@ -0,0 +1,209 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitGroupDefault Limit of the groups which are not configured
+const RateLimitGroupDefault = "default"
+
+const (
+	rateLimitRetryAfterDefault = 5 * time.Second // 429 without Retry-After
+	rateLimitSlowdownMax       = 8               // the rate is divided by at most
+	rateLimitRecoveryPeriod    = time.Minute     // the slowdown is halved after the period without 429
+)
+
+// RateLimit Token bucket of the endpoint group, zero Rate or Concurrency is unlimited
+type RateLimit struct {
+	Rate        float64 // requests per second
+	Burst       int     // requests sent at once after the idle time
+	Concurrency int     // requests in flight
+}
+
+// RateLimiter Token buckets and concurrency budgets of the endpoint groups, the requests wait in the queue
+// until the context is done. The server 429 pauses the group for Retry-After and slows it down,
+// the rate recovers while there is no 429
+type RateLimiter struct {
+	limits map[string]RateLimit
+	group  func(path string) string // group of the request by the URL path
+
+	mtx    sync.Mutex
+	groups map[string]*rateLimitGroup
+}
+
+type rateLimitGroup struct {
+	limit RateLimit
+	slots chan struct{} // nil if the concurrency is unlimited
+
+	mtx         sync.Mutex
+	tokens      float64
+	last        time.Time // last refill
+	pausedUntil time.Time
+	slowdown    float64 // >= 1, the rate is divided by it
+	lastLimited time.Time
+}
+
+// NewRateLimiter If group is nil or returns the group which is not in limits, the default limit is used
+func NewRateLimiter(limits map[string]RateLimit, group func(path string) string) *RateLimiter {
+	if limits == nil {
+		limits = map[string]RateLimit{}
+	}
+	return &RateLimiter{
+		limits: limits,
+		group:  group,
+		groups: make(map[string]*rateLimitGroup),
+	}
+}
+
+// Acquire Waits for the token and the concurrency slot of the request group, release must be called
+// when the response is read
+func (l *RateLimiter) Acquire(ctx context.Context, u *url.URL) (release func(), err error) {
+	g := l.groupOf(u)
+	if err = g.wait(ctx); err != nil {
+		return nil, err
+	}
+	if g.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case g.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-g.slots }) }, nil
+}
+
+// Observe Slows down the group of the request if the response is 429
+func (l *RateLimiter) Observe(u *url.URL, res *http.Response) {
+	if res == nil {
+		return
+	}
+	g := l.groupOf(u)
+	if res.StatusCode == http.StatusTooManyRequests {
+		g.limited(parseRetryAfter(res.Header.Get("Retry-After")))
+		return
+	}
+	g.recover()
+}
+
+func (l *RateLimiter) groupOf(u *url.URL) *rateLimitGroup {
+	name := RateLimitGroupDefault
+	if l.group != nil && u != nil {
+		if group := l.group(u.Path); group != "" {
+			name = group
+		}
+	}
+	limit, ok := l.limits[name]
+	if !ok {
+		name = RateLimitGroupDefault
+		limit = l.limits[name]
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	g, ok := l.groups[name]
+	if !ok {
+		g = &rateLimitGroup{
+			limit:    limit,
+			tokens:   float64(max(limit.Burst, 1)),
+			last:     time.Now(),
+			slowdown: 1,
+		}
+		if limit.Concurrency > 0 {
+			g.slots = make(chan struct{}, limit.Concurrency)
+		}
+		l.groups[name] = g
+	}
+	return g
+}
+
+// wait Takes the token, the pause after 429 is waited even if the rate is unlimited
+func (g *rateLimitGroup) wait(ctx context.Context) error {
+	for {
+		g.mtx.Lock()
+		now := time.Now()
+		var delay time.Duration
+		switch {
+		case now.Before(g.pausedUntil):
+			delay = g.pausedUntil.Sub(now)
+		case g.limit.Rate <= 0:
+			g.mtx.Unlock()
+			return nil
+		default:
+			rate := g.limit.Rate / g.slowdown
+			g.tokens = min(float64(max(g.limit.Burst, 1)), g.tokens+now.Sub(g.last).Seconds()*rate)
+			g.last = now
+			if g.tokens >= 1 {
+				g.tokens--
+				g.mtx.Unlock()
+				return nil
+			}
+			delay = time.Duration((1 - g.tokens) / rate * float64(time.Second))
+		}
+		g.mtx.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (g *rateLimitGroup) limited(retryAfter time.Duration) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	now := time.Now()
+	if until := now.Add(retryAfter); until.After(g.pausedUntil) {
+		g.pausedUntil = until
+	}
+	g.slowdown = min(g.slowdown*2, rateLimitSlowdownMax)
+	g.tokens = 0
+	g.last = now
+	g.lastLimited = now
+}
+
+func (g *rateLimitGroup) recover() {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if g.slowdown <= 1 || time.Since(g.lastLimited) < rateLimitRecoveryPeriod {
+		return
+	}
+	g.slowdown = max(g.slowdown/2, 1)
+	g.lastLimited = time.Now() // the next step after the next period
+}
+
+// parseRetryAfter Seconds or the HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return rateLimitRetryAfterDefault
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return rateLimitRetryAfterDefault
+}
+
+// rateLimitedBody Releases the concurrency slot when the body is closed
+type rateLimitedBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *rateLimitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
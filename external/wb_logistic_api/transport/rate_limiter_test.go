@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"missing", "", rateLimitRetryAfterDefault},
+		{"seconds", "3", 3 * time.Second},
+		{"zero seconds", "0", 0},
+		{"negative seconds", "-1", rateLimitRetryAfterDefault},
+		{"invalid", "soon", rateLimitRetryAfterDefault},
+		{"past date", "Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got != tt.want {
+				t.Fatalf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(future); got <= 58*time.Second || got > time.Minute {
+		t.Fatalf("parseRetryAfter(%q) = %v, want about a minute", future, got)
+	}
+}
+
+func TestRateLimiterSlowdown(t *testing.T) {
+	limiter := NewRateLimiter(map[string]RateLimit{RateLimitGroupDefault: {Rate: 100, Burst: 1}}, nil)
+	u := &url.URL{Path: "/shipments-service/api/v1/shipments"}
+	g := limiter.groupOf(u)
+	limited := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"0"}}}
+	ok := &http.Response{StatusCode: http.StatusOK}
+
+	steps := []struct {
+		name     string
+		res      *http.Response
+		elapsed  time.Duration // since the last 429 before the response
+		slowdown float64
+	}{
+		{"first 429", limited, 0, 2},
+		{"second 429", limited, 0, 4},
+		{"third 429", limited, 0, 8},
+		{"slowdown is capped", limited, 0, rateLimitSlowdownMax},
+		{"no recovery within the period", ok, rateLimitRecoveryPeriod / 2, rateLimitSlowdownMax},
+		{"recovery after the period", ok, rateLimitRecoveryPeriod, 4},
+		{"next step waits for the next period", ok, 0, 4},
+		{"next recovery", ok, rateLimitRecoveryPeriod, 2},
+		{"full recovery", ok, rateLimitRecoveryPeriod, 1},
+		{"no recovery below the rate", ok, rateLimitRecoveryPeriod, 1},
+	}
+	for _, step := range steps {
+		g.mtx.Lock()
+		g.lastLimited = g.lastLimited.Add(-step.elapsed)
+		g.mtx.Unlock()
+
+		limiter.Observe(u, step.res)
+
+		g.mtx.Lock()
+		slowdown := g.slowdown
+		g.mtx.Unlock()
+		if slowdown != step.slowdown {
+			t.Fatalf("%s: slowdown %v, want %v", step.name, slowdown, step.slowdown)
+		}
+	}
+}
+
+func TestRateLimiterPause(t *testing.T) {
+	limiter := NewRateLimiter(nil, nil)
+	u := &url.URL{Path: "/reports-service/api/v1/last-mile"}
+
+	// the rate is unlimited, but the pause of 429 is waited
+	release, err := limiter.Acquire(context.Background(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	limiter.Observe(u, &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"60"}}})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err = limiter.Acquire(ctx, u); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("request is not paused after 429: %v", err)
+	}
+}
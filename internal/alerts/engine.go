@@ -0,0 +1,170 @@
+package alerts
+
+import (
+	"sync"
+	"time"
+	"wb_logistic_assistant/internal/config"
+	"wb_logistic_assistant/internal/errors"
+)
+
+// Event Fired or resolved alert of the rule for the route
+type Event struct {
+	Rule       string
+	Source     string
+	Metric     string
+	Operator   string
+	Threshold  float64
+	RouteID    int
+	Value      float64
+	Since      time.Time // the condition is met since
+	Time       time.Time
+	IsResolved bool
+}
+
+type ruleStateKey struct {
+	rule    string
+	routeID int
+}
+
+type ruleState struct {
+	since        time.Time // zero if the condition is not met
+	value        float64
+	isFiring     bool
+	isNotified   bool // firing was sent, so the resolution must be sent too
+	lastNotified time.Time
+}
+
+// Engine Evaluates the rules over the samples of the report data. Safe for concurrent use by several reporters
+type Engine struct {
+	mu     sync.Mutex
+	rules  []*config.AlertsRule
+	states map[ruleStateKey]*ruleState
+}
+
+func NewEngine(config *config.Alerts) (*Engine, error) {
+	for _, rule := range config.Rules() {
+		metrics, ok := sourceMetrics[rule.Source()]
+		if !ok {
+			return nil, errors.Newf("alerts.NewEngine()", "unknown source %s of rule %s", rule.Source(), rule.Name())
+		}
+		if _, ok = metrics[rule.Metric()]; !ok {
+			return nil, errors.Newf("alerts.NewEngine()", "unknown metric %s of source %s in rule %s", rule.Metric(), rule.Source(), rule.Name())
+		}
+	}
+	return &Engine{
+		rules:  config.Rules(),
+		states: map[ruleStateKey]*ruleState{},
+	}, nil
+}
+
+// HasSource There are rules over the source, so the samples are worth collecting
+func (e *Engine) HasSource(source string) bool {
+	for _, rule := range e.rules {
+		if rule.Source() == source {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate Checks the rules of the source over the samples of one cycle.
+// An alert fires once the condition holds for the rule duration and is not repeated while it keeps holding.
+// Fired alerts of the same rule and route are not sent more often than the rule cooldown.
+// Routes which are missing in the samples are considered recovered
+func (e *Engine) Evaluate(source string, now time.Time, samples []*Sample) []*Event {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var events []*Event
+	for _, rule := range e.rules {
+		if rule.Source() != source {
+			continue
+		}
+
+		seen := make(map[int]struct{}, len(samples))
+		for _, sample := range samples {
+			if sample == nil || !isRuleRoute(rule, sample.RouteID) {
+				continue
+			}
+			seen[sample.RouteID] = struct{}{}
+
+			value, ok := sample.Values[rule.Metric()]
+			if !ok {
+				continue
+			}
+
+			key := ruleStateKey{rule: rule.Name(), routeID: sample.RouteID}
+			state := e.states[key]
+			if state == nil {
+				state = &ruleState{}
+				e.states[key] = state
+			}
+
+			if event := e.evaluateState(rule, state, sample.RouteID, value, now); event != nil {
+				events = append(events, event)
+			}
+		}
+
+		for key, state := range e.states {
+			if key.rule != rule.Name() {
+				continue
+			}
+			if _, ok := seen[key.routeID]; ok {
+				continue
+			}
+			if event := e.resolveState(rule, state, key.routeID, now); event != nil {
+				events = append(events, event)
+			}
+			delete(e.states, key)
+		}
+	}
+	return events
+}
+
+func (e *Engine) evaluateState(rule *config.AlertsRule, state *ruleState, routeID int, value float64, now time.Time) *Event {
+	state.value = value
+	if !isRuleMatch(rule, value) {
+		return e.resolveState(rule, state, routeID, now)
+	}
+
+	if state.since.IsZero() {
+		state.since = now
+	}
+	if state.isFiring || now.Sub(state.since) < rule.Duration() {
+		return nil
+	}
+
+	state.isFiring = true
+	state.isNotified = state.lastNotified.IsZero() || now.Sub(state.lastNotified) >= rule.Cooldown()
+	if !state.isNotified {
+		return nil
+	}
+	state.lastNotified = now
+	return newEvent(rule, state, routeID, now, false)
+}
+
+func (e *Engine) resolveState(rule *config.AlertsRule, state *ruleState, routeID int, now time.Time) *Event {
+	var event *Event
+	if state.isFiring && state.isNotified && rule.IsResolve() {
+		event = newEvent(rule, state, routeID, now, true)
+	}
+	state.since = time.Time{}
+	state.isFiring = false
+	state.isNotified = false
+	return event
+}
+
+func newEvent(rule *config.AlertsRule, state *ruleState, routeID int, now time.Time, isResolved bool) *Event {
+	return &Event{
+		Rule:       rule.Name(),
+		Source:     rule.Source(),
+		Metric:     rule.Metric(),
+		Operator:   rule.Operator(),
+		Threshold:  rule.Value(),
+		RouteID:    routeID,
+		Value:      state.value,
+		Since:      state.since,
+		Time:       now,
+		IsResolved: isResolved,
+	}
+}
@@ -0,0 +1,66 @@
+package alerts
+
+import "wb_logistic_assistant/internal/config"
+
+const (
+	SourceGeneralRoutes = config.AlertsSourceGeneralRoutes
+	SourceFinanceDaily  = config.AlertsSourceFinanceDaily
+)
+
+// Metrics of the general routes report data
+const (
+	MetricRemainsBarcodes        = "remains_barcodes"
+	MetricBarcodes               = "barcodes"
+	MetricChangeBarcodes         = "change_barcodes"
+	MetricTares                  = "tares"
+	MetricVolumeNormativePercent = "volume_normative_percent"
+	MetricRating                 = "rating"
+	MetricWaySheetInterval       = "waysheet_interval" // ms, the same as durations in the config
+)
+
+// Metrics of the finance daily report data
+const (
+	MetricFlights                  = "flights"
+	MetricFlightsOpened            = "flights_opened"
+	MetricBarcodesShipped          = "barcodes_shipped"
+	MetricBarcodesAverage          = "barcodes_average"
+	MetricBarcodesDeviationPercent = "barcodes_deviation_percent"
+	MetricTareReturned             = "tare_returned"
+	MetricIncome                   = "income"
+	MetricFine                     = "fine"
+	MetricMargin                   = "margin"
+)
+
+var sourceMetrics = map[string]map[string]struct{}{
+	SourceGeneralRoutes: {
+		MetricRemainsBarcodes:        {},
+		MetricBarcodes:               {},
+		MetricChangeBarcodes:         {},
+		MetricTares:                  {},
+		MetricVolumeNormativePercent: {},
+		MetricRating:                 {},
+		MetricWaySheetInterval:       {},
+	},
+	SourceFinanceDaily: {
+		MetricFlights:                  {},
+		MetricFlightsOpened:            {},
+		MetricBarcodesShipped:          {},
+		MetricBarcodesAverage:          {},
+		MetricBarcodesDeviationPercent: {},
+		MetricTareReturned:             {},
+		MetricIncome:                   {},
+		MetricFine:                     {},
+		MetricMargin:                   {},
+	},
+}
+
+// IsDurationMetric Value of the metric is a duration in ms
+func IsDurationMetric(metric string) bool {
+	return metric == MetricWaySheetInterval
+}
+
+// Sample Metrics of one route in one evaluation cycle, a metric is missing if its value is unknown
+type Sample struct {
+	RouteID int
+	Values  map[string]float64
+}
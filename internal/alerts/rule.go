@@ -0,0 +1,29 @@
+package alerts
+
+import "wb_logistic_assistant/internal/config"
+
+func isRuleRoute(rule *config.AlertsRule, routeID int) bool {
+	if len(rule.Routes()) == 0 {
+		return true
+	}
+	_, ok := rule.RoutesMap()[routeID]
+	return ok
+}
+
+func isRuleMatch(rule *config.AlertsRule, value float64) bool {
+	switch rule.Operator() {
+	case ">":
+		return value > rule.Value()
+	case ">=":
+		return value >= rule.Value()
+	case "<":
+		return value < rule.Value()
+	case "<=":
+		return value <= rule.Value()
+	case "==":
+		return value == rule.Value()
+	case "!=":
+		return value != rule.Value()
+	}
+	return false
+}
@@ -2,61 +2,100 @@ package app
 
 import (
 	"context"
+	"html"
+	"net/http"
 	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 	"wb_logistic_assistant/internal/config"
 	"wb_logistic_assistant/internal/errors"
 	"wb_logistic_assistant/internal/initializer"
 	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/outbox"
 	"wb_logistic_assistant/internal/prompters"
 	"wb_logistic_assistant/internal/reporters"
 	"wb_logistic_assistant/internal/scheduler"
 	"wb_logistic_assistant/internal/services"
 	"wb_logistic_assistant/internal/storage"
+	"wb_logistic_assistant/internal/telegram_commands"
 )
 
 type App struct {
-	config                           *config.Config
-	initializer                      *initializer.Initializer
-	storage                          storage.Storage
-	services                         *services.Container
-	scheduler                        scheduler.Scheduler
-	schedulerGeneralRoutesTaskConfig *scheduler.TaskConfig
-	schedulerShipmentCloseTaskConfig *scheduler.TaskConfig
-	schedulerFinanceRoutesTaskConfig *scheduler.TaskConfig
-	schedulerFinanceDailyTaskConfig  *scheduler.TaskConfig
-	generalRoutesReporter            reporters.Reporter
-	shipmentCloseReporter            reporters.Reporter
-	financeRoutesReporter            reporters.Reporter
-	financeDailyReporter             reporters.Reporter
-	isStarted                        bool
+	config                               *config.Config
+	initializer                          *initializer.Initializer
+	storage                              storage.Storage
+	services                             *services.Container
+	scheduler                            scheduler.Scheduler
+	schedulerGeneralRoutesTaskConfig     *scheduler.TaskConfig
+	schedulerShipmentCloseTaskConfig     *scheduler.TaskConfig
+	schedulerFinanceRoutesTaskConfig     *scheduler.TaskConfig
+	schedulerFinanceDailyTaskConfig      *scheduler.TaskConfig
+	schedulerFinanceWeeklyTaskConfig     *scheduler.TaskConfig
+	schedulerFinanceMonthlyTaskConfig    *scheduler.TaskConfig
+	schedulerDriverPerformanceTaskConfig *scheduler.TaskConfig
+	schedulerGeneralRoutesSchedule       scheduler.Schedule
+	schedulerShipmentCloseSchedule       scheduler.Schedule
+	schedulerFinanceRoutesSchedule       scheduler.Schedule
+	schedulerFinanceDailySchedule        scheduler.Schedule
+	schedulerFinanceWeeklySchedule       scheduler.Schedule
+	schedulerFinanceMonthlySchedule      scheduler.Schedule
+	schedulerDriverPerformanceSchedule   scheduler.Schedule
+	generalRoutesReporter                reporters.Reporter
+	shipmentCloseReporter                reporters.Reporter
+	financeRoutesReporter                reporters.Reporter
+	financeDailyReporter                 reporters.Reporter
+	financeWeeklyReporter                reporters.Reporter
+	financeMonthlyReporter               reporters.Reporter
+	driverPerformanceReporter            reporters.Reporter
+	wbLogisticTaskIDs                    []uint64 // scheduler tasks of the reports which use WB logistic
+	wbLogisticTaskIDsMtx                 sync.Mutex
+	reportTasks                          []*reportTask
+	reportTasksMtx                       sync.RWMutex
+	isReauthWBLogistic                   atomic.Bool
+	reauthPausedTaskIDs                  []uint64 // paused until the WB logistic session is restored, kept after the failed attempt
+	reauthPausedTaskIDsMtx               sync.Mutex
+	wbAuthPrompter                       *telegram_commands.WBAuthPrompter
+	telegramCommands                     *telegram_commands.Dispatcher
+	telegramCommandsCancel               context.CancelFunc
+	telegramCommandsDone                 chan struct{}
+	outboxCancel                         context.CancelFunc
+	outboxDone                           chan struct{}
+	httpServer                           *http.Server
+	isHeadless                           bool
+	headlessDelegate                     prompters.HeadlessDelegate
+	isStarted                            bool
 }
 
 func NewApp(config *config.Config) *App {
 	return &App{config: config}
 }
 
+// SetHeadless In the headless mode stdin is never read, must be called before Init
+func (a *App) SetHeadless(isHeadless bool) {
+	a.isHeadless = isHeadless
+}
+
 func (a *App) Init() error {
 	logger.Log(logger.INFO, "App.Init()", "Start init app")
 	cfg := a.config
 
-	storage, err := storage.NewFileStorage(cfg)
+	storage, err := loadStorage(cfg)
 	if err != nil {
-		return errors.Wrap(err, "App.Init()", "Failed to create storage")
-	}
-
-	storagePassword := os.Getenv(cfg.Storage().Env())
-	if storagePassword == "" {
-		return errors.New("App.Init()", "Storage key is missing from the system")
+		return errors.Wrap(err, "App.Init()", "")
 	}
-	storage.SetEncrypt([]byte(storagePassword))
 
-	err = storage.Load(cfg.Storage().Path())
+	prompter, delegate, err := newInitPrompter(cfg, storage, a.isHeadless)
 	if err != nil {
-		return errors.Wrap(err, "App.Init()", "Failed to load storage")
+		return errors.Wrap(err, "App.Init()", "")
 	}
+	a.headlessDelegate = delegate
 
-	a.initializer = initializer.NewInitializer(cfg, storage, &prompters.CLIInitAppPrompter{})
+	a.initializer = initializer.NewInitializer(cfg, storage, prompter)
 	dependencies, err := a.initializer.Init()
+	// the delegate is needed again only if the session is restored, e.g. the HTTP page is started again then
+	closeHeadlessDelegate(delegate)
 	if err != nil {
 		return errors.Wrap(err, "App.Init()", "Failed to init app dependencies")
 	}
@@ -68,10 +107,31 @@ func (a *App) Init() error {
 	a.schedulerShipmentCloseTaskConfig = dependencies.SchedulerShipmentCloseTaskConfig
 	a.schedulerFinanceRoutesTaskConfig = dependencies.SchedulerFinanceRoutesTaskConfig
 	a.schedulerFinanceDailyTaskConfig = dependencies.SchedulerFinanceDailyTaskConfig
+	a.schedulerFinanceWeeklyTaskConfig = dependencies.SchedulerFinanceWeeklyTaskConfig
+	a.schedulerFinanceMonthlyTaskConfig = dependencies.SchedulerFinanceMonthlyTaskConfig
+	a.schedulerDriverPerformanceTaskConfig = dependencies.SchedulerDriverPerformanceTaskConfig
+	a.schedulerGeneralRoutesSchedule = dependencies.SchedulerGeneralRoutesSchedule
+	a.schedulerShipmentCloseSchedule = dependencies.SchedulerShipmentCloseSchedule
+	a.schedulerFinanceRoutesSchedule = dependencies.SchedulerFinanceRoutesSchedule
+	a.schedulerFinanceDailySchedule = dependencies.SchedulerFinanceDailySchedule
+	a.schedulerFinanceWeeklySchedule = dependencies.SchedulerFinanceWeeklySchedule
+	a.schedulerFinanceMonthlySchedule = dependencies.SchedulerFinanceMonthlySchedule
+	a.schedulerDriverPerformanceSchedule = dependencies.SchedulerDriverPerformanceSchedule
 	a.generalRoutesReporter = dependencies.GeneralRoutesReporter
 	a.shipmentCloseReporter = dependencies.ShipmentCloseReporter
 	a.financeRoutesReporter = dependencies.FinanceRoutesReporter
 	a.financeDailyReporter = dependencies.FinanceDailyReporter
+	a.financeWeeklyReporter = dependencies.FinanceWeeklyReporter
+	a.financeMonthlyReporter = dependencies.FinanceMonthlyReporter
+	a.driverPerformanceReporter = dependencies.DriverPerformanceReporter
+	a.telegramCommands = dependencies.TelegramCommands
+	a.wbAuthPrompter = dependencies.WBAuthPrompter
+	if a.telegramCommands != nil {
+		a.telegramCommands.SetReauthHandler(a.reauthWBLogistic)
+	}
+	if dependencies.SchedulerCircuitBreaker != nil {
+		dependencies.SchedulerCircuitBreaker.OnStateChange = a.onCircuitStateChange
+	}
 
 	logger.Log(logger.INFO, "App.Init()", "Init app successfully")
 	return nil
@@ -85,14 +145,26 @@ func (a *App) Start() error {
 	a.isStarted = true
 	logger.Log(logger.INFO, "App.Start()", "Start application")
 
+	a.runOutbox()
 	a.runTasks()
+	a.runTelegramCommands()
+
+	// The service endpoints are auxiliary, so their failure must not stop the reports
+	if err := a.runHTTPServer(); err != nil {
+		logger.Logf(logger.ERROR, "App.Start()", "Failed to start HTTP server: %v", err)
+	}
 
 	return nil
 }
 
 func (a *App) Stop() {
 	logger.Log(logger.INFO, "App.Stop()", "Stop application")
+	a.stopTelegramCommands()
 	a.scheduler.Reset()
+	a.clearReauthPausedTasks()
+	a.stopOutbox()
+	a.stopHTTPServer()
+	closeHeadlessDelegate(a.headlessDelegate)
 	a.isStarted = false
 
 	err := a.storage.Save(a.config.Storage().Path())
@@ -106,46 +178,168 @@ func (a *App) Stop() {
 
 func (a *App) Pause() {
 	logger.Log(logger.INFO, "App.Pause()", "Pause application")
+	a.stopTelegramCommands()
 	a.scheduler.Reset()
+	a.clearReauthPausedTasks()
 	a.isStarted = false
 }
 
 func (a *App) runTasks() {
+	// held until all the tasks are scheduled, so the reauth started by the first of them pauses the rest too
+	a.wbLogisticTaskIDsMtx.Lock()
+	defer a.wbLogisticTaskIDsMtx.Unlock()
+	a.wbLogisticTaskIDs = nil
+	a.reportTasksMtx.Lock()
+	a.reportTasks = nil
+	a.reportTasksMtx.Unlock()
+
 	if a.config.Reports().GeneralRoutes().IsEnabled() {
 		logger.Log(logger.INFO, "App.runTasks()", "Start schedule periodic for \"general routes\" reporter")
-		a.scheduler.SchedulePeriodic(
+		a.wbLogisticTaskIDs = append(a.wbLogisticTaskIDs, a.schedule(
 			scheduler.NewCallbackTask("general_routes_report", a.generalRoutesHandler),
 			a.config.Reports().GeneralRoutes().PollingInterval(),
+			a.schedulerGeneralRoutesSchedule,
 			*a.schedulerGeneralRoutesTaskConfig,
-		)
+		))
 	}
 
 	if a.config.Reports().ShipmentClose().IsEnabled() {
 		logger.Log(logger.INFO, "App.runTasks()", "Start schedule periodic for \"shipment close\" reporter")
-		a.scheduler.SchedulePeriodic(
+		a.wbLogisticTaskIDs = append(a.wbLogisticTaskIDs, a.schedule(
 			scheduler.NewCallbackTask("shipment_close_report", a.shipmentCloseHandler),
 			a.config.Reports().ShipmentClose().PollingInterval(),
+			a.schedulerShipmentCloseSchedule,
 			*a.schedulerShipmentCloseTaskConfig,
-		)
+		))
 	}
 
 	if a.config.Reports().FinanceRoutes().IsEnabled() {
 		logger.Log(logger.INFO, "App.runTasks()", "Start schedule periodic for \"finance routes\" reporter")
-		a.scheduler.SchedulePeriodic(
+		a.wbLogisticTaskIDs = append(a.wbLogisticTaskIDs, a.schedule(
 			scheduler.NewCallbackTask("finance_routes_report", a.financeRoutesHandler),
 			a.config.Reports().FinanceRoutes().PollingInterval(),
+			a.schedulerFinanceRoutesSchedule,
 			*a.schedulerFinanceRoutesTaskConfig,
-		)
+		))
 	}
 
 	if a.config.Reports().FinanceDaily().IsEnabled() {
 		logger.Log(logger.INFO, "App.runTasks()", "Start schedule periodic for \"finance daily\" reporter")
-		a.scheduler.SchedulePeriodic(
+		a.wbLogisticTaskIDs = append(a.wbLogisticTaskIDs, a.schedule(
 			scheduler.NewCallbackTask("finance_daily_report", a.financeDailyHandler),
 			a.config.Reports().FinanceDaily().PollingInterval(),
+			a.schedulerFinanceDailySchedule,
 			*a.schedulerFinanceDailyTaskConfig,
-		)
+		))
+	}
+
+	if a.config.Reports().FinanceWeekly().IsEnabled() {
+		logger.Log(logger.INFO, "App.runTasks()", "Start schedule periodic for \"finance weekly\" reporter")
+		a.wbLogisticTaskIDs = append(a.wbLogisticTaskIDs, a.schedule(
+			scheduler.NewCallbackTask("finance_weekly_report", a.financeWeeklyHandler),
+			a.config.Reports().FinanceWeekly().PollingInterval(),
+			a.schedulerFinanceWeeklySchedule,
+			*a.schedulerFinanceWeeklyTaskConfig,
+		))
+	}
+
+	if a.config.Reports().FinanceMonthly().IsEnabled() {
+		logger.Log(logger.INFO, "App.runTasks()", "Start schedule periodic for \"finance monthly\" reporter")
+		a.wbLogisticTaskIDs = append(a.wbLogisticTaskIDs, a.schedule(
+			scheduler.NewCallbackTask("finance_monthly_report", a.financeMonthlyHandler),
+			a.config.Reports().FinanceMonthly().PollingInterval(),
+			a.schedulerFinanceMonthlySchedule,
+			*a.schedulerFinanceMonthlyTaskConfig,
+		))
+	}
+
+	if a.config.Reports().DriverPerformance().IsEnabled() {
+		logger.Log(logger.INFO, "App.runTasks()", "Start schedule periodic for \"driver performance\" reporter")
+		a.wbLogisticTaskIDs = append(a.wbLogisticTaskIDs, a.schedule(
+			scheduler.NewCallbackTask("driver_performance_report", a.driverPerformanceHandler),
+			a.config.Reports().DriverPerformance().PollingInterval(),
+			a.schedulerDriverPerformanceSchedule,
+			*a.schedulerDriverPerformanceTaskConfig,
+		))
+	}
+}
+
+// schedule Schedules the task by the calendar schedule if it is set, otherwise by the polling interval
+func (a *App) schedule(task scheduler.Task, interval time.Duration, schedule scheduler.Schedule, cfg scheduler.TaskConfig) uint64 {
+	var id uint64
+	if schedule != nil {
+		logger.Logf(logger.INFO, "App.schedule()", "Task '%s' runs by schedule %s", task.Name(), schedule)
+		id = a.scheduler.ScheduleCron(task, schedule, cfg)
+	} else {
+		id = a.scheduler.SchedulePeriodic(task, interval, cfg)
+	}
+
+	a.reportTasksMtx.Lock()
+	a.reportTasks = append(a.reportTasks, &reportTask{id: id, interval: interval, schedule: schedule, window: cfg.Window})
+	a.reportTasksMtx.Unlock()
+	return id
+}
+
+// runTelegramCommands Long polling blocks for a while, so it runs apart from the scheduler workers
+func (a *App) runTelegramCommands() {
+	if a.telegramCommands == nil {
+		return
+	}
+
+	logger.Log(logger.INFO, "App.runTelegramCommands()", "Start polling Telegram bot commands")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	a.telegramCommandsCancel = cancel
+	a.telegramCommandsDone = done
+
+	go func() {
+		defer close(done)
+		if err := a.telegramCommands.Run(ctx); err != nil {
+			logger.Logf(logger.ERROR, "App.runTelegramCommands()", "Failed to run Telegram bot commands: %v", err)
+		}
+	}()
+}
+
+func (a *App) stopTelegramCommands() {
+	if a.telegramCommandsCancel == nil {
+		return
+	}
+
+	logger.Log(logger.INFO, "App.stopTelegramCommands()", "Stop polling Telegram bot commands")
+	a.telegramCommandsCancel()
+	<-a.telegramCommandsDone
+	a.telegramCommandsCancel = nil
+	a.telegramCommandsDone = nil
+}
+
+// runOutbox Delivers the messages of the reports, it keeps running while the application is paused
+func (a *App) runOutbox() {
+	if a.services.Outbox == nil || a.outboxCancel != nil {
+		return
+	}
+
+	logger.Log(logger.INFO, "App.runOutbox()", "Start delivering outbox messages")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	a.outboxCancel = cancel
+	a.outboxDone = done
+
+	go func() {
+		defer close(done)
+		a.services.Outbox.Run(ctx)
+	}()
+}
+
+func (a *App) stopOutbox() {
+	if a.outboxCancel == nil {
+		return
 	}
+
+	logger.Log(logger.INFO, "App.stopOutbox()", "Stop delivering outbox messages")
+	a.outboxCancel()
+	<-a.outboxDone
+	a.outboxCancel = nil
+	a.outboxDone = nil
 }
 
 func (a *App) generalRoutesHandler(ctx context.Context) error {
@@ -157,7 +351,7 @@ func (a *App) generalRoutesHandler(ctx context.Context) error {
 		err := a.generalRoutesReporter.Run(ctx)
 		if err != nil {
 			logger.Log(logger.ERROR, "App.generalRoutesHandler()", "Failed to run general routes report")
-			a.checkAuthWBLogistic()
+			a.checkAuthWBLogistic(err)
 			return err
 		}
 	}
@@ -173,7 +367,7 @@ func (a *App) shipmentCloseHandler(ctx context.Context) error {
 		err := a.shipmentCloseReporter.Run(ctx)
 		if err != nil {
 			logger.Log(logger.ERROR, "App.shipmentCloseHandler()", "Failed to run shipment close report")
-			a.checkAuthWBLogistic()
+			a.checkAuthWBLogistic(err)
 			return err
 		}
 	}
@@ -189,7 +383,7 @@ func (a *App) financeRoutesHandler(ctx context.Context) error {
 		err := a.financeRoutesReporter.Run(ctx)
 		if err != nil {
 			logger.Log(logger.ERROR, "App.financeRoutesHandler()", "Failed to run finance routes report")
-			a.checkAuthWBLogistic()
+			a.checkAuthWBLogistic(err)
 			return err
 		}
 	}
@@ -205,30 +399,213 @@ func (a *App) financeDailyHandler(ctx context.Context) error {
 		err := a.financeDailyReporter.Run(ctx)
 		if err != nil {
 			logger.Log(logger.ERROR, "App.financeDailyHandler()", "Failed to run finance daily report")
-			a.checkAuthWBLogistic()
+			a.checkAuthWBLogistic(err)
 			return err
 		}
 	}
 	return nil
 }
 
-func (a *App) checkAuthWBLogistic() {
-	if a.services.WBLogisticService.IsSessionExpired() {
-		logger.Log(logger.ERROR, "App.checkAuthWBLogistic()", "WB logistic session expired")
-		go func() {
-			a.Pause()
+func (a *App) financeWeeklyHandler(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		logger.Log(logger.ERROR, "App.financeWeeklyHandler()", "Cancelling 'finance_weekly_report' callback task")
+		return ctx.Err()
+	default:
+		err := a.financeWeeklyReporter.Run(ctx)
+		if err != nil {
+			logger.Log(logger.ERROR, "App.financeWeeklyHandler()", "Failed to run finance weekly report")
+			a.checkAuthWBLogistic(err)
+			return err
+		}
+	}
+	return nil
+}
 
-			err := a.initializer.InitDirectWBLogistic()
-			if err != nil {
-				logger.Logf(logger.ERROR, "App.checkAuthWBLogistic()", "failed to init direct wb logistic: %v", err)
-				return
-			}
+func (a *App) financeMonthlyHandler(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		logger.Log(logger.ERROR, "App.financeMonthlyHandler()", "Cancelling 'finance_monthly_report' callback task")
+		return ctx.Err()
+	default:
+		err := a.financeMonthlyReporter.Run(ctx)
+		if err != nil {
+			logger.Log(logger.ERROR, "App.financeMonthlyHandler()", "Failed to run finance monthly report")
+			a.checkAuthWBLogistic(err)
+			return err
+		}
+	}
+	return nil
+}
 
-			err = a.Start()
-			if err != nil {
-				logger.Logf(logger.ERROR, "App.checkAuthWBLogistic()", "failed to start app: %v", err)
-				return
-			}
-		}()
+func (a *App) driverPerformanceHandler(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		logger.Log(logger.ERROR, "App.driverPerformanceHandler()", "Cancelling 'driver_performance_report' callback task")
+		return ctx.Err()
+	default:
+		err := a.driverPerformanceReporter.Run(ctx)
+		if err != nil {
+			logger.Log(logger.ERROR, "App.driverPerformanceHandler()", "Failed to run driver performance report")
+			a.checkAuthWBLogistic(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// onCircuitStateChange Notifies the alerts chat of Telegram bot about the report which stopped or resumed running
+func (a *App) onCircuitStateChange(task scheduler.Task, from, to scheduler.CircuitState) {
+	logger.Logf(logger.WARN, "App.onCircuitStateChange()", "Circuit breaker of task '%s' changed %s -> %s", task.Name(), from, to)
+
+	chatID := a.config.Telegram().Alerts().ChatID()
+	if a.services.Outbox == nil || chatID == 0 || to == scheduler.CircuitHalfOpen {
+		return
+	}
+
+	message := "Отчет <b>" + html.EscapeString(task.Name()) + "</b> остановлен после повторяющихся ошибок"
+	if to == scheduler.CircuitClosed {
+		message = "Отчет <b>" + html.EscapeString(task.Name()) + "</b> снова работает"
+	}
+	err := a.services.Outbox.Enqueue(&outbox.Message{Destination: chatID, Text: message, ParseMode: "HTML", Source: "CircuitBreaker"})
+	if err != nil {
+		logger.Logf(logger.ERROR, "App.onCircuitStateChange()", "Failed to enqueue message to chat %d: %v", chatID, err)
+	}
+}
+
+// checkAuthWBLogistic Pauses the reports which use WB logistic until the session is restored, the other tasks keep running.
+// The session is restored in the Telegram admin chat if it is set, otherwise by the init prompter.
+// The session is restored if the error of the report is unauthorized or the session token is expired.
+// Returns false if the session is valid or already being restored
+func (a *App) checkAuthWBLogistic(err error) bool {
+	if !services.IsWBLogisticUnauthorized(err) && !a.services.WBLogisticService.IsSessionExpired() {
+		return false
+	}
+	logger.Log(logger.ERROR, "App.checkAuthWBLogistic()", "WB logistic session expired or rejected")
+	return a.reauthWBLogistic()
+}
+
+// reauthWBLogistic Restores the session even if it looks valid, e.g. by /auth after the failed attempt left the reports paused.
+// Returns false if the session is already being restored
+func (a *App) reauthWBLogistic() bool {
+	if !a.isReauthWBLogistic.CompareAndSwap(false, true) {
+		return false // already restoring
+	}
+
+	go func() {
+		defer a.isReauthWBLogistic.Store(false)
+		// the reports stay paused after the failed attempt, so they are resumed by the next one
+		a.wbLogisticTaskIDsMtx.Lock()
+		taskIDs := slices.Clone(a.wbLogisticTaskIDs)
+		a.wbLogisticTaskIDsMtx.Unlock()
+		paused := a.pauseTasks(taskIDs)
+		a.reauthPausedTaskIDsMtx.Lock()
+		a.reauthPausedTaskIDs = append(a.reauthPausedTaskIDs, paused...)
+		a.reauthPausedTaskIDsMtx.Unlock()
+
+		var err error
+		if a.wbAuthPrompter != nil {
+			err = a.initializer.InitDirectWBLogisticWith(a.wbAuthPrompter)
+		} else {
+			err = a.initializer.InitDirectWBLogistic()
+		}
+		if err != nil {
+			logger.Logf(logger.ERROR, "App.reauthWBLogistic()", "failed to init direct wb logistic, reports stay paused: %v", err)
+			return
+		}
+
+		a.reauthPausedTaskIDsMtx.Lock()
+		paused = a.reauthPausedTaskIDs
+		a.reauthPausedTaskIDs = nil
+		a.reauthPausedTaskIDsMtx.Unlock()
+		a.resumeTasks(paused)
+	}()
+	return true
+}
+
+// clearReauthPausedTasks The ids of the tasks are not valid after the scheduler is reset
+func (a *App) clearReauthPausedTasks() {
+	a.reauthPausedTaskIDsMtx.Lock()
+	a.reauthPausedTaskIDs = nil
+	a.reauthPausedTaskIDsMtx.Unlock()
+}
+
+// pauseTasks Pauses the tasks and returns ids of the tasks which were not paused before
+func (a *App) pauseTasks(ids []uint64) []uint64 {
+	isPaused := make(map[uint64]bool)
+	for _, state := range a.scheduler.List() {
+		isPaused[state.ID] = state.IsPaused
+	}
+
+	paused := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if isPaused[id] {
+			continue // paused by the user, stays paused
+		}
+		if err := a.scheduler.Pause(id); err != nil {
+			logger.Logf(logger.ERROR, "App.pauseTasks()", "failed to pause task %d: %v", id, err)
+			continue
+		}
+		paused = append(paused, id)
+	}
+	return paused
+}
+
+func (a *App) resumeTasks(ids []uint64) {
+	for _, id := range ids {
+		if err := a.scheduler.Resume(id); err != nil {
+			logger.Logf(logger.ERROR, "App.resumeTasks()", "failed to resume task %d: %v", id, err)
+		}
+	}
+}
+
+// newInitPrompter The console prompter, or in the headless mode the prompter which never reads stdin with the delegate from the config
+func newInitPrompter(cfg *config.Config, storage storage.Storage, isHeadless bool) (prompters.InitializeAppPrompter, prompters.HeadlessDelegate, error) {
+	if !isHeadless {
+		return &prompters.CLIInitAppPrompter{}, nil, nil
+	}
+
+	var delegate prompters.HeadlessDelegate
+	switch cfg.Headless().Delegate() {
+	case config.HeadlessDelegateHTTP:
+		delegate = prompters.NewHTTPDelegate(cfg.Headless().Address())
+	case config.HeadlessDelegateTelegram:
+		token := storage.ConfigStore().GetTelegramBotToken()
+		if token == "" {
+			return nil, nil, errors.New("App.newInitPrompter()", "Telegram bot token is missing from the storage, the headless delegate 'telegram' can not be used")
+		}
+		delegate = prompters.NewTelegramDelegate(token, cfg.Headless().TelegramChatID())
+	}
+	logger.Logf(logger.INFO, "App.newInitPrompter()", "Headless mode, the interactive steps are delegated to '%s'", cfg.Headless().Delegate())
+
+	login := storage.ConfigStore().GetWBLogisticLogin()
+	return prompters.NewHeadlessInitAppPrompter(delegate, login, cfg.Headless().Timeout()), delegate, nil
+}
+
+func closeHeadlessDelegate(delegate prompters.HeadlessDelegate) {
+	if delegate == nil {
+		return
+	}
+	if err := delegate.Close(); err != nil {
+		logger.Logf(logger.WARN, "App.closeHeadlessDelegate()", "Failed to close headless delegate: %v", err)
+	}
+}
+
+func loadStorage(cfg *config.Config) (storage.Storage, error) {
+	fileStorage, err := storage.NewFileStorage(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "App.loadStorage()", "Failed to create storage")
+	}
+
+	storagePassword := os.Getenv(cfg.Storage().Env())
+	if storagePassword == "" {
+		return nil, errors.New("App.loadStorage()", "Storage key is missing from the system")
+	}
+	fileStorage.SetEncrypt([]byte(storagePassword))
+
+	err = fileStorage.Load(cfg.Storage().Path())
+	if err != nil {
+		return nil, errors.Wrap(err, "App.loadStorage()", "Failed to load storage")
 	}
+	return fileStorage, nil
 }
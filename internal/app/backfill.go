@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"time"
+	"wb_logistic_assistant/internal/config"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/initializer"
+	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/reporters"
+	"wb_logistic_assistant/internal/services"
+	"wb_logistic_assistant/internal/storage"
+)
+
+const (
+	BackfillReportFinanceDaily = "finance-daily"
+
+	BackfillOutputTelegramBot  = "telegram"
+	BackfillOutputGoogleSheets = "sheets"
+	BackfillOutputCSV          = "csv"
+)
+
+type BackfillParams struct {
+	Report        string
+	From          time.Time
+	To            time.Time
+	Output        string
+	ChatID        int64 // if 0, chat of the report from the configuration
+	SpreadsheetID string
+	SheetName     string
+	CSVPath       string
+}
+
+// Backfill One-shot recomputation of reports over a date range, without the scheduler loop
+type Backfill struct {
+	config     *config.Config
+	params     *BackfillParams
+	storage    storage.Storage
+	services   *services.Container
+	backfiller reporters.Backfiller
+	isHeadless bool
+}
+
+func NewBackfill(config *config.Config, params *BackfillParams) *Backfill {
+	return &Backfill{config: config, params: params}
+}
+
+// SetHeadless In the headless mode stdin is never read, must be called before Init
+func (b *Backfill) SetHeadless(isHeadless bool) {
+	b.isHeadless = isHeadless
+}
+
+func (b *Backfill) Init() error {
+	logger.Log(logger.INFO, "Backfill.Init()", "Start init backfill")
+
+	if err := b.validateParams(); err != nil {
+		return errors.Wrap(err, "Backfill.Init()", "")
+	}
+
+	storage, err := loadStorage(b.config)
+	if err != nil {
+		return errors.Wrap(err, "Backfill.Init()", "")
+	}
+
+	prompter, delegate, err := newInitPrompter(b.config, storage, b.isHeadless)
+	if err != nil {
+		return errors.Wrap(err, "Backfill.Init()", "")
+	}
+	defer closeHeadlessDelegate(delegate)
+
+	dependencies, err := initializer.NewInitializer(b.config, storage, prompter).InitBackfill(
+		b.params.Output == BackfillOutputTelegramBot,
+		b.params.Output == BackfillOutputGoogleSheets,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Backfill.Init()", "Failed to init backfill dependencies")
+	}
+
+	b.storage = storage
+	b.services = dependencies.Services
+	b.backfiller = dependencies.FinanceDailyBackfiller
+
+	logger.Log(logger.INFO, "Backfill.Init()", "Init backfill successfully")
+	return nil
+}
+
+func (b *Backfill) Run(ctx context.Context) (err error) {
+	output, err := b.newOutput()
+	if err != nil {
+		return errors.Wrap(err, "Backfill.Run()", "")
+	}
+	defer func() {
+		if closeErr := output.Close(); closeErr != nil && err == nil {
+			err = errors.Wrap(closeErr, "Backfill.Run()", "failed close output")
+		}
+	}()
+
+	logger.Logf(logger.INFO, "Backfill.Run()", "Start backfill %s %s - %s to %s", b.params.Report, b.params.From.Format(time.DateOnly), b.params.To.Format(time.DateOnly), b.params.Output)
+	if err = b.backfiller.Backfill(ctx, b.params.From, b.params.To, output); err != nil {
+		return errors.Wrap(err, "Backfill.Run()", "")
+	}
+	logger.Log(logger.INFO, "Backfill.Run()", "Finish backfill")
+	return nil
+}
+
+func (b *Backfill) Stop() {
+	if b.storage == nil {
+		return
+	}
+
+	// the session could be refreshed while backfilling
+	err := b.storage.Save(b.config.Storage().Path())
+	if err != nil {
+		logger.Logf(logger.ERROR, "Backfill.Stop()", "Failed to save storage: %v", err)
+	}
+
+	b.storage.SetEncrypt([]byte("")) // clear password
+	b.storage.Clear()
+}
+
+func (b *Backfill) validateParams() error {
+	if b.params == nil {
+		return errors.New("Backfill.validateParams()", "params is nil")
+	}
+	if b.params.Report != BackfillReportFinanceDaily {
+		return errors.Newf("Backfill.validateParams()", "unknown report '%s'", b.params.Report)
+	}
+	if b.params.From.IsZero() || b.params.To.IsZero() {
+		return errors.New("Backfill.validateParams()", "'from' and 'to' are required")
+	}
+	if b.params.To.Before(b.params.From) {
+		return errors.New("Backfill.validateParams()", "'to' must not be before 'from'")
+	}
+
+	switch b.params.Output {
+	case BackfillOutputTelegramBot:
+	case BackfillOutputGoogleSheets:
+		if b.params.SpreadsheetID == "" || b.params.SheetName == "" {
+			return errors.New("Backfill.validateParams()", "spreadsheet id and sheet name are required for Google Sheets output")
+		}
+	case BackfillOutputCSV:
+		if b.params.CSVPath == "" {
+			return errors.New("Backfill.validateParams()", "file path is required for CSV output")
+		}
+	default:
+		return errors.Newf("Backfill.validateParams()", "unknown output '%s'", b.params.Output)
+	}
+	return nil
+}
+
+func (b *Backfill) newOutput() (reporters.ReportOutput, error) {
+	switch b.params.Output {
+	case BackfillOutputTelegramBot:
+		chatID := b.params.ChatID
+		if chatID == 0 {
+			chatID = b.config.Telegram().FinanceDaily().ChatID()
+		}
+		return reporters.NewTelegramBotReportOutput(b.services.TelegramBotService, chatID), nil
+	case BackfillOutputGoogleSheets:
+		return reporters.NewGoogleSheetsReportOutput(b.services.GoogleSheetsService, b.params.SpreadsheetID, b.params.SheetName), nil
+	case BackfillOutputCSV:
+		output, err := reporters.NewCSVReportOutput(b.params.CSVPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "Backfill.newOutput()", "")
+		}
+		return output, nil
+	}
+	return nil, errors.Newf("Backfill.newOutput()", "unknown output '%s'", b.params.Output)
+}
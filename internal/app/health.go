@@ -0,0 +1,183 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/scheduler"
+)
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+)
+
+// reportTask Scheduled report whose freshness is checked by the health endpoints
+type reportTask struct {
+	id       uint64
+	interval time.Duration      // periodic task
+	schedule scheduler.Schedule // cron task, if set the interval is ignored
+	window   *scheduler.TimeWindow
+}
+
+type healthReport struct {
+	Status    string             `json:"status"`
+	Time      time.Time          `json:"time"`
+	WBSession *healthWBSession   `json:"wb_session,omitempty"`
+	Services  map[string]bool    `json:"services,omitempty"` // service -> initialized, the services which are not needed by the config are not initialized
+	Tasks     []*healthTaskState `json:"tasks"`
+	Problems  []string           `json:"problems,omitempty"`
+}
+
+type healthWBSession struct {
+	IsValid   bool      `json:"valid"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+type healthTaskState struct {
+	ID          uint64    `json:"id"`
+	Name        string    `json:"name"`
+	IsRunning   bool      `json:"running"`
+	IsPaused    bool      `json:"paused"`
+	LastSuccess time.Time `json:"last_success,omitzero"`
+	LastError   string    `json:"last_error,omitempty"`
+	Deadline    time.Time `json:"deadline,omitzero"` // the report is stale if it has not succeeded until this time
+	IsStale     bool      `json:"stale"`
+}
+
+// handleHealth Liveness: fails if any enabled report has not succeeded within the stale intervals, e.g. the process is wedged
+func (a *App) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	report := a.healthReport(false)
+	writeHealthReport(w, report)
+}
+
+// handleReady Readiness: in addition to the liveness, fails while the WB session is expired or being restored
+func (a *App) handleReady(w http.ResponseWriter, _ *http.Request) {
+	report := a.healthReport(true)
+	writeHealthReport(w, report)
+}
+
+func (a *App) healthReport(isReadiness bool) *healthReport {
+	now := time.Now()
+	report := &healthReport{
+		Status: healthStatusOK,
+		Time:   now,
+		Tasks:  a.healthTasks(now),
+	}
+
+	for _, task := range report.Tasks {
+		if task.IsStale {
+			report.Problems = append(report.Problems, "report '"+task.Name+"' has not succeeded since "+task.lastSuccessString())
+		}
+	}
+
+	if isReadiness {
+		report.Services = map[string]bool{
+			"wb_logistic":   a.services.WBLogisticService != nil,
+			"google_sheets": a.services.GoogleSheetsService != nil,
+			"telegram_bot":  a.services.TelegramBotService != nil,
+			"outbox":        a.services.Outbox != nil,
+		}
+
+		if a.services.WBLogisticService != nil {
+			report.WBSession = &healthWBSession{
+				IsValid:   !a.services.WBLogisticService.IsSessionExpired(),
+				ExpiresAt: a.services.WBLogisticService.SessionTokenExpiresAt(),
+			}
+			if !report.WBSession.IsValid {
+				report.Problems = append(report.Problems, "WB logistic session is expired")
+			}
+		}
+		if a.isReauthWBLogistic.Load() {
+			report.Problems = append(report.Problems, "WB logistic session is being restored")
+		}
+	}
+
+	if len(report.Problems) > 0 {
+		report.Status = healthStatusFail
+	}
+	return report
+}
+
+// healthTasks States of the scheduled reports, the paused reports are never stale
+func (a *App) healthTasks(now time.Time) []*healthTaskState {
+	a.reportTasksMtx.RLock()
+	reportTasks := make(map[uint64]*reportTask, len(a.reportTasks))
+	for _, task := range a.reportTasks {
+		reportTasks[task.id] = task
+	}
+	a.reportTasksMtx.RUnlock()
+
+	staleIntervals := a.config.HTTPServer().StaleIntervals()
+	list := make([]*healthTaskState, 0, len(reportTasks))
+	for _, state := range a.scheduler.List() {
+		task, ok := reportTasks[state.ID]
+		if !ok {
+			continue
+		}
+
+		taskState := &healthTaskState{
+			ID:          state.ID,
+			Name:        state.Name,
+			IsRunning:   state.IsRunning,
+			IsPaused:    state.IsPaused,
+			LastSuccess: state.LastSuccess,
+		}
+		if state.LastError != nil {
+			taskState.LastError = state.LastError.Error()
+		}
+		if !state.IsPaused && task.window.Contains(now) {
+			taskState.Deadline = task.deadline(state, now, staleIntervals)
+			taskState.IsStale = !taskState.Deadline.IsZero() && now.After(taskState.Deadline)
+		}
+		list = append(list, taskState)
+	}
+	return list
+}
+
+// deadline The time of the stale intervals after the last success, the schedule time or the opening of the current window.
+// Zero if the cron schedule has no more runs
+func (t *reportTask) deadline(state scheduler.TaskState, now time.Time, staleIntervals int) time.Time {
+	since := state.ScheduledAt
+	if state.LastSuccess.After(since) {
+		since = state.LastSuccess
+	}
+	if start := t.window.Start(now); start.After(since) {
+		since = start // the runs are skipped outside the window
+	}
+
+	if t.schedule == nil {
+		return since.Add(time.Duration(staleIntervals) * t.interval)
+	}
+
+	deadline := since
+	for i := 0; i < staleIntervals; i++ {
+		deadline = t.schedule.Next(deadline)
+		if deadline.IsZero() {
+			return time.Time{}
+		}
+	}
+	return deadline
+}
+
+func (t *healthTaskState) lastSuccessString() string {
+	if t.LastSuccess.IsZero() {
+		return "start"
+	}
+	return t.LastSuccess.Format(time.RFC3339)
+}
+
+func writeHealthReport(w http.ResponseWriter, report *healthReport) {
+	code := http.StatusOK
+	if report.Status != healthStatusOK {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logger.Logf(logger.ERROR, "App.writeHealthReport()", "failed write health report: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/metrics"
+)
+
+const (
+	httpServerReadHeaderTimeout = 5 * time.Second
+	httpServerShutdownTimeout   = 5 * time.Second
+)
+
+// newHTTPHandler Routes of the service endpoints
+func (a *App) newHTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(metrics.Default))
+	mux.HandleFunc("GET /healthz", a.handleHealth)
+	mux.HandleFunc("GET /readyz", a.handleReady)
+	return mux
+}
+
+// runHTTPServer Serves the service endpoints if the server is enabled, it keeps running while the application is paused
+func (a *App) runHTTPServer() error {
+	cfg := a.config.HTTPServer()
+	if !cfg.IsEnabled() || a.httpServer != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", cfg.Address())
+	if err != nil {
+		return errors.Wrapf(err, "App.runHTTPServer()", "failed listen address %s", cfg.Address())
+	}
+
+	server := &http.Server{
+		Handler:           a.newHTTPHandler(),
+		ReadHeaderTimeout: httpServerReadHeaderTimeout,
+	}
+	a.httpServer = server
+
+	logger.Logf(logger.INFO, "App.runHTTPServer()", "Start HTTP server on %s", listener.Addr())
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Logf(logger.ERROR, "App.runHTTPServer()", "HTTP server failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (a *App) stopHTTPServer() {
+	if a.httpServer == nil {
+		return
+	}
+
+	logger.Log(logger.INFO, "App.stopHTTPServer()", "Stop HTTP server")
+	ctx, cancel := context.WithTimeout(context.Background(), httpServerShutdownTimeout)
+	defer cancel()
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		logger.Logf(logger.ERROR, "App.stopHTTPServer()", "failed shutdown HTTP server: %v", err)
+	}
+	a.httpServer = nil
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPHandlerMetrics(t *testing.T) {
+	server := httptest.NewServer((&App{}).newHTTPHandler())
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status %d, want 200", res.StatusCode)
+	}
+	if !strings.Contains(string(body), "# TYPE wb_assistant_scheduler_task_runs_total counter\n") {
+		t.Fatalf("scheduler metrics are not exposed:\n%s", body)
+	}
+
+	res, err = http.Get(server.URL + "/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown path: status %d, want 404", res.StatusCode)
+	}
+}
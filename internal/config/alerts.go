@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AlertsSourceGeneralRoutes = "general_routes"
+	AlertsSourceFinanceDaily  = "finance_daily"
+)
+
+type Alerts struct {
+	isEnabled bool          // ro
+	rules     []*AlertsRule // ro
+}
+
+type alerts struct {
+	IsEnabled bool          `json:"enabled"`
+	Rules     []*AlertsRule `json:"rules"`
+}
+
+func newAlerts() *Alerts {
+	return &Alerts{
+		isEnabled: false,           // default
+		rules:     []*AlertsRule{}, // default
+	}
+}
+
+func (a *Alerts) IsEnabled() bool      { return a.isEnabled }
+func (a *Alerts) Rules() []*AlertsRule { return a.rules }
+
+func (a *Alerts) UnmarshalJSON(b []byte) error {
+	temp := &alerts{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	a.isEnabled = temp.IsEnabled
+	a.rules = temp.Rules
+	if a.rules == nil {
+		a.rules = []*AlertsRule{}
+	}
+	return nil
+}
+
+func (a *Alerts) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&alerts{
+		IsEnabled: a.isEnabled,
+		Rules:     a.rules,
+	})
+}
+
+// AlertsRule Single condition over a metric of the report data, e.g. remains_barcodes > 500 for 30m
+type AlertsRule struct {
+	name      string  // ro
+	source    string  // ro
+	metric    string  // ro
+	operator  string  // ro
+	value     float64 // ro
+	routes    []int   // ro
+	routesMap map[int]struct{}
+	duration  time.Duration // ro
+	cooldown  time.Duration // ro
+	isResolve bool          // ro
+}
+
+type alertsRule struct {
+	Name      string        `json:"name"`
+	Source    string        `json:"source"`
+	Metric    string        `json:"metric"`
+	Operator  string        `json:"operator"`
+	Value     float64       `json:"value"`
+	Routes    []int         `json:"routes"`
+	Duration  time.Duration `json:"for"`
+	Cooldown  time.Duration `json:"cooldown"`
+	IsResolve *bool         `json:"resolve"`
+}
+
+func (a *AlertsRule) Name() string     { return a.name }
+func (a *AlertsRule) Source() string   { return a.source }
+func (a *AlertsRule) Metric() string   { return a.metric }
+func (a *AlertsRule) Operator() string { return a.operator }
+func (a *AlertsRule) Value() float64   { return a.value }
+
+// Routes Routes checked by the rule, empty means all routes
+func (a *AlertsRule) Routes() []int               { return a.routes }
+func (a *AlertsRule) RoutesMap() map[int]struct{} { return a.routesMap }
+
+// Duration Time the condition must hold before the alert is fired
+func (a *AlertsRule) Duration() time.Duration { return a.duration }
+
+// Cooldown Minimal time between two fired alerts of the rule for the same route
+func (a *AlertsRule) Cooldown() time.Duration { return a.cooldown }
+
+// IsResolve Send message when the condition is no longer met
+func (a *AlertsRule) IsResolve() bool { return a.isResolve }
+
+func (a *AlertsRule) UnmarshalJSON(b []byte) error {
+	temp := &alertsRule{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	a.name = temp.Name
+	a.source = temp.Source
+	a.metric = temp.Metric
+	a.operator = temp.Operator
+	a.value = temp.Value
+	a.routes = temp.Routes
+	if a.routes == nil {
+		a.routes = []int{}
+	}
+	a.routesMap = sliceToSetInt(a.routes)
+	a.duration = temp.Duration * reportsTimePeriod
+	a.cooldown = temp.Cooldown * reportsTimePeriod
+	a.isResolve = true // default
+	if temp.IsResolve != nil {
+		a.isResolve = *temp.IsResolve
+	}
+	return nil
+}
+
+func (a *AlertsRule) MarshalJSON() ([]byte, error) {
+	isResolve := a.isResolve
+	return json.Marshal(&alertsRule{
+		Name:      a.name,
+		Source:    a.source,
+		Metric:    a.metric,
+		Operator:  a.operator,
+		Value:     a.value,
+		Routes:    a.routes,
+		Duration:  a.duration / reportsTimePeriod,
+		Cooldown:  a.cooldown / reportsTimePeriod,
+		IsResolve: &isResolve,
+	})
+}
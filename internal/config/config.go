@@ -14,6 +14,12 @@ type Config struct {
 	logistic     *Logistic     // ro
 	googleSheets *GoogleSheets // ro
 	telegram     *TelegramBot  // ro
+	history      *History      // ro
+	alerts       *Alerts       // ro
+	scheduler    *Scheduler    // ro
+	outbox       *Outbox       // ro
+	httpServer   *HTTPServer   // ro
+	headless     *Headless     // ro
 }
 
 type config struct {
@@ -23,6 +29,12 @@ type config struct {
 	Logistic     *Logistic     `json:"logistic"`
 	GoogleSheets *GoogleSheets `json:"google_sheets"`
 	Telegram     *TelegramBot  `json:"telegram_bot"`
+	History      *History      `json:"history"`
+	Alerts       *Alerts       `json:"alerts"`
+	Scheduler    *Scheduler    `json:"scheduler"`
+	Outbox       *Outbox       `json:"outbox"`
+	HTTPServer   *HTTPServer   `json:"http_server"`
+	Headless     *Headless     `json:"headless"`
 }
 
 func NewConfigFile(filePath string) (*Config, error) {
@@ -34,6 +46,12 @@ func NewConfigFile(filePath string) (*Config, error) {
 		logistic:     newLogistic(),     // default
 		googleSheets: newGoogleSheets(), // default
 		telegram:     newTelegramBot(),  // default
+		history:      newHistory(),      // default
+		alerts:       newAlerts(),       // default
+		scheduler:    newScheduler(),    // default
+		outbox:       newOutbox(),       // default
+		httpServer:   newHTTPServer(),   // default
+		headless:     newHeadless(),     // default
 	}
 
 	file, err := os.Open(filePath)
@@ -62,6 +80,12 @@ func (c *Config) Storage() *Storage           { return c.storage }
 func (c *Config) Logistic() *Logistic         { return c.logistic }
 func (c *Config) GoogleSheets() *GoogleSheets { return c.googleSheets }
 func (c *Config) Telegram() *TelegramBot      { return c.telegram }
+func (c *Config) History() *History           { return c.history }
+func (c *Config) Alerts() *Alerts             { return c.alerts }
+func (c *Config) Scheduler() *Scheduler       { return c.scheduler }
+func (c *Config) Outbox() *Outbox             { return c.outbox }
+func (c *Config) HTTPServer() *HTTPServer     { return c.httpServer }
+func (c *Config) Headless() *Headless         { return c.headless }
 
 func (c *Config) UnmarshalJSON(b []byte) error {
 	temp := &config{}
@@ -69,12 +93,32 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return err
 	}
-	c.debug = temp.Debug
+	if temp.Debug != nil {
+		c.debug = temp.Debug
+	}
 	c.reports = temp.Reports
 	c.storage = temp.Storage
 	c.googleSheets = temp.GoogleSheets
 	c.logistic = temp.Logistic
 	c.telegram = temp.Telegram
+	if temp.History != nil {
+		c.history = temp.History
+	}
+	if temp.Alerts != nil {
+		c.alerts = temp.Alerts
+	}
+	if temp.Scheduler != nil {
+		c.scheduler = temp.Scheduler
+	}
+	if temp.Outbox != nil {
+		c.outbox = temp.Outbox
+	}
+	if temp.HTTPServer != nil {
+		c.httpServer = temp.HTTPServer
+	}
+	if temp.Headless != nil {
+		c.headless = temp.Headless
+	}
 	return nil
 }
 
@@ -86,5 +130,11 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 		Logistic:     c.logistic,
 		GoogleSheets: c.googleSheets,
 		Telegram:     c.telegram,
+		History:      c.history,
+		Alerts:       c.alerts,
+		Scheduler:    c.scheduler,
+		Outbox:       c.outbox,
+		HTTPServer:   c.httpServer,
+		Headless:     c.headless,
 	})
 }
@@ -1,27 +1,53 @@
 package config
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Debug struct {
-	enabled bool
-	path    string
+	enabled      bool
+	path         string
+	level        string
+	consoleLevel string
+	format       string
+	rotation     *DebugRotation
 }
 
 type debug struct {
-	Enabled bool   `json:"enabled"`
-	Path    string `json:"path"`
+	Enabled      bool           `json:"enabled"`
+	Path         string         `json:"path"`
+	Level        string         `json:"level"`
+	ConsoleLevel string         `json:"console_level"`
+	Format       string         `json:"format"`
+	Rotation     *DebugRotation `json:"rotation"`
 }
 
 func newDebug() *Debug {
 	return &Debug{
-		enabled: false,
-		path:    "debug.txt",
+		enabled:      false,
+		path:         "debug.txt",
+		level:        "DEBUG",
+		consoleLevel: "DEBUG",
+		format:       "text",
+		rotation:     newDebugRotation(),
 	}
 }
 
 func (d *Debug) Enabled() bool { return d.enabled }
 func (d *Debug) Path() string  { return d.path }
 
+// Level Minimum level of the entries written to the file: DEBUG, INFO, WARN, ERROR or FATAL
+func (d *Debug) Level() string { return d.level }
+
+// ConsoleLevel Minimum level of the entries written to stdout when debug is enabled
+func (d *Debug) ConsoleLevel() string { return d.consoleLevel }
+
+// Format Encoding of the entries: text or json
+func (d *Debug) Format() string { return d.format }
+
+func (d *Debug) Rotation() *DebugRotation { return d.rotation }
+
 func (d *Debug) UnmarshalJSON(b []byte) error {
 	temp := &debug{}
 	err := json.Unmarshal(b, temp)
@@ -30,12 +56,90 @@ func (d *Debug) UnmarshalJSON(b []byte) error {
 	}
 	d.enabled = temp.Enabled
 	d.path = temp.Path
+	d.level = "DEBUG"
+	if temp.Level != "" {
+		d.level = temp.Level
+	}
+	d.consoleLevel = "DEBUG"
+	if temp.ConsoleLevel != "" {
+		d.consoleLevel = temp.ConsoleLevel
+	}
+	d.format = "text"
+	if temp.Format != "" {
+		d.format = temp.Format
+	}
+	d.rotation = newDebugRotation()
+	if temp.Rotation != nil {
+		d.rotation = temp.Rotation
+	}
 	return nil
 }
 
 func (d *Debug) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&debug{
-		Enabled: d.enabled,
-		Path:    d.path,
+		Enabled:      d.enabled,
+		Path:         d.path,
+		Level:        d.level,
+		ConsoleLevel: d.consoleLevel,
+		Format:       d.format,
+		Rotation:     d.rotation,
+	})
+}
+
+// DebugRotation Rotation of the debug file by the size and the time with the retention of the rotated files
+type DebugRotation struct {
+	maxSizeMB  int           // ro
+	interval   time.Duration // ro
+	maxBackups int           // ro
+	maxAgeDays int           // ro
+}
+
+type debugRotation struct {
+	MaxSizeMB  int           `json:"max_size_mb"`
+	Interval   time.Duration `json:"interval"`
+	MaxBackups int           `json:"max_backups"`
+	MaxAgeDays int           `json:"max_age_days"`
+}
+
+func newDebugRotation() *DebugRotation {
+	return &DebugRotation{
+		maxSizeMB:  50,                             // default
+		interval:   86_400_000 * reportsTimePeriod, // default
+		maxBackups: 7,                              // default
+		maxAgeDays: 30,                             // default
+	}
+}
+
+// MaxSizeMB If 0, the file is not rotated by the size
+func (d *DebugRotation) MaxSizeMB() int { return d.maxSizeMB }
+
+// Interval If 0, the file is not rotated by the time
+func (d *DebugRotation) Interval() time.Duration { return d.interval }
+
+// MaxBackups If 0, all rotated files are kept
+func (d *DebugRotation) MaxBackups() int { return d.maxBackups }
+
+// MaxAgeDays If 0, the rotated files are not removed by the age
+func (d *DebugRotation) MaxAgeDays() int { return d.maxAgeDays }
+
+func (d *DebugRotation) UnmarshalJSON(b []byte) error {
+	temp := &debugRotation{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	d.maxSizeMB = temp.MaxSizeMB
+	d.interval = temp.Interval * reportsTimePeriod
+	d.maxBackups = temp.MaxBackups
+	d.maxAgeDays = temp.MaxAgeDays
+	return nil
+}
+
+func (d *DebugRotation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&debugRotation{
+		MaxSizeMB:  d.maxSizeMB,
+		Interval:   d.interval / reportsTimePeriod,
+		MaxBackups: d.maxBackups,
+		MaxAgeDays: d.maxAgeDays,
 	})
 }
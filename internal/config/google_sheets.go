@@ -91,24 +91,40 @@ func (s *GoogleSheetsClient) MarshalJSON() ([]byte, error) {
 }
 
 type GoogleSheetsReportSheets struct {
-	generalRoutes *GoogleSheetsReportSheet // ro
-	shipmentClose *GoogleSheetsReportSheet // ro
+	generalRoutes     *GoogleSheetsReportSheet // ro
+	shipmentClose     *GoogleSheetsReportSheet // ro
+	financeWeekly     *GoogleSheetsReportSheet // ro
+	financeMonthly    *GoogleSheetsReportSheet // ro
+	driverPerformance *GoogleSheetsReportSheet // ro
 }
 
 type googleSheetsSheetsData struct {
-	GeneralRoutes *GoogleSheetsReportSheet `json:"general_routes"`
-	ShipmentClose *GoogleSheetsReportSheet `json:"shipment_close"`
+	GeneralRoutes     *GoogleSheetsReportSheet `json:"general_routes"`
+	ShipmentClose     *GoogleSheetsReportSheet `json:"shipment_close"`
+	FinanceWeekly     *GoogleSheetsReportSheet `json:"finance_weekly"`
+	FinanceMonthly    *GoogleSheetsReportSheet `json:"finance_monthly"`
+	DriverPerformance *GoogleSheetsReportSheet `json:"driver_performance"`
 }
 
 func newGoogleSheetsReportSheets() *GoogleSheetsReportSheets {
 	return &GoogleSheetsReportSheets{
-		generalRoutes: newGoogleSheetsReportSheet(), // default
-		shipmentClose: newGoogleSheetsReportSheet(), // default
+		generalRoutes:     newGoogleSheetsReportSheet(), // default
+		shipmentClose:     newGoogleSheetsReportSheet(), // default
+		financeWeekly:     newGoogleSheetsReportSheet(), // default
+		financeMonthly:    newGoogleSheetsReportSheet(), // default
+		driverPerformance: newGoogleSheetsReportSheet(), // default
 	}
 }
 
 func (s *GoogleSheetsReportSheets) GeneralRoutes() *GoogleSheetsReportSheet { return s.generalRoutes }
 func (s *GoogleSheetsReportSheets) ShipmentClose() *GoogleSheetsReportSheet { return s.shipmentClose }
+func (s *GoogleSheetsReportSheets) FinanceWeekly() *GoogleSheetsReportSheet { return s.financeWeekly }
+func (s *GoogleSheetsReportSheets) FinanceMonthly() *GoogleSheetsReportSheet {
+	return s.financeMonthly
+}
+func (s *GoogleSheetsReportSheets) DriverPerformance() *GoogleSheetsReportSheet {
+	return s.driverPerformance
+}
 
 func (s *GoogleSheetsReportSheets) UnmarshalJSON(b []byte) error {
 	temp := &googleSheetsSheetsData{}
@@ -118,13 +134,25 @@ func (s *GoogleSheetsReportSheets) UnmarshalJSON(b []byte) error {
 	}
 	s.generalRoutes = temp.GeneralRoutes
 	s.shipmentClose = temp.ShipmentClose
+	if temp.FinanceWeekly != nil {
+		s.financeWeekly = temp.FinanceWeekly
+	}
+	if temp.FinanceMonthly != nil {
+		s.financeMonthly = temp.FinanceMonthly
+	}
+	if temp.DriverPerformance != nil {
+		s.driverPerformance = temp.DriverPerformance
+	}
 	return nil
 }
 
 func (s *GoogleSheetsReportSheets) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&googleSheetsSheetsData{
-		GeneralRoutes: s.generalRoutes,
-		ShipmentClose: s.shipmentClose,
+		GeneralRoutes:     s.generalRoutes,
+		ShipmentClose:     s.shipmentClose,
+		FinanceWeekly:     s.financeWeekly,
+		FinanceMonthly:    s.financeMonthly,
+		DriverPerformance: s.driverPerformance,
 	})
 }
 
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	HeadlessDelegateNone     = "none"
+	HeadlessDelegateHTTP     = "http"
+	HeadlessDelegateTelegram = "telegram"
+)
+
+// Headless Non-interactive startup, the interactive steps of the authorization are delegated
+// to the local HTTP page or the Telegram admin chat, otherwise the startup fails
+type Headless struct {
+	delegate       string        // ro
+	address        string        // ro
+	telegramChatID int64         // ro
+	timeout        time.Duration // ro
+}
+
+type headless struct {
+	Delegate       string `json:"delegate"`
+	Address        string `json:"address"`
+	TelegramChatID int64  `json:"telegram_chat_id"`
+	Timeout        int    `json:"timeout"`
+}
+
+func newHeadless() *Headless {
+	return &Headless{
+		delegate:       HeadlessDelegateNone, // default
+		address:        "127.0.0.1:9101",     // default
+		telegramChatID: 0,                    // default
+		timeout:        10 * time.Minute,     // default
+	}
+}
+
+// Delegate Where the interactive steps are delegated: 'none', 'http' or 'telegram'
+func (h *Headless) Delegate() string { return h.delegate }
+
+// Address Listen address of the local HTTP page in the 'host:port' format
+func (h *Headless) Address() string { return h.address }
+
+// TelegramChatID Admin chat which receives the prompts, the bot token is taken from the storage
+func (h *Headless) TelegramChatID() int64 { return h.telegramChatID }
+
+// Timeout Waiting for the answer on the delegated prompt
+func (h *Headless) Timeout() time.Duration { return h.timeout }
+
+func (h *Headless) UnmarshalJSON(b []byte) error {
+	temp := &headless{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	h.delegate = temp.Delegate
+	if h.delegate == "" {
+		h.delegate = HeadlessDelegateNone
+	}
+	h.address = temp.Address
+	h.telegramChatID = temp.TelegramChatID
+	h.timeout = time.Duration(temp.Timeout) * reportsTimePeriod
+	return nil
+}
+
+func (h *Headless) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&headless{
+		Delegate:       h.delegate,
+		Address:        h.address,
+		TelegramChatID: h.telegramChatID,
+		Timeout:        int(h.timeout / reportsTimePeriod),
+	})
+}
@@ -0,0 +1,47 @@
+package config
+
+import "encoding/json"
+
+type History struct {
+	enabled       bool   // ro
+	path          string // ro
+	retentionDays int    // ro
+}
+
+type history struct {
+	Enabled       bool   `json:"enabled"`
+	Path          string `json:"path"`
+	RetentionDays int    `json:"retention_days"`
+}
+
+func newHistory() *History {
+	return &History{
+		enabled:       false,       // default
+		path:          "./history", // default
+		retentionDays: 90,          // default
+	}
+}
+
+func (h *History) IsEnabled() bool    { return h.enabled }
+func (h *History) Path() string       { return h.path }
+func (h *History) RetentionDays() int { return h.retentionDays }
+
+func (h *History) UnmarshalJSON(b []byte) error {
+	temp := &history{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	h.enabled = temp.Enabled
+	h.path = temp.Path
+	h.retentionDays = temp.RetentionDays
+	return nil
+}
+
+func (h *History) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&history{
+		Enabled:       h.enabled,
+		Path:          h.path,
+		RetentionDays: h.retentionDays,
+	})
+}
@@ -0,0 +1,52 @@
+package config
+
+import "encoding/json"
+
+// HTTPServer Local HTTP server of the service endpoints: Prometheus metrics, health and readiness checks
+type HTTPServer struct {
+	enabled        bool   // ro
+	address        string // ro
+	staleIntervals int    // ro
+}
+
+type httpServer struct {
+	Enabled        bool   `json:"enabled"`
+	Address        string `json:"address"`
+	StaleIntervals int    `json:"stale_intervals"`
+}
+
+func newHTTPServer() *HTTPServer {
+	return &HTTPServer{
+		enabled:        false,            // default
+		address:        "127.0.0.1:9100", // default
+		staleIntervals: 3,                // default
+	}
+}
+
+func (h *HTTPServer) IsEnabled() bool { return h.enabled }
+
+// Address Listen address in the 'host:port' format
+func (h *HTTPServer) Address() string { return h.address }
+
+// StaleIntervals Polling intervals or scheduled runs of the report without success after which the health check fails
+func (h *HTTPServer) StaleIntervals() int { return h.staleIntervals }
+
+func (h *HTTPServer) UnmarshalJSON(b []byte) error {
+	temp := &httpServer{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	h.enabled = temp.Enabled
+	h.address = temp.Address
+	h.staleIntervals = temp.StaleIntervals
+	return nil
+}
+
+func (h *HTTPServer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&httpServer{
+		Enabled:        h.enabled,
+		Address:        h.address,
+		StaleIntervals: h.staleIntervals,
+	})
+}
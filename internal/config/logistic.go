@@ -7,6 +7,12 @@ import (
 
 const logisticTimePeriod = time.Millisecond
 
+const (
+	LogisticCassetteOff    = "off"
+	LogisticCassetteRecord = "record"
+	LogisticCassetteReplay = "replay"
+)
+
 type Logistic struct {
 	wbClient *LogisticClient   // ro
 	office   *LogisticOffice   // ro
@@ -52,15 +58,23 @@ func (l *Logistic) MarshalJSON() ([]byte, error) {
 }
 
 type LogisticClient struct {
-	userAgent    string // ro
-	secUserAgent string // ro
-	platform     string // ro
+	userAgent    string                        // ro
+	secUserAgent string                        // ro
+	platform     string                        // ro
+	endpoints    map[string]string             // ro
+	cassetteMode string                        // ro
+	cassetteDir  string                        // ro
+	rateLimits   map[string]*LogisticRateLimit // ro
 }
 
 type logisticClient struct {
-	UserAgent    string `json:"user_agent"`
-	SecUserAgent string `json:"sec_user_agent"`
-	Platform     string `json:"platform"`
+	UserAgent    string                        `json:"user_agent"`
+	SecUserAgent string                        `json:"sec_user_agent"`
+	Platform     string                        `json:"platform"`
+	Endpoints    map[string]string             `json:"endpoints,omitempty"`
+	CassetteMode string                        `json:"cassette_mode,omitempty"`
+	CassetteDir  string                        `json:"cassette_dir,omitempty"`
+	RateLimits   map[string]*LogisticRateLimit `json:"rate_limits,omitempty"`
 }
 
 func newLogisticClient() *LogisticClient {
@@ -68,6 +82,12 @@ func newLogisticClient() *LogisticClient {
 		userAgent:    "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
 		secUserAgent: "\"Google Chrome\";v=\"131\", \"Chromium\";v=\"131\", \"Not_A Brand\";v=\"24\"",
 		platform:     "windows",
+		endpoints:    map[string]string{},
+		cassetteMode: LogisticCassetteOff,     // default
+		cassetteDir:  "cassettes/wb_logistic", // default
+		rateLimits: map[string]*LogisticRateLimit{
+			LogisticRateLimitDefault: newLogisticRateLimit(), // default
+		},
 	}
 }
 
@@ -75,6 +95,19 @@ func (l *LogisticClient) UserAgent() string    { return l.userAgent }
 func (l *LogisticClient) SecUserAgent() string { return l.secUserAgent }
 func (l *LogisticClient) Platform() string     { return l.platform }
 
+// Endpoints Base URLs of the WB logistic API services by the name: auth, users, shipments, waysheets, reports,
+// finance, routes, planning, tares, captcha. The services which are not set use the production hosts
+func (l *LogisticClient) Endpoints() map[string]string { return l.endpoints }
+
+// CassetteMode 'record' saves the traffic to CassetteDir with the tokens redacted, 'replay' serves it without
+// the network to reproduce the reports. The authorization is not recorded, so the replay needs the saved session
+func (l *LogisticClient) CassetteMode() string { return l.cassetteMode }
+func (l *LogisticClient) CassetteDir() string  { return l.cassetteDir }
+
+// RateLimits Limits of the requests by the service name like Endpoints, 'default' is used for the services
+// which are not set
+func (l *LogisticClient) RateLimits() map[string]*LogisticRateLimit { return l.rateLimits }
+
 func (l *LogisticClient) UnmarshalJSON(b []byte) error {
 	temp := &logisticClient{}
 	err := json.Unmarshal(b, temp)
@@ -84,6 +117,25 @@ func (l *LogisticClient) UnmarshalJSON(b []byte) error {
 	l.userAgent = temp.UserAgent
 	l.secUserAgent = temp.SecUserAgent
 	l.platform = temp.Platform
+	l.endpoints = temp.Endpoints
+	if l.endpoints == nil {
+		l.endpoints = map[string]string{}
+	}
+	l.cassetteMode = temp.CassetteMode
+	if l.cassetteMode == "" {
+		l.cassetteMode = LogisticCassetteOff
+	}
+	l.cassetteDir = temp.CassetteDir
+	if l.cassetteDir == "" {
+		l.cassetteDir = "cassettes/wb_logistic"
+	}
+	l.rateLimits = temp.RateLimits
+	if l.rateLimits == nil {
+		l.rateLimits = map[string]*LogisticRateLimit{}
+	}
+	if l.rateLimits[LogisticRateLimitDefault] == nil {
+		l.rateLimits[LogisticRateLimitDefault] = newLogisticRateLimit()
+	}
 	return nil
 }
 
@@ -92,6 +144,68 @@ func (l *LogisticClient) MarshalJSON() ([]byte, error) {
 		UserAgent:    l.userAgent,
 		SecUserAgent: l.secUserAgent,
 		Platform:     l.platform,
+		Endpoints:    l.endpoints,
+		CassetteMode: l.cassetteMode,
+		CassetteDir:  l.cassetteDir,
+		RateLimits:   l.rateLimits,
+	})
+}
+
+const LogisticRateLimitDefault = "default"
+
+// LogisticRateLimit Token bucket of the WB logistic API requests of the service, 0 is unlimited
+type LogisticRateLimit struct {
+	rate        float64 // ro
+	burst       int     // ro
+	concurrency int     // ro
+}
+
+type logisticRateLimit struct {
+	Rate        float64 `json:"rate"`
+	Burst       int     `json:"burst"`
+	Concurrency int     `json:"concurrency"`
+}
+
+func newLogisticRateLimit() *LogisticRateLimit {
+	return &LogisticRateLimit{
+		rate:        5,  // default
+		burst:       10, // default
+		concurrency: 8,  // default
+	}
+}
+
+// Rate Requests per second, after 429 the rate is reduced and the requests wait for Retry-After
+func (l *LogisticRateLimit) Rate() float64 { return l.rate }
+
+// Burst Requests sent at once after the idle time
+func (l *LogisticRateLimit) Burst() int { return l.burst }
+
+// Concurrency Requests in flight
+func (l *LogisticRateLimit) Concurrency() int { return l.concurrency }
+
+// UnmarshalJSON The keys which are not set keep the default values, 0 set explicitly is unlimited
+func (l *LogisticRateLimit) UnmarshalJSON(b []byte) error {
+	defaults := newLogisticRateLimit()
+	temp := &logisticRateLimit{
+		Rate:        defaults.rate,
+		Burst:       defaults.burst,
+		Concurrency: defaults.concurrency,
+	}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	l.rate = temp.Rate
+	l.burst = temp.Burst
+	l.concurrency = temp.Concurrency
+	return nil
+}
+
+func (l *LogisticRateLimit) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&logisticRateLimit{
+		Rate:        l.rate,
+		Burst:       l.burst,
+		Concurrency: l.concurrency,
 	})
 }
 
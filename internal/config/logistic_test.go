@@ -0,0 +1,34 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLogisticRateLimitUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		rate        float64
+		burst       int
+		concurrency int
+	}{
+		{"empty", `{}`, 5, 10, 8},
+		{"rate only", `{"rate": 2}`, 2, 10, 8},
+		{"unlimited concurrency", `{"rate": 2, "concurrency": 0}`, 2, 10, 0},
+		{"all", `{"rate": 1, "burst": 3, "concurrency": 4}`, 1, 3, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var limits map[string]*LogisticRateLimit
+			if err := json.Unmarshal([]byte(`{"default": `+tt.data+`}`), &limits); err != nil {
+				t.Fatal(err)
+			}
+			l := limits[LogisticRateLimitDefault]
+			if l.Rate() != tt.rate || l.Burst() != tt.burst || l.Concurrency() != tt.concurrency {
+				t.Fatalf("rate %v, burst %d, concurrency %d, want %v, %d, %d",
+					l.Rate(), l.Burst(), l.Concurrency(), tt.rate, tt.burst, tt.concurrency)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Outbox Durable queue of the outbound Telegram bot messages
+type Outbox struct {
+	path        string        // ro
+	maxAttempts int           // ro
+	baseBackoff time.Duration // ro
+	maxBackoff  time.Duration // ro
+}
+
+type outbox struct {
+	Path        string        `json:"path"`
+	MaxAttempts int           `json:"max_attempts"`
+	BaseBackoff time.Duration `json:"base_backoff"`
+	MaxBackoff  time.Duration `json:"max_backoff"`
+}
+
+func newOutbox() *Outbox {
+	return &Outbox{
+		path:        "./outbox",                  // default
+		maxAttempts: 10,                          // default
+		baseBackoff: 1000 * reportsTimePeriod,    // default
+		maxBackoff:  600_000 * reportsTimePeriod, // default
+	}
+}
+
+func (o *Outbox) Path() string { return o.path }
+
+// MaxAttempts After that the message goes to the dead letters
+func (o *Outbox) MaxAttempts() int { return o.maxAttempts }
+
+func (o *Outbox) BaseBackoff() time.Duration { return o.baseBackoff }
+func (o *Outbox) MaxBackoff() time.Duration  { return o.maxBackoff }
+
+func (o *Outbox) UnmarshalJSON(b []byte) error {
+	temp := &outbox{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	o.path = temp.Path
+	o.maxAttempts = temp.MaxAttempts
+	o.baseBackoff = temp.BaseBackoff * reportsTimePeriod
+	o.maxBackoff = temp.MaxBackoff * reportsTimePeriod
+	return nil
+}
+
+func (o *Outbox) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&outbox{
+		Path:        o.path,
+		MaxAttempts: o.maxAttempts,
+		BaseBackoff: o.baseBackoff / reportsTimePeriod,
+		MaxBackoff:  o.maxBackoff / reportsTimePeriod,
+	})
+}
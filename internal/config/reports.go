@@ -8,32 +8,44 @@ import (
 const reportsTimePeriod = time.Millisecond
 
 type Reports struct {
-	generalRoutes *ReportsGeneralRoutes // ro
-	shipmentClose *ReportsShipmentClose // ro
-	financeRoutes *ReportsFinanceRoutes // ro
-	financeDaily  *ReportsFinanceDaily  // ro
+	generalRoutes     *ReportsGeneralRoutes     // ro
+	shipmentClose     *ReportsShipmentClose     // ro
+	financeRoutes     *ReportsFinanceRoutes     // ro
+	financeDaily      *ReportsFinanceDaily      // ro
+	financeWeekly     *ReportsFinancePeriod     // ro
+	financeMonthly    *ReportsFinancePeriod     // ro
+	driverPerformance *ReportsDriverPerformance // ro
 }
 
 type reports struct {
-	GeneralRoutes *ReportsGeneralRoutes `json:"general_routes"`
-	ShipmentClose *ReportsShipmentClose `json:"shipment_close"`
-	FinanceRoutes *ReportsFinanceRoutes `json:"finance_routes"`
-	FinanceDaily  *ReportsFinanceDaily  `json:"finance_daily"`
+	GeneralRoutes     *ReportsGeneralRoutes     `json:"general_routes"`
+	ShipmentClose     *ReportsShipmentClose     `json:"shipment_close"`
+	FinanceRoutes     *ReportsFinanceRoutes     `json:"finance_routes"`
+	FinanceDaily      *ReportsFinanceDaily      `json:"finance_daily"`
+	FinanceWeekly     *ReportsFinancePeriod     `json:"finance_weekly"`
+	FinanceMonthly    *ReportsFinancePeriod     `json:"finance_monthly"`
+	DriverPerformance *ReportsDriverPerformance `json:"driver_performance"`
 }
 
 func newReports() *Reports {
 	return &Reports{
-		generalRoutes: newReportsGeneralRoutes(), // default
-		shipmentClose: newReportsShipmentClose(), // default
-		financeRoutes: newReportsFinanceRoutes(), // default
-		financeDaily:  newReportsFinanceDaily(),  // default
+		generalRoutes:     newReportsGeneralRoutes(),     // default
+		shipmentClose:     newReportsShipmentClose(),     // default
+		financeRoutes:     newReportsFinanceRoutes(),     // default
+		financeDaily:      newReportsFinanceDaily(),      // default
+		financeWeekly:     newReportsFinancePeriod(),     // default
+		financeMonthly:    newReportsFinancePeriod(),     // default
+		driverPerformance: newReportsDriverPerformance(), // default
 	}
 }
 
-func (r *Reports) GeneralRoutes() *ReportsGeneralRoutes { return r.generalRoutes }
-func (r *Reports) ShipmentClose() *ReportsShipmentClose { return r.shipmentClose }
-func (r *Reports) FinanceRoutes() *ReportsFinanceRoutes { return r.financeRoutes }
-func (r *Reports) FinanceDaily() *ReportsFinanceDaily   { return r.financeDaily }
+func (r *Reports) GeneralRoutes() *ReportsGeneralRoutes         { return r.generalRoutes }
+func (r *Reports) ShipmentClose() *ReportsShipmentClose         { return r.shipmentClose }
+func (r *Reports) FinanceRoutes() *ReportsFinanceRoutes         { return r.financeRoutes }
+func (r *Reports) FinanceDaily() *ReportsFinanceDaily           { return r.financeDaily }
+func (r *Reports) FinanceWeekly() *ReportsFinancePeriod         { return r.financeWeekly }
+func (r *Reports) FinanceMonthly() *ReportsFinancePeriod        { return r.financeMonthly }
+func (r *Reports) DriverPerformance() *ReportsDriverPerformance { return r.driverPerformance }
 
 func (r *Reports) UnmarshalJSON(b []byte) error {
 	temp := &reports{}
@@ -45,46 +57,62 @@ func (r *Reports) UnmarshalJSON(b []byte) error {
 	r.shipmentClose = temp.ShipmentClose
 	r.financeRoutes = temp.FinanceRoutes
 	r.financeDaily = temp.FinanceDaily
+	if temp.FinanceWeekly != nil {
+		r.financeWeekly = temp.FinanceWeekly
+	}
+	if temp.FinanceMonthly != nil {
+		r.financeMonthly = temp.FinanceMonthly
+	}
+	if temp.DriverPerformance != nil {
+		r.driverPerformance = temp.DriverPerformance
+	}
 	return nil
 }
 
 func (r *Reports) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&reports{
-		GeneralRoutes: r.generalRoutes,
-		ShipmentClose: r.shipmentClose,
-		FinanceRoutes: r.financeRoutes,
-		FinanceDaily:  r.financeDaily,
+		GeneralRoutes:     r.generalRoutes,
+		ShipmentClose:     r.shipmentClose,
+		FinanceRoutes:     r.financeRoutes,
+		FinanceDaily:      r.financeDaily,
+		FinanceWeekly:     r.financeWeekly,
+		FinanceMonthly:    r.financeMonthly,
+		DriverPerformance: r.driverPerformance,
 	})
 }
 
 type ReportsGeneralRoutes struct {
-	isEnabled                   bool          // ro
-	errRetryTaskLimit           int           // ro
-	pollingInterval             time.Duration // ro
-	taskTimeout                 time.Duration // ro
-	intervalResetChangeBarcodes time.Duration // ro
-	intervalUpdateRating        time.Duration // ro
-	intervalUpdateShipments     time.Duration // ro
-	intervalUpdateWaySheets     time.Duration // ro
-	isSort                      bool          // ro
-	isSortAscending             bool          // ro
-	sortColumn                  int           // ro
-	isRenderGoogleSheets        bool          // ro
+	isEnabled                   bool                 // ro
+	errRetryTaskLimit           int                  // ro
+	pollingInterval             time.Duration        // ro
+	taskTimeout                 time.Duration        // ro
+	schedule                    *ReportsSchedule     // ro
+	intervalResetChangeBarcodes time.Duration        // ro
+	intervalUpdateRating        time.Duration        // ro
+	intervalUpdateShipments     time.Duration        // ro
+	intervalUpdateWaySheets     time.Duration        // ro
+	isSort                      bool                 // ro
+	isSortAscending             bool                 // ro
+	sortColumn                  int                  // ro
+	isRenderGoogleSheets        bool                 // ro
+	ratingAlerts                *ReportsRatingAlerts // ro
 }
 
 type reportsGeneralRoutes struct {
-	IsEnabled                   bool          `json:"enabled"`
-	ErrRetryTaskLimit           int           `json:"err_retry_task_limit"`
-	PollingInterval             time.Duration `json:"polling_interval"`
-	TaskTimeout                 time.Duration `json:"task_timeout"`
-	IntervalResetChangeBarcodes time.Duration `json:"interval_reset_change_barcodes"`
-	IntervalUpdateRating        time.Duration `json:"interval_update_rating"`
-	IntervalUpdateShipments     time.Duration `json:"interval_update_shipments"`
-	IntervalUpdateWaySheets     time.Duration `json:"interval_update_waysheets"`
-	IsSort                      bool          `json:"sort"`
-	IsSortAscending             bool          `json:"sort_ascending"`
-	SortColumn                  int           `json:"sort_column"`
-	IsRenderGoogleSheets        bool          `json:"render_google_sheets"`
+	IsEnabled                   bool                 `json:"enabled"`
+	ErrRetryTaskLimit           int                  `json:"err_retry_task_limit"`
+	PollingInterval             time.Duration        `json:"polling_interval"`
+	TaskTimeout                 time.Duration        `json:"task_timeout"`
+	Schedule                    *ReportsSchedule     `json:"schedule"`
+	IntervalResetChangeBarcodes time.Duration        `json:"interval_reset_change_barcodes"`
+	IntervalUpdateRating        time.Duration        `json:"interval_update_rating"`
+	IntervalUpdateShipments     time.Duration        `json:"interval_update_shipments"`
+	IntervalUpdateWaySheets     time.Duration        `json:"interval_update_waysheets"`
+	IsSort                      bool                 `json:"sort"`
+	IsSortAscending             bool                 `json:"sort_ascending"`
+	SortColumn                  int                  `json:"sort_column"`
+	IsRenderGoogleSheets        bool                 `json:"render_google_sheets"`
+	RatingAlerts                *ReportsRatingAlerts `json:"rating_alerts"`
 }
 
 func newReportsGeneralRoutes() *ReportsGeneralRoutes {
@@ -93,6 +121,7 @@ func newReportsGeneralRoutes() *ReportsGeneralRoutes {
 		errRetryTaskLimit:           3,                             // default
 		pollingInterval:             5000 * reportsTimePeriod,      // default
 		taskTimeout:                 600_000 * reportsTimePeriod,   // default
+		schedule:                    newReportsSchedule(),          // default
 		intervalResetChangeBarcodes: 300_000 * reportsTimePeriod,   // default
 		intervalUpdateRating:        6_000_000 * reportsTimePeriod, // default
 		intervalUpdateShipments:     100_000 * reportsTimePeriod,   // default
@@ -101,6 +130,7 @@ func newReportsGeneralRoutes() *ReportsGeneralRoutes {
 		isSortAscending:             false,                         // default
 		sortColumn:                  0,                             // default
 		isRenderGoogleSheets:        false,                         // default
+		ratingAlerts:                newReportsRatingAlerts(),      // default
 	}
 }
 
@@ -112,6 +142,9 @@ func (r *ReportsGeneralRoutes) PollingInterval() time.Duration { return r.pollin
 
 func (r *ReportsGeneralRoutes) TaskTimeout() time.Duration { return r.taskTimeout }
 
+// Schedule If it is calendar, the report runs by it instead of the polling interval
+func (r *ReportsGeneralRoutes) Schedule() *ReportsSchedule { return r.schedule }
+
 func (r *ReportsGeneralRoutes) IntervalResetChangeBarcodes() time.Duration {
 	return r.intervalResetChangeBarcodes
 }
@@ -131,6 +164,8 @@ func (r *ReportsGeneralRoutes) SortColumn() int   { return r.sortColumn }
 
 func (r *ReportsGeneralRoutes) IsRenderGoogleSheets() bool { return r.isRenderGoogleSheets }
 
+func (r *ReportsGeneralRoutes) RatingAlerts() *ReportsRatingAlerts { return r.ratingAlerts }
+
 func (r *ReportsGeneralRoutes) UnmarshalJSON(b []byte) error {
 	temp := &reportsGeneralRoutes{}
 	err := json.Unmarshal(b, temp)
@@ -141,6 +176,10 @@ func (r *ReportsGeneralRoutes) UnmarshalJSON(b []byte) error {
 	r.pollingInterval = temp.PollingInterval * reportsTimePeriod
 	r.errRetryTaskLimit = temp.ErrRetryTaskLimit
 	r.taskTimeout = temp.TaskTimeout * reportsTimePeriod
+	r.schedule = newReportsSchedule()
+	if temp.Schedule != nil {
+		r.schedule = temp.Schedule
+	}
 	r.intervalResetChangeBarcodes = temp.IntervalResetChangeBarcodes * reportsTimePeriod
 	r.intervalUpdateRating = temp.IntervalUpdateRating * reportsTimePeriod
 	r.intervalUpdateShipments = temp.IntervalUpdateShipments * reportsTimePeriod
@@ -149,6 +188,10 @@ func (r *ReportsGeneralRoutes) UnmarshalJSON(b []byte) error {
 	r.isSortAscending = temp.IsSortAscending
 	r.sortColumn = temp.SortColumn
 	r.isRenderGoogleSheets = temp.IsRenderGoogleSheets
+	r.ratingAlerts = newReportsRatingAlerts()
+	if temp.RatingAlerts != nil {
+		r.ratingAlerts = temp.RatingAlerts
+	}
 	return nil
 }
 
@@ -158,6 +201,7 @@ func (r *ReportsGeneralRoutes) MarshalJSON() ([]byte, error) {
 		PollingInterval:             r.pollingInterval / reportsTimePeriod,
 		ErrRetryTaskLimit:           r.errRetryTaskLimit,
 		TaskTimeout:                 r.taskTimeout / reportsTimePeriod,
+		Schedule:                    r.schedule,
 		IntervalResetChangeBarcodes: r.intervalResetChangeBarcodes / reportsTimePeriod,
 		IntervalUpdateRating:        r.intervalUpdateRating / reportsTimePeriod,
 		IntervalUpdateShipments:     r.intervalUpdateShipments / reportsTimePeriod,
@@ -166,27 +210,159 @@ func (r *ReportsGeneralRoutes) MarshalJSON() ([]byte, error) {
 		IsSortAscending:             r.isSortAscending,
 		SortColumn:                  r.sortColumn,
 		IsRenderGoogleSheets:        r.isRenderGoogleSheets,
+		RatingAlerts:                r.ratingAlerts,
+	})
+}
+
+// ReportsRatingAlerts Config of alerts about drops of the route rating components, checked on every rating update
+type ReportsRatingAlerts struct {
+	isEnabled bool          // ro
+	threshold float64       // ro
+	window    time.Duration // ro
+}
+
+type reportsRatingAlerts struct {
+	IsEnabled bool          `json:"enabled"`
+	Threshold float64       `json:"threshold"`
+	Window    time.Duration `json:"window"`
+}
+
+func newReportsRatingAlerts() *ReportsRatingAlerts {
+	return &ReportsRatingAlerts{
+		isEnabled: false,                          // default
+		threshold: 0.5,                            // default
+		window:    86_400_000 * reportsTimePeriod, // default
+	}
+}
+
+func (r *ReportsRatingAlerts) IsEnabled() bool { return r.isEnabled }
+
+// Threshold Minimal drop of any rating component within the window which triggers the alert
+func (r *ReportsRatingAlerts) Threshold() float64 { return r.threshold }
+
+func (r *ReportsRatingAlerts) Window() time.Duration { return r.window }
+
+func (r *ReportsRatingAlerts) UnmarshalJSON(b []byte) error {
+	temp := &reportsRatingAlerts{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	r.isEnabled = temp.IsEnabled
+	r.threshold = temp.Threshold
+	r.window = temp.Window * reportsTimePeriod
+	return nil
+}
+
+func (r *ReportsRatingAlerts) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&reportsRatingAlerts{
+		IsEnabled: r.isEnabled,
+		Threshold: r.threshold,
+		Window:    r.window / reportsTimePeriod,
+	})
+}
+
+// ReportsSchedule Calendar schedule of the report by cron expression or times of day, in the timezone.
+// Window from-to restricts runs of the report to the business hours in both polling and calendar modes
+type ReportsSchedule struct {
+	cron     string   // ro
+	at       []string // ro
+	timezone string   // ro
+	from     string   // ro
+	to       string   // ro
+}
+
+type reportsSchedule struct {
+	Cron     string   `json:"cron"`
+	At       []string `json:"at"`
+	Timezone string   `json:"timezone"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+}
+
+func newReportsSchedule() *ReportsSchedule {
+	return &ReportsSchedule{
+		cron:     "",      // default
+		at:       nil,     // default
+		timezone: "Local", // default
+		from:     "",      // default
+		to:       "",      // default
+	}
+}
+
+// Cron Expression of 5 fields: minute hour day-of-month month day-of-week, or @hourly, @daily, @weekly, @monthly
+func (r *ReportsSchedule) Cron() string { return r.cron }
+
+// At Times of day in the format HH:MM
+func (r *ReportsSchedule) At() []string { return r.at }
+
+func (r *ReportsSchedule) Timezone() string { return r.timezone }
+
+// Location Returns the location of the timezone, local if the timezone is invalid
+func (r *ReportsSchedule) Location() *time.Location {
+	location, err := time.LoadLocation(r.timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+func (r *ReportsSchedule) From() string { return r.from }
+func (r *ReportsSchedule) To() string   { return r.to }
+
+// IsCalendar The report runs by cron or at the times of day
+func (r *ReportsSchedule) IsCalendar() bool { return r.cron != "" || len(r.at) > 0 }
+
+// IsWindow The runs are restricted to the business hours
+func (r *ReportsSchedule) IsWindow() bool { return r.from != "" || r.to != "" }
+
+func (r *ReportsSchedule) UnmarshalJSON(b []byte) error {
+	temp := &reportsSchedule{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	r.cron = temp.Cron
+	r.at = temp.At
+	r.timezone = temp.Timezone
+	if r.timezone == "" {
+		r.timezone = "Local"
+	}
+	r.from = temp.From
+	r.to = temp.To
+	return nil
+}
+
+func (r *ReportsSchedule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&reportsSchedule{
+		Cron:     r.cron,
+		At:       r.at,
+		Timezone: r.timezone,
+		From:     r.from,
+		To:       r.to,
 	})
 }
 
 type ReportsShipmentClose struct {
-	isEnabled               bool          // ro
-	errRetryTaskLimit       int           // ro
-	pollingInterval         time.Duration // ro
-	taskTimeout             time.Duration // ro
-	intervalUpdateShipments time.Duration // ro
-	isRenderGoogleSheets    bool          // ro
-	isRenderTelegramBot     bool          // ro
+	isEnabled               bool             // ro
+	errRetryTaskLimit       int              // ro
+	pollingInterval         time.Duration    // ro
+	taskTimeout             time.Duration    // ro
+	schedule                *ReportsSchedule // ro
+	intervalUpdateShipments time.Duration    // ro
+	isRenderGoogleSheets    bool             // ro
+	isRenderTelegramBot     bool             // ro
 }
 
 type reportsShipmentClose struct {
-	IsEnabled               bool          `json:"enabled"`
-	ErrRetryTaskLimit       int           `json:"err_retry_task_limit"`
-	PollingInterval         time.Duration `json:"polling_interval"`
-	TaskTimeout             time.Duration `json:"task_timeout"`
-	IntervalUpdateShipments time.Duration `json:"interval_update_shipments"`
-	IsRenderGoogleSheets    bool          `json:"render_google_sheets"`
-	IsRenderTelegramBot     bool          `json:"render_telegram_bot"`
+	IsEnabled               bool             `json:"enabled"`
+	ErrRetryTaskLimit       int              `json:"err_retry_task_limit"`
+	PollingInterval         time.Duration    `json:"polling_interval"`
+	TaskTimeout             time.Duration    `json:"task_timeout"`
+	Schedule                *ReportsSchedule `json:"schedule"`
+	IntervalUpdateShipments time.Duration    `json:"interval_update_shipments"`
+	IsRenderGoogleSheets    bool             `json:"render_google_sheets"`
+	IsRenderTelegramBot     bool             `json:"render_telegram_bot"`
 }
 
 func newReportsShipmentClose() *ReportsShipmentClose {
@@ -195,6 +371,7 @@ func newReportsShipmentClose() *ReportsShipmentClose {
 		errRetryTaskLimit:       3,                           // default
 		pollingInterval:         1000 * reportsTimePeriod,    // default
 		taskTimeout:             600_000 * reportsTimePeriod, // default
+		schedule:                newReportsSchedule(),        // default
 		intervalUpdateShipments: 100_000 * reportsTimePeriod, // default
 		isRenderGoogleSheets:    false,                       // default
 		isRenderTelegramBot:     false,                       // default
@@ -211,6 +388,9 @@ func (r *ReportsShipmentClose) IntervalUpdateShipments() time.Duration {
 
 func (r *ReportsShipmentClose) TaskTimeout() time.Duration { return r.taskTimeout }
 
+// Schedule If it is calendar, the report runs by it instead of the polling interval
+func (r *ReportsShipmentClose) Schedule() *ReportsSchedule { return r.schedule }
+
 func (r *ReportsShipmentClose) ErrRetryTaskLimit() int { return r.errRetryTaskLimit }
 
 func (r *ReportsShipmentClose) IsRenderGoogleSheets() bool { return r.isRenderGoogleSheets }
@@ -227,6 +407,10 @@ func (r *ReportsShipmentClose) UnmarshalJSON(b []byte) error {
 	r.intervalUpdateShipments = temp.IntervalUpdateShipments * reportsTimePeriod
 	r.errRetryTaskLimit = temp.ErrRetryTaskLimit
 	r.taskTimeout = temp.TaskTimeout * reportsTimePeriod
+	r.schedule = newReportsSchedule()
+	if temp.Schedule != nil {
+		r.schedule = temp.Schedule
+	}
 	r.isRenderGoogleSheets = temp.IsRenderGoogleSheets
 	r.isRenderTelegramBot = temp.IsRenderTelegramBot
 	return nil
@@ -239,29 +423,32 @@ func (r *ReportsShipmentClose) MarshalJSON() ([]byte, error) {
 		IntervalUpdateShipments: r.intervalUpdateShipments / reportsTimePeriod,
 		ErrRetryTaskLimit:       r.errRetryTaskLimit,
 		TaskTimeout:             r.taskTimeout / reportsTimePeriod,
+		Schedule:                r.schedule,
 		IsRenderGoogleSheets:    r.isRenderGoogleSheets,
 		IsRenderTelegramBot:     r.isRenderTelegramBot,
 	})
 }
 
 type ReportsFinanceRoutes struct {
-	isEnabled                   bool          // ro
-	errRetryTaskLimit           int           // ro
-	pollingInterval             time.Duration // ro
-	taskTimeout                 time.Duration // ro
-	renderDelay                 time.Duration // ro
-	sendMessageDelayTelegramBot time.Duration // ro
-	isRenderTelegramBot         bool          // ro
+	isEnabled                   bool             // ro
+	errRetryTaskLimit           int              // ro
+	pollingInterval             time.Duration    // ro
+	taskTimeout                 time.Duration    // ro
+	schedule                    *ReportsSchedule // ro
+	renderDelay                 time.Duration    // ro
+	sendMessageDelayTelegramBot time.Duration    // ro
+	isRenderTelegramBot         bool             // ro
 }
 
 type reportsFinanceRoutes struct {
-	IsEnabled                   bool          `json:"enabled"`
-	ErrRetryTaskLimit           int           `json:"err_retry_task_limit"`
-	PollingInterval             time.Duration `json:"polling_interval"`
-	TaskTimeout                 time.Duration `json:"task_timeout"`
-	RenderDelay                 time.Duration `json:"render_delay"`
-	SendMessageDelayTelegramBot time.Duration `json:"send_message_delay_telegram_bot"`
-	IsRenderTelegramBot         bool          `json:"render_telegram_bot"`
+	IsEnabled                   bool             `json:"enabled"`
+	ErrRetryTaskLimit           int              `json:"err_retry_task_limit"`
+	PollingInterval             time.Duration    `json:"polling_interval"`
+	TaskTimeout                 time.Duration    `json:"task_timeout"`
+	Schedule                    *ReportsSchedule `json:"schedule"`
+	RenderDelay                 time.Duration    `json:"render_delay"`
+	SendMessageDelayTelegramBot time.Duration    `json:"send_message_delay_telegram_bot"`
+	IsRenderTelegramBot         bool             `json:"render_telegram_bot"`
 }
 
 func newReportsFinanceRoutes() *ReportsFinanceRoutes {
@@ -270,6 +457,7 @@ func newReportsFinanceRoutes() *ReportsFinanceRoutes {
 		errRetryTaskLimit:           3,                           // default
 		pollingInterval:             1000 * reportsTimePeriod,    // default
 		taskTimeout:                 600_000 * reportsTimePeriod, // default
+		schedule:                    newReportsSchedule(),        // default
 		renderDelay:                 600_000 * reportsTimePeriod, // default
 		sendMessageDelayTelegramBot: 120_000 * reportsTimePeriod, // default
 		isRenderTelegramBot:         false,                       // default
@@ -282,6 +470,9 @@ func (r *ReportsFinanceRoutes) PollingInterval() time.Duration { return r.pollin
 
 func (r *ReportsFinanceRoutes) TaskTimeout() time.Duration { return r.taskTimeout }
 
+// Schedule If it is calendar, the report runs by it instead of the polling interval
+func (r *ReportsFinanceRoutes) Schedule() *ReportsSchedule { return r.schedule }
+
 func (r *ReportsFinanceRoutes) ErrRetryTaskLimit() int { return r.errRetryTaskLimit }
 
 func (r *ReportsFinanceRoutes) RenderDelay() time.Duration {
@@ -304,6 +495,10 @@ func (r *ReportsFinanceRoutes) UnmarshalJSON(b []byte) error {
 	r.pollingInterval = temp.PollingInterval * reportsTimePeriod
 	r.errRetryTaskLimit = temp.ErrRetryTaskLimit
 	r.taskTimeout = temp.TaskTimeout * reportsTimePeriod
+	r.schedule = newReportsSchedule()
+	if temp.Schedule != nil {
+		r.schedule = temp.Schedule
+	}
 	r.renderDelay = temp.RenderDelay * reportsTimePeriod
 	r.sendMessageDelayTelegramBot = temp.SendMessageDelayTelegramBot * reportsTimePeriod
 	r.isRenderTelegramBot = temp.IsRenderTelegramBot
@@ -316,6 +511,7 @@ func (r *ReportsFinanceRoutes) MarshalJSON() ([]byte, error) {
 		PollingInterval:             r.pollingInterval / reportsTimePeriod,
 		ErrRetryTaskLimit:           r.errRetryTaskLimit,
 		TaskTimeout:                 r.taskTimeout / reportsTimePeriod,
+		Schedule:                    r.schedule,
 		RenderDelay:                 r.renderDelay / reportsTimePeriod,
 		SendMessageDelayTelegramBot: r.sendMessageDelayTelegramBot / reportsTimePeriod,
 		IsRenderTelegramBot:         r.isRenderTelegramBot,
@@ -323,23 +519,25 @@ func (r *ReportsFinanceRoutes) MarshalJSON() ([]byte, error) {
 }
 
 type ReportsFinanceDaily struct {
-	isEnabled           bool          // ro
-	errRetryTaskLimit   int           // ro
-	pollingInterval     time.Duration // ro
-	taskTimeout         time.Duration // ro
-	renderAtStart       bool          // ro
-	dayOffset           int           // ro
-	isRenderTelegramBot bool          // ro
+	isEnabled           bool             // ro
+	errRetryTaskLimit   int              // ro
+	pollingInterval     time.Duration    // ro
+	taskTimeout         time.Duration    // ro
+	schedule            *ReportsSchedule // ro
+	renderAtStart       bool             // ro
+	dayOffset           int              // ro
+	isRenderTelegramBot bool             // ro
 }
 
 type reportsFinanceDaily struct {
-	IsEnabled           bool          `json:"enabled"`
-	ErrRetryTaskLimit   int           `json:"err_retry_task_limit"`
-	PollingInterval     time.Duration `json:"polling_interval"`
-	TaskTimeout         time.Duration `json:"task_timeout"`
-	RenderAtStart       bool          `json:"render_at_start"`
-	DayOffset           int           `json:"day_offset"`
-	IsRenderTelegramBot bool          `json:"render_telegram_bot"`
+	IsEnabled           bool             `json:"enabled"`
+	ErrRetryTaskLimit   int              `json:"err_retry_task_limit"`
+	PollingInterval     time.Duration    `json:"polling_interval"`
+	TaskTimeout         time.Duration    `json:"task_timeout"`
+	Schedule            *ReportsSchedule `json:"schedule"`
+	RenderAtStart       bool             `json:"render_at_start"`
+	DayOffset           int              `json:"day_offset"`
+	IsRenderTelegramBot bool             `json:"render_telegram_bot"`
 }
 
 func newReportsFinanceDaily() *ReportsFinanceDaily {
@@ -348,6 +546,7 @@ func newReportsFinanceDaily() *ReportsFinanceDaily {
 		errRetryTaskLimit:   3,                           // default
 		pollingInterval:     1000 * reportsTimePeriod,    // default
 		taskTimeout:         600_000 * reportsTimePeriod, // default
+		schedule:            newReportsSchedule(),        // default
 		renderAtStart:       false,                       // default
 		dayOffset:           -1,                          // default
 		isRenderTelegramBot: false,                       // default
@@ -360,6 +559,9 @@ func (r *ReportsFinanceDaily) PollingInterval() time.Duration { return r.polling
 
 func (r *ReportsFinanceDaily) TaskTimeout() time.Duration { return r.taskTimeout }
 
+// Schedule If it is calendar, the report runs by it instead of the polling interval
+func (r *ReportsFinanceDaily) Schedule() *ReportsSchedule { return r.schedule }
+
 func (r *ReportsFinanceDaily) ErrRetryTaskLimit() int { return r.errRetryTaskLimit }
 
 func (r *ReportsFinanceDaily) RenderAtStart() bool {
@@ -380,6 +582,10 @@ func (r *ReportsFinanceDaily) UnmarshalJSON(b []byte) error {
 	r.pollingInterval = temp.PollingInterval * reportsTimePeriod
 	r.errRetryTaskLimit = temp.ErrRetryTaskLimit
 	r.taskTimeout = temp.TaskTimeout * reportsTimePeriod
+	r.schedule = newReportsSchedule()
+	if temp.Schedule != nil {
+		r.schedule = temp.Schedule
+	}
 	r.renderAtStart = temp.RenderAtStart
 	r.dayOffset = temp.DayOffset
 	r.isRenderTelegramBot = temp.IsRenderTelegramBot
@@ -392,8 +598,217 @@ func (r *ReportsFinanceDaily) MarshalJSON() ([]byte, error) {
 		PollingInterval:     r.pollingInterval / reportsTimePeriod,
 		ErrRetryTaskLimit:   r.errRetryTaskLimit,
 		TaskTimeout:         r.taskTimeout / reportsTimePeriod,
+		Schedule:            r.schedule,
 		RenderAtStart:       r.renderAtStart,
 		DayOffset:           r.dayOffset,
 		IsRenderTelegramBot: r.isRenderTelegramBot,
 	})
 }
+
+// ReportsFinancePeriod Config of the finance roll-up over a calendar period (week or month)
+type ReportsFinancePeriod struct {
+	isEnabled            bool             // ro
+	errRetryTaskLimit    int              // ro
+	pollingInterval      time.Duration    // ro
+	taskTimeout          time.Duration    // ro
+	schedule             *ReportsSchedule // ro
+	renderAtStart        bool             // ro
+	periodOffset         int              // ro
+	isRenderTelegramBot  bool             // ro
+	isRenderGoogleSheets bool             // ro
+}
+
+type reportsFinancePeriod struct {
+	IsEnabled            bool             `json:"enabled"`
+	ErrRetryTaskLimit    int              `json:"err_retry_task_limit"`
+	PollingInterval      time.Duration    `json:"polling_interval"`
+	TaskTimeout          time.Duration    `json:"task_timeout"`
+	Schedule             *ReportsSchedule `json:"schedule"`
+	RenderAtStart        bool             `json:"render_at_start"`
+	PeriodOffset         int              `json:"period_offset"`
+	IsRenderTelegramBot  bool             `json:"render_telegram_bot"`
+	IsRenderGoogleSheets bool             `json:"render_google_sheets"`
+}
+
+func newReportsFinancePeriod() *ReportsFinancePeriod {
+	return &ReportsFinancePeriod{
+		isEnabled:            false,                         // default
+		errRetryTaskLimit:    3,                             // default
+		pollingInterval:      3_600_000 * reportsTimePeriod, // default
+		taskTimeout:          1_800_000 * reportsTimePeriod, // default
+		schedule:             newReportsSchedule(),          // default
+		renderAtStart:        false,                         // default
+		periodOffset:         -1,                            // default
+		isRenderTelegramBot:  false,                         // default
+		isRenderGoogleSheets: false,                         // default
+	}
+}
+
+func (r *ReportsFinancePeriod) IsEnabled() bool { return r.isEnabled }
+
+func (r *ReportsFinancePeriod) PollingInterval() time.Duration { return r.pollingInterval }
+
+func (r *ReportsFinancePeriod) TaskTimeout() time.Duration { return r.taskTimeout }
+
+// Schedule If it is calendar, the report runs by it instead of the polling interval
+func (r *ReportsFinancePeriod) Schedule() *ReportsSchedule { return r.schedule }
+
+func (r *ReportsFinancePeriod) ErrRetryTaskLimit() int { return r.errRetryTaskLimit }
+
+func (r *ReportsFinancePeriod) RenderAtStart() bool { return r.renderAtStart }
+
+// PeriodOffset Offset in periods from the current one, -1 is the previous week or month
+func (r *ReportsFinancePeriod) PeriodOffset() int { return r.periodOffset }
+
+func (r *ReportsFinancePeriod) IsRenderTelegramBot() bool  { return r.isRenderTelegramBot }
+func (r *ReportsFinancePeriod) IsRenderGoogleSheets() bool { return r.isRenderGoogleSheets }
+
+func (r *ReportsFinancePeriod) UnmarshalJSON(b []byte) error {
+	temp := &reportsFinancePeriod{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	r.isEnabled = temp.IsEnabled
+	r.pollingInterval = temp.PollingInterval * reportsTimePeriod
+	r.errRetryTaskLimit = temp.ErrRetryTaskLimit
+	r.taskTimeout = temp.TaskTimeout * reportsTimePeriod
+	r.schedule = newReportsSchedule()
+	if temp.Schedule != nil {
+		r.schedule = temp.Schedule
+	}
+	r.renderAtStart = temp.RenderAtStart
+	r.periodOffset = temp.PeriodOffset
+	r.isRenderTelegramBot = temp.IsRenderTelegramBot
+	r.isRenderGoogleSheets = temp.IsRenderGoogleSheets
+	return nil
+}
+
+func (r *ReportsFinancePeriod) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&reportsFinancePeriod{
+		IsEnabled:            r.isEnabled,
+		PollingInterval:      r.pollingInterval / reportsTimePeriod,
+		ErrRetryTaskLimit:    r.errRetryTaskLimit,
+		TaskTimeout:          r.taskTimeout / reportsTimePeriod,
+		Schedule:             r.schedule,
+		RenderAtStart:        r.renderAtStart,
+		PeriodOffset:         r.periodOffset,
+		IsRenderTelegramBot:  r.isRenderTelegramBot,
+		IsRenderGoogleSheets: r.isRenderGoogleSheets,
+	})
+}
+
+const (
+	ReportPeriodDay   = "day"
+	ReportPeriodWeek  = "week"
+	ReportPeriodMonth = "month"
+)
+
+// ReportsDriverPerformance Config of the report grouped by drivers over a calendar period (day, week or month)
+type ReportsDriverPerformance struct {
+	isEnabled            bool             // ro
+	errRetryTaskLimit    int              // ro
+	pollingInterval      time.Duration    // ro
+	taskTimeout          time.Duration    // ro
+	schedule             *ReportsSchedule // ro
+	renderAtStart        bool             // ro
+	period               string           // ro
+	periodOffset         int              // ro
+	isLoadAddresses      bool             // ro
+	isRenderTelegramBot  bool             // ro
+	isRenderGoogleSheets bool             // ro
+}
+
+type reportsDriverPerformance struct {
+	IsEnabled            bool             `json:"enabled"`
+	ErrRetryTaskLimit    int              `json:"err_retry_task_limit"`
+	PollingInterval      time.Duration    `json:"polling_interval"`
+	TaskTimeout          time.Duration    `json:"task_timeout"`
+	Schedule             *ReportsSchedule `json:"schedule"`
+	RenderAtStart        bool             `json:"render_at_start"`
+	Period               string           `json:"period"`
+	PeriodOffset         int              `json:"period_offset"`
+	IsLoadAddresses      bool             `json:"load_addresses"`
+	IsRenderTelegramBot  bool             `json:"render_telegram_bot"`
+	IsRenderGoogleSheets bool             `json:"render_google_sheets"`
+}
+
+func newReportsDriverPerformance() *ReportsDriverPerformance {
+	return &ReportsDriverPerformance{
+		isEnabled:            false,                         // default
+		errRetryTaskLimit:    3,                             // default
+		pollingInterval:      3_600_000 * reportsTimePeriod, // default
+		taskTimeout:          1_800_000 * reportsTimePeriod, // default
+		schedule:             newReportsSchedule(),          // default
+		renderAtStart:        false,                         // default
+		period:               ReportPeriodWeek,              // default
+		periodOffset:         -1,                            // default
+		isLoadAddresses:      true,                          // default
+		isRenderTelegramBot:  false,                         // default
+		isRenderGoogleSheets: false,                         // default
+	}
+}
+
+func (r *ReportsDriverPerformance) IsEnabled() bool { return r.isEnabled }
+
+func (r *ReportsDriverPerformance) PollingInterval() time.Duration { return r.pollingInterval }
+
+func (r *ReportsDriverPerformance) TaskTimeout() time.Duration { return r.taskTimeout }
+
+// Schedule If it is calendar, the report runs by it instead of the polling interval
+func (r *ReportsDriverPerformance) Schedule() *ReportsSchedule { return r.schedule }
+
+func (r *ReportsDriverPerformance) ErrRetryTaskLimit() int { return r.errRetryTaskLimit }
+
+func (r *ReportsDriverPerformance) RenderAtStart() bool { return r.renderAtStart }
+
+// Period One of ReportPeriodDay, ReportPeriodWeek, ReportPeriodMonth
+func (r *ReportsDriverPerformance) Period() string { return r.period }
+
+// PeriodOffset Offset in periods from the current one, -1 is the previous day, week or month
+func (r *ReportsDriverPerformance) PeriodOffset() int { return r.periodOffset }
+
+// IsLoadAddresses Whether to load way sheet info for completion of addresses, one request per way sheet
+func (r *ReportsDriverPerformance) IsLoadAddresses() bool { return r.isLoadAddresses }
+
+func (r *ReportsDriverPerformance) IsRenderTelegramBot() bool  { return r.isRenderTelegramBot }
+func (r *ReportsDriverPerformance) IsRenderGoogleSheets() bool { return r.isRenderGoogleSheets }
+
+func (r *ReportsDriverPerformance) UnmarshalJSON(b []byte) error {
+	temp := &reportsDriverPerformance{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	r.isEnabled = temp.IsEnabled
+	r.pollingInterval = temp.PollingInterval * reportsTimePeriod
+	r.errRetryTaskLimit = temp.ErrRetryTaskLimit
+	r.taskTimeout = temp.TaskTimeout * reportsTimePeriod
+	r.schedule = newReportsSchedule()
+	if temp.Schedule != nil {
+		r.schedule = temp.Schedule
+	}
+	r.renderAtStart = temp.RenderAtStart
+	r.period = temp.Period
+	r.periodOffset = temp.PeriodOffset
+	r.isLoadAddresses = temp.IsLoadAddresses
+	r.isRenderTelegramBot = temp.IsRenderTelegramBot
+	r.isRenderGoogleSheets = temp.IsRenderGoogleSheets
+	return nil
+}
+
+func (r *ReportsDriverPerformance) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&reportsDriverPerformance{
+		IsEnabled:            r.isEnabled,
+		PollingInterval:      r.pollingInterval / reportsTimePeriod,
+		ErrRetryTaskLimit:    r.errRetryTaskLimit,
+		TaskTimeout:          r.taskTimeout / reportsTimePeriod,
+		Schedule:             r.schedule,
+		RenderAtStart:        r.renderAtStart,
+		Period:               r.period,
+		PeriodOffset:         r.periodOffset,
+		IsLoadAddresses:      r.isLoadAddresses,
+		IsRenderTelegramBot:  r.isRenderTelegramBot,
+		IsRenderGoogleSheets: r.isRenderGoogleSheets,
+	})
+}
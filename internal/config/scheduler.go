@@ -0,0 +1,152 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Scheduler Retries and circuit breaker of the report tasks
+type Scheduler struct {
+	retry          *SchedulerRetry          // ro
+	circuitBreaker *SchedulerCircuitBreaker // ro
+}
+
+type scheduler struct {
+	Retry          *SchedulerRetry          `json:"retry"`
+	CircuitBreaker *SchedulerCircuitBreaker `json:"circuit_breaker"`
+}
+
+func newScheduler() *Scheduler {
+	return &Scheduler{
+		retry:          newSchedulerRetry(),          // default
+		circuitBreaker: newSchedulerCircuitBreaker(), // default
+	}
+}
+
+func (s *Scheduler) Retry() *SchedulerRetry                   { return s.retry }
+func (s *Scheduler) CircuitBreaker() *SchedulerCircuitBreaker { return s.circuitBreaker }
+
+func (s *Scheduler) UnmarshalJSON(b []byte) error {
+	temp := &scheduler{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	s.retry = newSchedulerRetry()
+	if temp.Retry != nil {
+		s.retry = temp.Retry
+	}
+	s.circuitBreaker = newSchedulerCircuitBreaker()
+	if temp.CircuitBreaker != nil {
+		s.circuitBreaker = temp.CircuitBreaker
+	}
+	return nil
+}
+
+func (s *Scheduler) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&scheduler{
+		Retry:          s.retry,
+		CircuitBreaker: s.circuitBreaker,
+	})
+}
+
+// SchedulerRetry Exponential backoff between the attempts of the task run
+type SchedulerRetry struct {
+	baseBackoff time.Duration // ro
+	maxBackoff  time.Duration // ro
+	multiplier  float64       // ro
+	jitter      float64       // ro
+}
+
+type schedulerRetry struct {
+	BaseBackoff time.Duration `json:"base_backoff"`
+	MaxBackoff  time.Duration `json:"max_backoff"`
+	Multiplier  float64       `json:"multiplier"`
+	Jitter      float64       `json:"jitter"`
+}
+
+func newSchedulerRetry() *SchedulerRetry {
+	return &SchedulerRetry{
+		baseBackoff: 2000 * reportsTimePeriod,   // default
+		maxBackoff:  60_000 * reportsTimePeriod, // default
+		multiplier:  2,                          // default
+		jitter:      0.2,                        // default
+	}
+}
+
+func (s *SchedulerRetry) BaseBackoff() time.Duration { return s.baseBackoff }
+
+// MaxBackoff If 0, the backoff is not limited
+func (s *SchedulerRetry) MaxBackoff() time.Duration { return s.maxBackoff }
+
+func (s *SchedulerRetry) Multiplier() float64 { return s.multiplier }
+
+// Jitter Fraction of the random deviation of the backoff from 0 to 1
+func (s *SchedulerRetry) Jitter() float64 { return s.jitter }
+
+func (s *SchedulerRetry) UnmarshalJSON(b []byte) error {
+	temp := &schedulerRetry{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	s.baseBackoff = temp.BaseBackoff * reportsTimePeriod
+	s.maxBackoff = temp.MaxBackoff * reportsTimePeriod
+	s.multiplier = temp.Multiplier
+	s.jitter = temp.Jitter
+	return nil
+}
+
+func (s *SchedulerRetry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&schedulerRetry{
+		BaseBackoff: s.baseBackoff / reportsTimePeriod,
+		MaxBackoff:  s.maxBackoff / reportsTimePeriod,
+		Multiplier:  s.multiplier,
+		Jitter:      s.jitter,
+	})
+}
+
+// SchedulerCircuitBreaker Stops running the report which uses WB API after the failed runs in a row until the cooldown passes
+type SchedulerCircuitBreaker struct {
+	isEnabled        bool          // ro
+	failureThreshold int           // ro
+	cooldown         time.Duration // ro
+}
+
+type schedulerCircuitBreaker struct {
+	IsEnabled        bool          `json:"enabled"`
+	FailureThreshold int           `json:"failure_threshold"`
+	Cooldown         time.Duration `json:"cooldown"`
+}
+
+func newSchedulerCircuitBreaker() *SchedulerCircuitBreaker {
+	return &SchedulerCircuitBreaker{
+		isEnabled:        false,                       // default
+		failureThreshold: 5,                           // default
+		cooldown:         300_000 * reportsTimePeriod, // default
+	}
+}
+
+func (s *SchedulerCircuitBreaker) IsEnabled() bool         { return s.isEnabled }
+func (s *SchedulerCircuitBreaker) FailureThreshold() int   { return s.failureThreshold }
+func (s *SchedulerCircuitBreaker) Cooldown() time.Duration { return s.cooldown }
+
+func (s *SchedulerCircuitBreaker) UnmarshalJSON(b []byte) error {
+	temp := &schedulerCircuitBreaker{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	s.isEnabled = temp.IsEnabled
+	s.failureThreshold = temp.FailureThreshold
+	s.cooldown = temp.Cooldown * reportsTimePeriod
+	return nil
+}
+
+func (s *SchedulerCircuitBreaker) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&schedulerCircuitBreaker{
+		IsEnabled:        s.isEnabled,
+		FailureThreshold: s.failureThreshold,
+		Cooldown:         s.cooldown / reportsTimePeriod,
+	})
+}
@@ -2,25 +2,47 @@ package config
 
 import (
 	"encoding/json"
+	"time"
 )
 
 type TelegramBot struct {
-	shipmentClose *TelegramBotParams
-	financeRoutes *TelegramBotParams
-	financeDaily  *TelegramBotParams
+	shipmentClose     *TelegramBotParams
+	financeRoutes     *TelegramBotParams
+	financeDaily      *TelegramBotParams
+	financeWeekly     *TelegramBotParams
+	financeMonthly    *TelegramBotParams
+	driverPerformance *TelegramBotParams
+	ratingAlerts      *TelegramBotParams
+	alerts            *TelegramBotParams
+	commands          *TelegramBotCommands
+	access            *TelegramBotAccess
 }
 
 type telegramBot struct {
-	ShipmentClose *TelegramBotParams `json:"shipment_close"`
-	FinanceRoutes *TelegramBotParams `json:"finance_routes"`
-	FinanceDaily  *TelegramBotParams `json:"finance_daily"`
+	ShipmentClose     *TelegramBotParams   `json:"shipment_close"`
+	FinanceRoutes     *TelegramBotParams   `json:"finance_routes"`
+	FinanceDaily      *TelegramBotParams   `json:"finance_daily"`
+	FinanceWeekly     *TelegramBotParams   `json:"finance_weekly"`
+	FinanceMonthly    *TelegramBotParams   `json:"finance_monthly"`
+	DriverPerformance *TelegramBotParams   `json:"driver_performance"`
+	RatingAlerts      *TelegramBotParams   `json:"rating_alerts"`
+	Alerts            *TelegramBotParams   `json:"alerts"`
+	Commands          *TelegramBotCommands `json:"commands"`
+	Access            *TelegramBotAccess   `json:"access"`
 }
 
 func newTelegramBot() *TelegramBot {
 	return &TelegramBot{
-		shipmentClose: newTelegramBotParams(),
-		financeRoutes: newTelegramBotParams(),
-		financeDaily:  newTelegramBotParams(),
+		shipmentClose:     newTelegramBotParams(),
+		financeRoutes:     newTelegramBotParams(),
+		financeDaily:      newTelegramBotParams(),
+		financeWeekly:     newTelegramBotParams(),
+		financeMonthly:    newTelegramBotParams(),
+		driverPerformance: newTelegramBotParams(),
+		ratingAlerts:      newTelegramBotParams(),
+		alerts:            newTelegramBotParams(),
+		commands:          newTelegramBotCommands(),
+		access:            newTelegramBotAccess(),
 	}
 }
 
@@ -33,6 +55,27 @@ func (t *TelegramBot) FinanceRoutes() *TelegramBotParams {
 func (t *TelegramBot) FinanceDaily() *TelegramBotParams {
 	return t.financeDaily
 }
+func (t *TelegramBot) FinanceWeekly() *TelegramBotParams {
+	return t.financeWeekly
+}
+func (t *TelegramBot) FinanceMonthly() *TelegramBotParams {
+	return t.financeMonthly
+}
+func (t *TelegramBot) DriverPerformance() *TelegramBotParams {
+	return t.driverPerformance
+}
+func (t *TelegramBot) RatingAlerts() *TelegramBotParams {
+	return t.ratingAlerts
+}
+func (t *TelegramBot) Alerts() *TelegramBotParams {
+	return t.alerts
+}
+func (t *TelegramBot) Commands() *TelegramBotCommands {
+	return t.commands
+}
+func (t *TelegramBot) Access() *TelegramBotAccess {
+	return t.access
+}
 
 func (t *TelegramBot) UnmarshalJSON(b []byte) error {
 	temp := &telegramBot{}
@@ -43,14 +86,42 @@ func (t *TelegramBot) UnmarshalJSON(b []byte) error {
 	t.shipmentClose = temp.ShipmentClose
 	t.financeRoutes = temp.FinanceRoutes
 	t.financeDaily = temp.FinanceDaily
+	if temp.FinanceWeekly != nil {
+		t.financeWeekly = temp.FinanceWeekly
+	}
+	if temp.FinanceMonthly != nil {
+		t.financeMonthly = temp.FinanceMonthly
+	}
+	if temp.DriverPerformance != nil {
+		t.driverPerformance = temp.DriverPerformance
+	}
+	if temp.RatingAlerts != nil {
+		t.ratingAlerts = temp.RatingAlerts
+	}
+	if temp.Alerts != nil {
+		t.alerts = temp.Alerts
+	}
+	if temp.Commands != nil {
+		t.commands = temp.Commands
+	}
+	if temp.Access != nil {
+		t.access = temp.Access
+	}
 	return nil
 }
 
 func (t *TelegramBot) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&telegramBot{
-		ShipmentClose: t.shipmentClose,
-		FinanceRoutes: t.financeRoutes,
-		FinanceDaily:  t.financeDaily,
+		ShipmentClose:     t.shipmentClose,
+		FinanceRoutes:     t.financeRoutes,
+		FinanceDaily:      t.financeDaily,
+		FinanceWeekly:     t.financeWeekly,
+		FinanceMonthly:    t.financeMonthly,
+		DriverPerformance: t.driverPerformance,
+		RatingAlerts:      t.ratingAlerts,
+		Alerts:            t.alerts,
+		Commands:          t.commands,
+		Access:            t.access,
 	})
 }
 
@@ -83,3 +154,159 @@ func (t *TelegramBotParams) UnmarshalJSON(b []byte) error {
 func (t *TelegramBotParams) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&telegramBotParams{ChatID: t.chatID})
 }
+
+// TelegramBotCommands Config of the long polling loop which answers the bot commands
+type TelegramBotCommands struct {
+	isEnabled      bool          // ro
+	pollingTimeout time.Duration // ro
+	pollingLimit   int           // ro
+	retryInterval  time.Duration // ro
+	authTimeout    time.Duration // ro
+}
+
+type telegramBotCommands struct {
+	IsEnabled      bool          `json:"enabled"`
+	PollingTimeout time.Duration `json:"polling_timeout"`
+	PollingLimit   int           `json:"polling_limit"`
+	RetryInterval  time.Duration `json:"retry_interval"`
+	AuthTimeout    time.Duration `json:"auth_timeout"`
+}
+
+func newTelegramBotCommands() *TelegramBotCommands {
+	return &TelegramBotCommands{
+		isEnabled:      false,                       // default
+		pollingTimeout: 10_000 * reportsTimePeriod,  // default
+		pollingLimit:   100,                         // default
+		retryInterval:  5000 * reportsTimePeriod,    // default
+		authTimeout:    600_000 * reportsTimePeriod, // default
+	}
+}
+
+func (t *TelegramBotCommands) IsEnabled() bool { return t.isEnabled }
+
+// PollingTimeout Time of the long polling request to wait for updates
+func (t *TelegramBotCommands) PollingTimeout() time.Duration { return t.pollingTimeout }
+
+func (t *TelegramBotCommands) PollingLimit() int { return t.pollingLimit }
+
+// RetryInterval Pause after the failed polling request
+func (t *TelegramBotCommands) RetryInterval() time.Duration { return t.retryInterval }
+
+// AuthTimeout Waiting for the answer of the admin chat on the step of the WB logistic authorization
+func (t *TelegramBotCommands) AuthTimeout() time.Duration { return t.authTimeout }
+
+func (t *TelegramBotCommands) UnmarshalJSON(b []byte) error {
+	temp := &telegramBotCommands{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	t.isEnabled = temp.IsEnabled
+	t.pollingTimeout = temp.PollingTimeout * reportsTimePeriod
+	t.pollingLimit = temp.PollingLimit
+	t.retryInterval = temp.RetryInterval * reportsTimePeriod
+	if temp.AuthTimeout > 0 {
+		t.authTimeout = temp.AuthTimeout * reportsTimePeriod
+	}
+	return nil
+}
+
+func (t *TelegramBotCommands) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&telegramBotCommands{
+		IsEnabled:      t.isEnabled,
+		PollingTimeout: t.pollingTimeout / reportsTimePeriod,
+		PollingLimit:   t.pollingLimit,
+		RetryInterval:  t.retryInterval / reportsTimePeriod,
+		AuthTimeout:    t.authTimeout / reportsTimePeriod,
+	})
+}
+
+const (
+	TelegramBotRoleViewer     = "viewer"
+	TelegramBotRoleDispatcher = "dispatcher"
+	TelegramBotRoleFinance    = "finance"
+	TelegramBotRoleAdmin      = "admin"
+)
+
+// TelegramBotAccess Config of the roles of the chats and users which are allowed to use the bot commands.
+// The roles of the chat are granted to everyone in it. In a group the command must be allowed both by the chat
+// and by the user if the user is listed, so the user roles do not extend the chat. In the private chat with the bot
+// the roles of the user apply
+type TelegramBotAccess struct {
+	isEnabled            bool               // ro
+	chats                map[int64][]string // ro
+	chatsTemp            map[string][]string
+	users                map[int64][]string // ro
+	usersTemp            map[string][]string
+	adminChatID          int64 // ro
+	isReportUnauthorized bool  // ro
+}
+
+type telegramBotAccess struct {
+	IsEnabled            bool                `json:"enabled"`
+	Chats                map[string][]string `json:"chats"`
+	Users                map[string][]string `json:"users"`
+	AdminChatID          int64               `json:"admin_chat_id"`
+	IsReportUnauthorized bool                `json:"report_unauthorized"`
+}
+
+func newTelegramBotAccess() *TelegramBotAccess {
+	return &TelegramBotAccess{
+		isEnabled:            false,                // default
+		chats:                map[int64][]string{}, // default
+		users:                map[int64][]string{}, // default
+		adminChatID:          0,                    // default
+		isReportUnauthorized: false,                // default
+	}
+}
+
+func (t *TelegramBotAccess) IsEnabled() bool { return t.isEnabled }
+
+// Chats chat id -> roles
+func (t *TelegramBotAccess) Chats() map[int64][]string { return t.chats }
+
+// Users user id -> roles
+func (t *TelegramBotAccess) Users() map[int64][]string { return t.users }
+
+func (t *TelegramBotAccess) AdminChatID() int64 { return t.adminChatID }
+
+// IsReportUnauthorized Send unauthorized attempts to the admin chat
+func (t *TelegramBotAccess) IsReportUnauthorized() bool { return t.isReportUnauthorized }
+
+func (t *TelegramBotAccess) UnmarshalJSON(b []byte) error {
+	temp := &telegramBotAccess{}
+	err := json.Unmarshal(b, temp)
+	if err != nil {
+		return err
+	}
+	t.isEnabled = temp.IsEnabled
+	t.adminChatID = temp.AdminChatID
+	t.isReportUnauthorized = temp.IsReportUnauthorized
+	t.chatsTemp = temp.Chats
+	t.usersTemp = temp.Users
+
+	t.chats = map[int64][]string{}
+	for chatID, roles := range temp.Chats {
+		if chatID != "" {
+			t.chats[atoiSafe64(chatID)] = roles
+		}
+	}
+
+	t.users = map[int64][]string{}
+	for userID, roles := range temp.Users {
+		if userID != "" {
+			t.users[atoiSafe64(userID)] = roles
+		}
+	}
+	return nil
+}
+
+func (t *TelegramBotAccess) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&telegramBotAccess{
+		IsEnabled:            t.isEnabled,
+		Chats:                t.chatsTemp,
+		Users:                t.usersTemp,
+		AdminChatID:          t.adminChatID,
+		IsReportUnauthorized: t.isReportUnauthorized,
+	})
+}
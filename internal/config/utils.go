@@ -15,6 +15,11 @@ func atoiSafe(s string) int {
 	return int(v)
 }
 
+func atoiSafe64(s string) int64 {
+	v, _ := strconv.ParseInt(s, 10, 64)
+	return v
+}
+
 func atofSafe(s string) float64 {
 	f, err := strconv.ParseFloat(s, 64)
 	if err != nil {
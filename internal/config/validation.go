@@ -1,13 +1,21 @@
 package config
 
 import (
+	"net"
+	"strings"
+	"time"
+	"wb_logistic_assistant/external/wb_logistic_api/request"
 	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
 )
 
 func validation(config *Config) error {
 	if config == nil {
 		return errors.Newf("Config.validation()", "config is nil")
 	}
+	if err := validationDebug(config.debug); err != nil {
+		return errors.Wrapf(err, "config.validation()", "config 'debug' validation failed")
+	}
 	if err := validationReports(config.reports); err != nil {
 		return errors.Wrapf(err, "config.validation()", "config 'reports' validation failed")
 	}
@@ -23,6 +31,24 @@ func validation(config *Config) error {
 	if err := validationTelegramBot(config.telegram); err != nil {
 		return errors.Wrapf(err, "config.validation()", "config 'telegramBot' validation failed")
 	}
+	if err := validationHistory(config.history); err != nil {
+		return errors.Wrapf(err, "config.validation()", "config 'history' validation failed")
+	}
+	if err := validationAlerts(config.alerts); err != nil {
+		return errors.Wrapf(err, "config.validation()", "config 'alerts' validation failed")
+	}
+	if err := validationScheduler(config.scheduler); err != nil {
+		return errors.Wrapf(err, "config.validation()", "config 'scheduler' validation failed")
+	}
+	if err := validationOutbox(config.outbox); err != nil {
+		return errors.Wrapf(err, "config.validation()", "config 'outbox' validation failed")
+	}
+	if err := validationHTTPServer(config.httpServer); err != nil {
+		return errors.Wrapf(err, "config.validation()", "config 'http_server' validation failed")
+	}
+	if err := validationHeadless(config.headless); err != nil {
+		return errors.Wrapf(err, "config.validation()", "config 'headless' validation failed")
+	}
 	return nil
 }
 
@@ -59,6 +85,19 @@ func validationReports(config *Reports) error {
 	if generalRoutes.sortColumn < 0 {
 		return errors.New("config.validationReports()", "'general_routes.sort_column' is invalid")
 	}
+	ratingAlerts := generalRoutes.ratingAlerts
+	if ratingAlerts == nil {
+		return errors.New("config.validationReports()", "'general_routes.rating_alerts' is nil")
+	}
+	if ratingAlerts.threshold <= 0 {
+		return errors.New("config.validationReports()", "'general_routes.rating_alerts.threshold' is invalid, it must be > 0")
+	}
+	if ratingAlerts.window <= 0 {
+		return errors.New("config.validationReports()", "'general_routes.rating_alerts.window' is invalid, it must be > 0")
+	}
+	if err := validationReportsSchedule("general_routes", generalRoutes.schedule); err != nil {
+		return err
+	}
 
 	shipmentsClose := config.shipmentClose
 	if shipmentsClose == nil {
@@ -76,6 +115,9 @@ func validationReports(config *Reports) error {
 	if shipmentsClose.intervalUpdateShipments < 0 {
 		return errors.New("config.validationReports()", "'shipment_close.interval_update_shipments' is it must be > 0")
 	}
+	if err := validationReportsSchedule("shipment_close", shipmentsClose.schedule); err != nil {
+		return err
+	}
 
 	financeRoutes := config.financeRoutes
 	if financeRoutes == nil {
@@ -93,6 +135,9 @@ func validationReports(config *Reports) error {
 	if financeRoutes.renderDelay < 0 {
 		return errors.New("config.validationReports()", "'finance_routes.report_delay' is invalid, it must be >= 0")
 	}
+	if err := validationReportsSchedule("finance_routes", financeRoutes.schedule); err != nil {
+		return err
+	}
 
 	financeDaily := config.financeDaily
 	if financeDaily == nil {
@@ -107,10 +152,91 @@ func validationReports(config *Reports) error {
 	if financeDaily.taskTimeout <= 0 {
 		return errors.New("config.validationReports()", "'finance_daily.task_timeout' is invalid, it must be > 0")
 	}
+	if err := validationReportsSchedule("finance_daily", financeDaily.schedule); err != nil {
+		return err
+	}
+
+	if err := validationReportsFinancePeriod("finance_weekly", config.financeWeekly); err != nil {
+		return err
+	}
+	if err := validationReportsFinancePeriod("finance_monthly", config.financeMonthly); err != nil {
+		return err
+	}
+	if err := validationReportsDriverPerformance(config.driverPerformance); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+func validationReportsDriverPerformance(config *ReportsDriverPerformance) error {
+	if config == nil {
+		return errors.New("config.validationReportsDriverPerformance()", "'driver_performance' is nil")
+	}
+	if config.pollingInterval <= 0 {
+		return errors.New("config.validationReportsDriverPerformance()", "'driver_performance.polling_interval' is invalid, it must be > 0")
+	}
+	if config.errRetryTaskLimit <= 0 {
+		return errors.New("config.validationReportsDriverPerformance()", "'driver_performance.err_retry_limit' is invalid, it must be > 0")
+	}
+	if config.taskTimeout <= 0 {
+		return errors.New("config.validationReportsDriverPerformance()", "'driver_performance.task_timeout' is invalid, it must be > 0")
+	}
+	if config.period != ReportPeriodDay && config.period != ReportPeriodWeek && config.period != ReportPeriodMonth {
+		return errors.Newf("config.validationReportsDriverPerformance()", "'driver_performance.period' is invalid, it must be one of '%s', '%s', '%s'", ReportPeriodDay, ReportPeriodWeek, ReportPeriodMonth)
+	}
+	if config.periodOffset > 0 {
+		return errors.New("config.validationReportsDriverPerformance()", "'driver_performance.period_offset' is invalid, it must be <= 0")
+	}
+	return validationReportsSchedule("driver_performance", config.schedule)
+}
+
+func validationReportsFinancePeriod(name string, config *ReportsFinancePeriod) error {
+	if config == nil {
+		return errors.Newf("config.validationReportsFinancePeriod()", "'%s' is nil", name)
+	}
+	if config.pollingInterval <= 0 {
+		return errors.Newf("config.validationReportsFinancePeriod()", "'%s.polling_interval' is invalid, it must be > 0", name)
+	}
+	if config.errRetryTaskLimit <= 0 {
+		return errors.Newf("config.validationReportsFinancePeriod()", "'%s.err_retry_limit' is invalid, it must be > 0", name)
+	}
+	if config.taskTimeout <= 0 {
+		return errors.Newf("config.validationReportsFinancePeriod()", "'%s.task_timeout' is invalid, it must be > 0", name)
+	}
+	if config.periodOffset > 0 {
+		return errors.Newf("config.validationReportsFinancePeriod()", "'%s.period_offset' is invalid, it must be <= 0", name)
+	}
+	return validationReportsSchedule(name, config.schedule)
+}
+
+// validationReportsSchedule The cron expression itself is parsed by the scheduler at the initialization
+func validationReportsSchedule(name string, config *ReportsSchedule) error {
+	if config == nil {
+		return errors.Newf("config.validationReportsSchedule()", "'%s.schedule' is nil", name)
+	}
+	if config.cron != "" && len(config.at) > 0 {
+		return errors.Newf("config.validationReportsSchedule()", "'%s.schedule' is invalid, only one of 'cron' and 'at' may be set", name)
+	}
+	if _, err := time.LoadLocation(config.timezone); err != nil {
+		return errors.Newf("config.validationReportsSchedule()", "'%s.schedule.timezone' is invalid: %v", name, err)
+	}
+	for _, at := range config.at {
+		if _, err := time.Parse("15:04", at); err != nil {
+			return errors.Newf("config.validationReportsSchedule()", "'%s.schedule.at' is invalid, '%s' must be in the format HH:MM", name, at)
+		}
+	}
+	if config.IsWindow() {
+		if _, err := time.Parse("15:04", config.from); err != nil {
+			return errors.Newf("config.validationReportsSchedule()", "'%s.schedule.from' is invalid, it must be in the format HH:MM", name)
+		}
+		if _, err := time.Parse("15:04", config.to); err != nil {
+			return errors.Newf("config.validationReportsSchedule()", "'%s.schedule.to' is invalid, it must be in the format HH:MM", name)
+		}
+	}
+	return nil
+}
+
 func validationStorage(config *Storage) error {
 	if config == nil {
 		return errors.New("config.validationStorage()", "config is nil")
@@ -138,6 +264,36 @@ func validationLogistic(config *Logistic) error {
 	if config.wbClient.platform == "" {
 		return errors.New("config.validationLogistic()", "'wb_client.platform' is empty")
 	}
+	endpoints := request.NewEndpoints()
+	for service, baseURL := range config.wbClient.endpoints {
+		if err := endpoints.Set(service, baseURL); err != nil {
+			return errors.Wrap(err, "config.validationLogistic()", "'wb_client.endpoints' is invalid")
+		}
+	}
+	for service, limit := range config.wbClient.rateLimits {
+		if service != LogisticRateLimitDefault && !request.IsService(service) {
+			return errors.Newf("config.validationLogistic()", "'wb_client.rate_limits' has unknown service '%s', supported: %s, %s",
+				service, LogisticRateLimitDefault, strings.Join(request.Services(), ", "))
+		}
+		if limit == nil {
+			return errors.Newf("config.validationLogistic()", "'wb_client.rate_limits.%s' is nil", service)
+		}
+		if limit.rate < 0 {
+			return errors.Newf("config.validationLogistic()", "'wb_client.rate_limits.%s.rate' is invalid, it must be >= 0", service)
+		}
+		if limit.burst < 0 {
+			return errors.Newf("config.validationLogistic()", "'wb_client.rate_limits.%s.burst' is invalid, it must be >= 0", service)
+		}
+		if limit.concurrency < 0 {
+			return errors.Newf("config.validationLogistic()", "'wb_client.rate_limits.%s.concurrency' is invalid, it must be >= 0", service)
+		}
+	}
+	switch config.wbClient.cassetteMode {
+	case LogisticCassetteOff, LogisticCassetteRecord, LogisticCassetteReplay:
+	default:
+		return errors.Newf("config.validationLogistic()", "'wb_client.cassette_mode' is invalid '%s', it must be '%s', '%s' or '%s'",
+			config.wbClient.cassetteMode, LogisticCassetteOff, LogisticCassetteRecord, LogisticCassetteReplay)
+	}
 
 	if config.office == nil {
 		return errors.New("config.validationLogistic()", "'office' is nil")
@@ -232,6 +388,16 @@ func validationGoogleSheets(config *GoogleSheets) error {
 		return errors.New("config.validationGoogleSheets()", "'report_sheets.shipment_close.sheet_name' is empty")
 	}
 
+	if config.reportSheets.financeWeekly == nil {
+		return errors.New("config.validationGoogleSheets()", "'report_sheets.finance_weekly' is nil")
+	}
+	if config.reportSheets.financeMonthly == nil {
+		return errors.New("config.validationGoogleSheets()", "'report_sheets.finance_monthly' is nil")
+	}
+	if config.reportSheets.driverPerformance == nil {
+		return errors.New("config.validationGoogleSheets()", "'report_sheets.driver_performance' is nil")
+	}
+
 	return nil
 }
 
@@ -262,5 +428,244 @@ func validationTelegramBot(config *TelegramBot) error {
 	if financeDaily.chatID == 0 {
 		return errors.New("config.validationTelegramBot()", "'telegram_bot.finance_daily.chat_id' is it not must be 0")
 	}
+
+	if config.financeWeekly == nil {
+		return errors.New("config.validationTelegramBot()", "'telegram_bot.finance_weekly' is nil")
+	}
+	if config.financeMonthly == nil {
+		return errors.New("config.validationTelegramBot()", "'telegram_bot.finance_monthly' is nil")
+	}
+	if config.driverPerformance == nil {
+		return errors.New("config.validationTelegramBot()", "'telegram_bot.driver_performance' is nil")
+	}
+	if config.ratingAlerts == nil {
+		return errors.New("config.validationTelegramBot()", "'telegram_bot.rating_alerts' is nil")
+	}
+	if config.alerts == nil {
+		return errors.New("config.validationTelegramBot()", "'telegram_bot.alerts' is nil")
+	}
+
+	commands := config.commands
+	if commands == nil {
+		return errors.New("config.validationTelegramBot()", "'telegram_bot.commands' is nil")
+	}
+	if commands.isEnabled {
+		if commands.pollingTimeout < time.Second {
+			return errors.New("config.validationTelegramBot()", "'telegram_bot.commands.polling_timeout' is invalid, it must be >= 1000")
+		}
+		if commands.pollingLimit <= 0 || commands.pollingLimit > 100 {
+			return errors.New("config.validationTelegramBot()", "'telegram_bot.commands.polling_limit' is invalid, it must be in [1, 100]")
+		}
+		if commands.retryInterval <= 0 {
+			return errors.New("config.validationTelegramBot()", "'telegram_bot.commands.retry_interval' is invalid, it must be > 0")
+		}
+	}
+
+	access := config.access
+	if access == nil {
+		return errors.New("config.validationTelegramBot()", "'telegram_bot.access' is nil")
+	}
+	if access.isEnabled {
+		for chatID, roles := range access.chats {
+			if chatID == 0 {
+				return errors.New("config.validationTelegramBot()", "'telegram_bot.access.chats' contains invalid chat id")
+			}
+			if err := validationTelegramBotRoles(roles); err != nil {
+				return errors.Wrapf(err, "config.validationTelegramBot()", "'telegram_bot.access.chats.%d' is invalid", chatID)
+			}
+		}
+		for userID, roles := range access.users {
+			if userID <= 0 {
+				return errors.New("config.validationTelegramBot()", "'telegram_bot.access.users' contains invalid user id")
+			}
+			if err := validationTelegramBotRoles(roles); err != nil {
+				return errors.Wrapf(err, "config.validationTelegramBot()", "'telegram_bot.access.users.%d' is invalid", userID)
+			}
+		}
+		if access.isReportUnauthorized && access.adminChatID == 0 {
+			return errors.New("config.validationTelegramBot()", "'telegram_bot.access.admin_chat_id' is it not must be 0 if 'report_unauthorized' is enabled")
+		}
+	}
+	return nil
+}
+
+func validationTelegramBotRoles(roles []string) error {
+	for _, role := range roles {
+		switch role {
+		case TelegramBotRoleViewer, TelegramBotRoleDispatcher, TelegramBotRoleFinance, TelegramBotRoleAdmin:
+		default:
+			return errors.Newf("config.validationTelegramBotRoles()", "unknown role %s, it must be one of: %s, %s, %s, %s",
+				role, TelegramBotRoleViewer, TelegramBotRoleDispatcher, TelegramBotRoleFinance, TelegramBotRoleAdmin)
+		}
+	}
+	return nil
+}
+
+func validationHistory(config *History) error {
+	if config == nil {
+		return errors.New("config.validationHistory()", "config is nil")
+	}
+	if !config.enabled {
+		return nil
+	}
+	if config.path == "" {
+		return errors.New("config.validationHistory()", "'history.path' is empty")
+	}
+	if config.retentionDays < 0 {
+		return errors.New("config.validationHistory()", "'history.retention_days' is invalid, it must be >= 0")
+	}
+	return nil
+}
+
+func validationAlerts(config *Alerts) error {
+	if config == nil {
+		return errors.New("config.validationAlerts()", "config is nil")
+	}
+	if !config.isEnabled {
+		return nil
+	}
+	names := make(map[string]struct{}, len(config.rules))
+	for i, rule := range config.rules {
+		if rule == nil {
+			return errors.Newf("config.validationAlerts()", "'alerts.rules[%d]' is nil", i)
+		}
+		if rule.name == "" {
+			return errors.Newf("config.validationAlerts()", "'alerts.rules[%d].name' is empty", i)
+		}
+		if _, ok := names[rule.name]; ok {
+			return errors.Newf("config.validationAlerts()", "'alerts.rules[%d].name' %s is duplicated", i, rule.name)
+		}
+		names[rule.name] = struct{}{}
+		if rule.source != AlertsSourceGeneralRoutes && rule.source != AlertsSourceFinanceDaily {
+			return errors.Newf("config.validationAlerts()", "'alerts.rules[%d].source' is invalid, it must be one of: %s, %s", i, AlertsSourceGeneralRoutes, AlertsSourceFinanceDaily)
+		}
+		if rule.metric == "" {
+			return errors.Newf("config.validationAlerts()", "'alerts.rules[%d].metric' is empty", i)
+		}
+		switch rule.operator {
+		case ">", ">=", "<", "<=", "==", "!=":
+		default:
+			return errors.Newf("config.validationAlerts()", "'alerts.rules[%d].operator' is invalid, it must be one of: >, >=, <, <=, ==, !=", i)
+		}
+		if rule.duration < 0 {
+			return errors.Newf("config.validationAlerts()", "'alerts.rules[%d].for' is invalid, it must be >= 0", i)
+		}
+		if rule.cooldown < 0 {
+			return errors.Newf("config.validationAlerts()", "'alerts.rules[%d].cooldown' is invalid, it must be >= 0", i)
+		}
+	}
+	return nil
+}
+
+func validationScheduler(config *Scheduler) error {
+	if config == nil {
+		return errors.New("config.validationScheduler()", "config is nil")
+	}
+
+	retry := config.retry
+	if retry.baseBackoff <= 0 {
+		return errors.New("config.validationScheduler()", "'retry.base_backoff' is invalid, it must be > 0")
+	}
+	if retry.maxBackoff < 0 {
+		return errors.New("config.validationScheduler()", "'retry.max_backoff' is invalid, it must be >= 0")
+	}
+	if retry.multiplier < 1 {
+		return errors.New("config.validationScheduler()", "'retry.multiplier' is invalid, it must be >= 1")
+	}
+	if retry.jitter < 0 || retry.jitter > 1 {
+		return errors.New("config.validationScheduler()", "'retry.jitter' is invalid, it must be from 0 to 1")
+	}
+
+	circuitBreaker := config.circuitBreaker
+	if circuitBreaker.isEnabled {
+		if circuitBreaker.failureThreshold <= 0 {
+			return errors.New("config.validationScheduler()", "'circuit_breaker.failure_threshold' is invalid, it must be > 0")
+		}
+		if circuitBreaker.cooldown <= 0 {
+			return errors.New("config.validationScheduler()", "'circuit_breaker.cooldown' is invalid, it must be > 0")
+		}
+	}
+	return nil
+}
+
+func validationOutbox(config *Outbox) error {
+	if config == nil {
+		return errors.New("config.validationOutbox()", "config is nil")
+	}
+	if config.path == "" {
+		return errors.New("config.validationOutbox()", "'outbox.path' is empty")
+	}
+	if config.maxAttempts <= 0 {
+		return errors.New("config.validationOutbox()", "'outbox.max_attempts' is invalid, it must be > 0")
+	}
+	if config.baseBackoff <= 0 {
+		return errors.New("config.validationOutbox()", "'outbox.base_backoff' is invalid, it must be > 0")
+	}
+	if config.maxBackoff < 0 {
+		return errors.New("config.validationOutbox()", "'outbox.max_backoff' is invalid, it must be >= 0")
+	}
+	return nil
+}
+
+func validationHTTPServer(config *HTTPServer) error {
+	if config == nil {
+		return errors.New("config.validationHTTPServer()", "config is nil")
+	}
+	if !config.enabled {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(config.address); err != nil {
+		return errors.Wrap(err, "config.validationHTTPServer()", "'http_server.address' is invalid, it must be in the 'host:port' format")
+	}
+	if config.staleIntervals <= 0 {
+		return errors.New("config.validationHTTPServer()", "'http_server.stale_intervals' is invalid, it must be > 0")
+	}
+	return nil
+}
+
+func validationHeadless(config *Headless) error {
+	if config == nil {
+		return errors.New("config.validationHeadless()", "config is nil")
+	}
+	switch config.delegate {
+	case HeadlessDelegateNone:
+		return nil
+	case HeadlessDelegateHTTP:
+		if _, _, err := net.SplitHostPort(config.address); err != nil {
+			return errors.Wrap(err, "config.validationHeadless()", "'headless.address' is invalid, it must be in the 'host:port' format")
+		}
+	case HeadlessDelegateTelegram:
+		if config.telegramChatID == 0 {
+			return errors.New("config.validationHeadless()", "'headless.telegram_chat_id' is empty")
+		}
+	default:
+		return errors.Newf("config.validationHeadless()", "'headless.delegate' is invalid: '%s', supported: none, http, telegram", config.delegate)
+	}
+	if config.timeout <= 0 {
+		return errors.New("config.validationHeadless()", "'headless.timeout' is invalid, it must be > 0")
+	}
+	return nil
+}
+
+func validationDebug(config *Debug) error {
+	if config == nil {
+		return errors.New("config.validationDebug()", "config is nil")
+	}
+	if _, err := logger.ParseLevel(config.level); err != nil {
+		return errors.Wrap(err, "config.validationDebug()", "'debug.level' is invalid")
+	}
+	if _, err := logger.ParseLevel(config.consoleLevel); err != nil {
+		return errors.Wrap(err, "config.validationDebug()", "'debug.console_level' is invalid")
+	}
+	if _, err := logger.NewEncoder(config.format); err != nil {
+		return errors.Wrap(err, "config.validationDebug()", "'debug.format' is invalid, it must be 'text' or 'json'")
+	}
+	rotation := config.rotation
+	if rotation == nil {
+		return errors.New("config.validationDebug()", "'rotation' is nil")
+	}
+	if rotation.maxSizeMB < 0 || rotation.interval < 0 || rotation.maxBackups < 0 || rotation.maxAgeDays < 0 {
+		return errors.New("config.validationDebug()", "'rotation' values must be >= 0")
+	}
 	return nil
 }
@@ -31,3 +31,11 @@ func Wrapf(err error, location, format string, args ...interface{}) error {
 func Is(err, target error) bool {
 	return errors.Is(err, target)
 }
+
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
+
+func Join(errs ...error) error {
+	return errors.Join(errs...)
+}
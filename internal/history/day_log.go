@@ -0,0 +1,148 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
+)
+
+const (
+	dayFileLayout = "2006-01-02"
+	dayFileExt    = ".jsonl"
+	maxLineSize   = 1024 * 1024
+)
+
+// dayLog Append-only log of JSON lines, one file per UTC day. Not safe for concurrent use, callers hold their own lock
+type dayLog[T any] struct {
+	dir           string
+	retentionDays int
+	lastPrune     time.Time
+}
+
+func newDayLog[T any](dir string, retentionDays int) (*dayLog[T], error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "dayLog.new()", "failed to create history directory %s", dir)
+	}
+	return &dayLog[T]{
+		dir:           dir,
+		retentionDays: retentionDays,
+	}, nil
+}
+
+func (l *dayLog[T]) appendFile(name string, data []byte) error {
+	path := filepath.Join(l.dir, name)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "dayLog.appendFile()", "failed to open history file %s", path)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(data); err != nil {
+		return errors.Wrapf(err, "dayLog.appendFile()", "failed to write history file %s", path)
+	}
+	if err = file.Sync(); err != nil {
+		return errors.Wrapf(err, "dayLog.appendFile()", "failed to sync history file %s", path)
+	}
+	return nil
+}
+
+func (l *dayLog[T]) readFile(name string, fn func(item *T)) error {
+	path := filepath.Join(l.dir, name)
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "dayLog.readFile()", "failed to open history file %s", path)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		item := new(T)
+		if err = json.Unmarshal(scanner.Bytes(), item); err != nil {
+			// the tail of the file may be broken if the process was killed during the write
+			logger.Logf(logger.WARN, "dayLog.readFile()", "skip broken line %d in history file %s: %v", line, path, err)
+			continue
+		}
+		fn(item)
+	}
+	if err = scanner.Err(); err != nil {
+		return errors.Wrapf(err, "dayLog.readFile()", "failed to read history file %s", path)
+	}
+	return nil
+}
+
+// readRange Calls fn for every item of the day files covering [from, to]
+func (l *dayLog[T]) readRange(from, to time.Time, fn func(item *T)) error {
+	day := startOfDayUTC(from)
+	for !day.After(to) {
+		if err := l.readFile(l.dayFileName(day), fn); err != nil {
+			return errors.Wrap(err, "dayLog.readRange()", "")
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return nil
+}
+
+// dayFiles Returns names of the day files sorted from the oldest to the newest
+func (l *dayLog[T]) dayFiles() ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "dayLog.dayFiles()", "failed to read history directory %s", l.dir)
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), dayFileExt) {
+			continue
+		}
+		files = append(files, entry.Name())
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// prune Removes day files older than retention, at most once a day
+func (l *dayLog[T]) prune(now time.Time) {
+	if l.retentionDays <= 0 || now.Sub(l.lastPrune) < 24*time.Hour {
+		return
+	}
+	l.lastPrune = now
+
+	files, err := l.dayFiles()
+	if err != nil {
+		logger.Logf(logger.WARN, "dayLog.prune()", "failed to list history files: %v", err)
+		return
+	}
+
+	border := l.dayFileName(now.AddDate(0, 0, -l.retentionDays))
+	for _, name := range files {
+		if name >= border {
+			break
+		}
+		if err = os.Remove(filepath.Join(l.dir, name)); err != nil {
+			logger.Logf(logger.WARN, "dayLog.prune()", "failed to remove history file %s: %v", name, err)
+		}
+	}
+}
+
+func (l *dayLog[T]) dayFileName(t time.Time) string {
+	return t.UTC().Format(dayFileLayout) + dayFileExt
+}
+
+func startOfDayUTC(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
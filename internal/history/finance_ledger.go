@@ -0,0 +1,280 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
+)
+
+const financeLedgerFile = "finance_ledger.jsonl"
+
+const (
+	FinanceSourceRoutes = "finance_routes"
+	FinanceSourceDaily  = "finance_daily"
+)
+
+// FinanceEntry Computed finance of one closed way sheet together with the config rates used for the calculation
+type FinanceEntry struct {
+	WaySheetID         string    `json:"way_sheet_id"`
+	RouteID            int       `json:"route_id"`
+	SupplierID         int       `json:"supplier_id"`
+	DriverID           string    `json:"driver_id"`
+	DriverName         string    `json:"driver_name"`
+	VehicleNumberPlate string    `json:"vehicle_number_plate"`
+	DateOpen           time.Time `json:"date_open"`
+	DateClose          time.Time `json:"date_close"`
+
+	TotalPrice float64 `json:"total_price"`
+	SumFine    float64 `json:"sum_fine"`
+	SumReturn  float64 `json:"sum_return"`
+	SalaryRate float64 `json:"salary_rate"`
+	Defect     float64 `json:"defect"`
+	Tax        float64 `json:"tax"`
+	Margin     float64 `json:"margin"`
+
+	// Rates in force at the time of calculation
+	ConfigSalaryRate        float64 `json:"config_salary_rate"`         // fixed rate of the route, 0 if not set
+	ConfigSalaryRatePercent float64 `json:"config_salary_rate_percent"` // percent rate of the route, 0 if not set
+	ConfigPercentDefect     float64 `json:"config_percent_defect"`
+	ConfigPercentTax        float64 `json:"config_percent_tax"`
+
+	Source    string    `json:"source"` // reporter that computed the entry
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewFinanceEntry(waySheet *wb_models.WaySheet, routeID, supplierID int) *FinanceEntry {
+	if waySheet == nil {
+		return nil
+	}
+	return &FinanceEntry{
+		WaySheetID:         waySheet.WaySheetID,
+		RouteID:            routeID,
+		SupplierID:         supplierID,
+		DriverID:           waySheet.DriverID,
+		DriverName:         waySheet.DriverName,
+		VehicleNumberPlate: waySheet.VehicleNumberPlate,
+		DateOpen:           waySheet.OpenDt,
+		DateClose:          waySheet.CloseDt,
+		TotalPrice:         waySheet.TotalPrice,
+		SumFine:            waySheet.SumFine,
+		SumReturn:          waySheet.SumReturn,
+	}
+}
+
+// equal Compares entries ignoring source and update time
+func (e *FinanceEntry) equal(other *FinanceEntry) bool {
+	a, b := *e, *other
+	a.Source, b.Source = "", ""
+	a.UpdatedAt, b.UpdatedAt = time.Time{}, time.Time{}
+	return a == b
+}
+
+type FinanceLedger interface {
+	// Upsert Inserts or replaces the entry by way sheet ID, an unchanged entry is not written again
+	Upsert(entry *FinanceEntry) error
+	// Insert Adds the entry only if the way sheet has none, returns false if the existing entry is kept
+	Insert(entry *FinanceEntry) (bool, error)
+	// Get Returns the entry by way sheet ID, nil if there is none
+	Get(waySheetID string) *FinanceEntry
+	// Query Returns entries closed in [from, to] ordered by close date
+	Query(from, to time.Time) []*FinanceEntry
+}
+
+// FileFinanceLedger Ledger kept in memory and persisted as append-only JSON lines, the last line of a way sheet wins
+type FileFinanceLedger struct {
+	mtx     sync.RWMutex
+	path    string
+	entries map[string]*FinanceEntry // way sheet id -> entry
+}
+
+func NewFileFinanceLedger(path string) (*FileFinanceLedger, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, errors.Wrapf(err, "FileFinanceLedger.New()", "failed to create history directory %s", path)
+	}
+	l := &FileFinanceLedger{
+		path:    filepath.Join(path, financeLedgerFile),
+		entries: map[string]*FinanceEntry{},
+	}
+
+	lines, err := l.load()
+	if err != nil {
+		return nil, errors.Wrap(err, "FileFinanceLedger.New()", "")
+	}
+
+	// replaced entries only take up space, rewrite the file if there are too many of them
+	if lines > 2*len(l.entries) {
+		if err = l.compact(); err != nil {
+			return nil, errors.Wrap(err, "FileFinanceLedger.New()", "")
+		}
+	}
+
+	return l, nil
+}
+
+func (l *FileFinanceLedger) Upsert(entry *FinanceEntry) error {
+	if entry == nil || entry.WaySheetID == "" {
+		return errors.New("FileFinanceLedger.Upsert()", "entry or way sheet id is empty")
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if existing, ok := l.entries[entry.WaySheetID]; ok && existing.equal(entry) {
+		return nil
+	}
+	return l.write("FileFinanceLedger.Upsert()", entry)
+}
+
+func (l *FileFinanceLedger) Insert(entry *FinanceEntry) (bool, error) {
+	if entry == nil || entry.WaySheetID == "" {
+		return false, errors.New("FileFinanceLedger.Insert()", "entry or way sheet id is empty")
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if _, ok := l.entries[entry.WaySheetID]; ok {
+		return false, nil
+	}
+	if err := l.write("FileFinanceLedger.Insert()", entry); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// write Appends the entry to the file and keeps it in memory, must be called under the lock
+func (l *FileFinanceLedger) write(location string, entry *FinanceEntry) error {
+	if entry.UpdatedAt.IsZero() {
+		entry.UpdatedAt = time.Now()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrapf(err, location, "failed to encode entry of way sheet %s", entry.WaySheetID)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrapf(err, location, "failed to open ledger file %s", l.path)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		return errors.Wrapf(err, location, "failed to write ledger file %s", l.path)
+	}
+	if err = file.Sync(); err != nil {
+		return errors.Wrapf(err, location, "failed to sync ledger file %s", l.path)
+	}
+
+	l.entries[entry.WaySheetID] = entry
+	return nil
+}
+
+func (l *FileFinanceLedger) Get(waySheetID string) *FinanceEntry {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	entry, ok := l.entries[waySheetID]
+	if !ok {
+		return nil
+	}
+	copyEntry := *entry
+	return &copyEntry
+}
+
+func (l *FileFinanceLedger) Query(from, to time.Time) []*FinanceEntry {
+	l.mtx.RLock()
+	result := make([]*FinanceEntry, 0)
+	for _, entry := range l.entries {
+		if entry.DateClose.Before(from) || entry.DateClose.After(to) {
+			continue
+		}
+		copyEntry := *entry
+		result = append(result, &copyEntry)
+	}
+	l.mtx.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DateClose.Equal(result[j].DateClose) {
+			return result[i].WaySheetID < result[j].WaySheetID
+		}
+		return result[i].DateClose.Before(result[j].DateClose)
+	})
+	return result
+}
+
+// load Replays the ledger file, returns the count of read lines
+func (l *FileFinanceLedger) load() (int, error) {
+	file, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "FileFinanceLedger.load()", "failed to open ledger file %s", l.path)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	lines := 0
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		lines++
+		entry := &FinanceEntry{}
+		if err = json.Unmarshal(scanner.Bytes(), entry); err != nil || entry.WaySheetID == "" {
+			// the tail of the file may be broken if the process was killed during the write
+			logger.Logf(logger.WARN, "FileFinanceLedger.load()", "skip broken line %d in ledger file %s: %v", lines, l.path, err)
+			continue
+		}
+		l.entries[entry.WaySheetID] = entry
+	}
+	if err = scanner.Err(); err != nil {
+		return 0, errors.Wrapf(err, "FileFinanceLedger.load()", "failed to read ledger file %s", l.path)
+	}
+	return lines, nil
+}
+
+// compact Rewrites the ledger file with only actual entries
+func (l *FileFinanceLedger) compact() error {
+	var buf bytes.Buffer
+	for _, entry := range l.entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return errors.Wrapf(err, "FileFinanceLedger.compact()", "failed to encode entry of way sheet %s", entry.WaySheetID)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	// the temporary file is synced before the rename, otherwise a crash may leave the ledger empty
+	tmpPath := l.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "FileFinanceLedger.compact()", "failed to open temporary file %s", tmpPath)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(buf.Bytes()); err != nil {
+		return errors.Wrapf(err, "FileFinanceLedger.compact()", "failed to write temporary file %s", tmpPath)
+	}
+	if err = file.Sync(); err != nil {
+		return errors.Wrapf(err, "FileFinanceLedger.compact()", "failed to sync temporary file %s", tmpPath)
+	}
+	if err = file.Close(); err != nil {
+		return errors.Wrapf(err, "FileFinanceLedger.compact()", "failed to close temporary file %s", tmpPath)
+	}
+
+	if err = os.Rename(tmpPath, l.path); err != nil {
+		return errors.Wrapf(err, "FileFinanceLedger.compact()", "failed to rename temporary file %s to %s", tmpPath, l.path)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package history
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFileFinanceLedger(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	entry := func(id string, salaryRate float64) *FinanceEntry {
+		return &FinanceEntry{
+			WaySheetID: id,
+			RouteID:    200,
+			DateClose:  day.Add(12 * time.Hour),
+			TotalPrice: 5000,
+			SalaryRate: salaryRate,
+		}
+	}
+
+	ledger, err := NewFileFinanceLedger(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ledger.Upsert(entry("500", 1000)); err != nil {
+		t.Fatal(err)
+	}
+	// the replaced entries are compacted when the ledger is opened again
+	for i := 1; i <= 5; i++ {
+		if err = ledger.Upsert(entry("501", float64(i*100))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inserted, err := ledger.Insert(entry("500", 2000))
+	if err != nil || inserted {
+		t.Fatalf("existing entry is replaced by insert: %v, %v", inserted, err)
+	}
+	if inserted, err = ledger.Insert(entry("502", 2000)); err != nil || !inserted {
+		t.Fatalf("missing entry is not inserted: %v, %v", inserted, err)
+	}
+
+	path := filepath.Join(dir, financeLedgerFile)
+	if lines := countLines(t, path); lines != 7 {
+		t.Fatalf("ledger file has %d lines, want 7", lines)
+	}
+
+	for reopen := 0; reopen < 2; reopen++ {
+		ledger, err = NewFileFinanceLedger(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := countLines(t, path); lines != 3 {
+			t.Fatalf("reopen %d: ledger file has %d lines, want 3", reopen, lines)
+		}
+		want := map[string]float64{"500": 1000, "501": 500, "502": 2000}
+		entries := ledger.Query(day, day.Add(24*time.Hour))
+		if len(entries) != len(want) {
+			t.Fatalf("reopen %d: got %d entries, want %d", reopen, len(entries), len(want))
+		}
+		for i, e := range entries {
+			if e.WaySheetID != strconv.Itoa(500+i) || e.SalaryRate != want[e.WaySheetID] {
+				t.Fatalf("reopen %d: entry %+v, want salary rate %v", reopen, e, want[e.WaySheetID])
+			}
+		}
+	}
+	if _, err = os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temporary file is left after the compaction: %v", err)
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(data, []byte{'\n'})
+}
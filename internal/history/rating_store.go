@@ -0,0 +1,132 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
+	"wb_logistic_assistant/internal/errors"
+)
+
+const ratingsDir = "ratings"
+
+// RatingSnapshot All components of the route rating at the time of one update
+type RatingSnapshot struct {
+	Time        time.Time `json:"time"`
+	RouteID     int       `json:"route_id"`
+	Overall     float64   `json:"overall"`
+	BufferSpeed float64   `json:"buffer_speed"`
+	RoadSpeed   float64   `json:"road_speed"`
+	Brak        float64   `json:"brak"`
+	Pretensions float64   `json:"pretensions"`
+	ActiveDays  float64   `json:"active_days"`
+	NoReturn    float64   `json:"no_return"`
+	Authorized  float64   `json:"authorized"`
+}
+
+func NewRatingSnapshot(t time.Time, routeID int, rating *wb_models.RouteRating) *RatingSnapshot {
+	if rating == nil {
+		return nil
+	}
+	return &RatingSnapshot{
+		Time:        t,
+		RouteID:     routeID,
+		Overall:     rating.OverallRating,
+		BufferSpeed: rating.BufferSpeedRating,
+		RoadSpeed:   rating.RoadSpeedRating,
+		Brak:        rating.BrakRating,
+		Pretensions: rating.PretensionsRating,
+		ActiveDays:  rating.ActiveDaysRating,
+		NoReturn:    rating.NoReturnRating,
+		Authorized:  rating.AuthorizedRating,
+	}
+}
+
+type RatingStore interface {
+	// Append Persists snapshots of one rating update
+	Append(snapshots []*RatingSnapshot) error
+	// Query Returns snapshots of the route in [from, to] ordered by time. If routeID <= 0, returns all routes
+	Query(routeID int, from, to time.Time) ([]*RatingSnapshot, error)
+}
+
+// FileRatingStore Append-only log of rating snapshots, one JSON line per snapshot and one file per UTC day
+type FileRatingStore struct {
+	mtx sync.RWMutex
+	log *dayLog[RatingSnapshot]
+}
+
+func NewFileRatingStore(path string, retentionDays int) (*FileRatingStore, error) {
+	log, err := newDayLog[RatingSnapshot](filepath.Join(path, ratingsDir), retentionDays)
+	if err != nil {
+		return nil, errors.Wrap(err, "FileRatingStore.New()", "")
+	}
+	return &FileRatingStore{log: log}, nil
+}
+
+func (s *FileRatingStore) Append(snapshots []*RatingSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	buffers := map[string]*bytes.Buffer{}
+	for _, snapshot := range snapshots {
+		if snapshot == nil {
+			continue
+		}
+		name := s.log.dayFileName(snapshot.Time)
+		buf := buffers[name]
+		if buf == nil {
+			buf = &bytes.Buffer{}
+			buffers[name] = buf
+		}
+		line, err := json.Marshal(snapshot)
+		if err != nil {
+			return errors.Wrapf(err, "FileRatingStore.Append()", "failed to encode rating of route %d", snapshot.RouteID)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for name, buf := range buffers {
+		if err := s.log.appendFile(name, buf.Bytes()); err != nil {
+			return errors.Wrap(err, "FileRatingStore.Append()", "")
+		}
+	}
+
+	s.log.prune(time.Now())
+	return nil
+}
+
+func (s *FileRatingStore) Query(routeID int, from, to time.Time) ([]*RatingSnapshot, error) {
+	if to.Before(from) {
+		return nil, errors.Newf("FileRatingStore.Query()", "invalid period %s - %s", from, to)
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	result := make([]*RatingSnapshot, 0)
+	err := s.log.readRange(from, to, func(snapshot *RatingSnapshot) {
+		if routeID > 0 && snapshot.RouteID != routeID {
+			return
+		}
+		if snapshot.Time.Before(from) || snapshot.Time.After(to) {
+			return
+		}
+		result = append(result, snapshot)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "FileRatingStore.Query()", "")
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package history
+
+import (
+	"time"
+	"wb_logistic_assistant/internal/reports"
+)
+
+// RouteSnapshot State of one route at the time of one GeneralRoutesReporter cycle
+type RouteSnapshot struct {
+	Time    time.Time `json:"time"`
+	RouteID int       `json:"route_id"`
+	Parking int       `json:"parking"`
+	Tares   int       `json:"tares"`
+
+	VolumeLiters                 float32 `json:"volume_liters"`
+	VolumeNormativeLiters        float32 `json:"volume_normative_liters"`
+	VolumeNormativeLitersPercent float32 `json:"volume_normative_liters_percent"`
+
+	Barcodes        int     `json:"barcodes"`
+	ChangeBarcodes  int     `json:"change_barcodes"`
+	RemainsBarcodes int     `json:"remains_barcodes"`
+	Rating          float32 `json:"rating"`
+
+	ShipmentID         int       `json:"shipment_id"`
+	ShipmentCreateDate time.Time `json:"shipment_create_date"`
+	ShipmentCloseDate  time.Time `json:"shipment_close_date"`
+
+	WaySheetID                   int       `json:"way_sheet_id"`
+	WaySheetDateLastOperation    time.Time `json:"way_sheet_date_last_operation"`
+	WaySheetTotalAddresses       int       `json:"way_sheet_total_addresses"`
+	WaySheetCurrentAddresses     int       `json:"way_sheet_current_addresses"`
+	WaySheetTotalReturnedTares   int       `json:"way_sheet_total_returned_tares"`
+	WaySheetCurrentReturnedTares int       `json:"way_sheet_current_returned_tares"`
+
+	PrevWaySheetID                   int       `json:"prev_way_sheet_id"`
+	PrevWaySheetDateLastOperation    time.Time `json:"prev_way_sheet_date_last_operation"`
+	PrevWaySheetTotalAddresses       int       `json:"prev_way_sheet_total_addresses"`
+	PrevWaySheetCurrentAddresses     int       `json:"prev_way_sheet_current_addresses"`
+	PrevWaySheetTotalReturnedTares   int       `json:"prev_way_sheet_total_returned_tares"`
+	PrevWaySheetCurrentReturnedTares int       `json:"prev_way_sheet_current_returned_tares"`
+
+	WaySheetsInterval time.Duration `json:"way_sheets_interval"`
+}
+
+func NewRouteSnapshot(t time.Time, data *reports.GeneralRoutesReportData) *RouteSnapshot {
+	if data == nil {
+		return nil
+	}
+	return &RouteSnapshot{
+		Time:                             t,
+		RouteID:                          data.RouteID,
+		Parking:                          data.Parking,
+		Tares:                            data.Tares,
+		VolumeLiters:                     data.VolumeLiters,
+		VolumeNormativeLiters:            data.VolumeNormativeLiters,
+		VolumeNormativeLitersPercent:     data.VolumeNormativeLitersPercent,
+		Barcodes:                         data.Barcodes,
+		ChangeBarcodes:                   data.ChangeBarcodes,
+		RemainsBarcodes:                  data.RemainsBarcodes,
+		Rating:                           data.Rating,
+		ShipmentID:                       data.ShipmentID,
+		ShipmentCreateDate:               data.ShipmentCreateDate,
+		ShipmentCloseDate:                data.ShipmentCloseDate,
+		WaySheetID:                       data.WaySheetID,
+		WaySheetDateLastOperation:        data.WaySheetDateLastOperation,
+		WaySheetTotalAddresses:           data.WaySheetTotalAddresses,
+		WaySheetCurrentAddresses:         data.WaySheetCurrentAddresses,
+		WaySheetTotalReturnedTares:       data.WaySheetTotalReturnedTares,
+		WaySheetCurrentReturnedTares:     data.WaySheetCurrentReturnedTares,
+		PrevWaySheetID:                   data.PrevWaySheetID,
+		PrevWaySheetDateLastOperation:    data.PrevWaySheetDateLastOperation,
+		PrevWaySheetTotalAddresses:       data.PrevWaySheetTotalAddresses,
+		PrevWaySheetCurrentAddresses:     data.PrevWaySheetCurrentAddresses,
+		PrevWaySheetTotalReturnedTares:   data.PrevWaySheetTotalReturnedTares,
+		PrevWaySheetCurrentReturnedTares: data.PrevWaySheetCurrentReturnedTares,
+		WaySheetsInterval:                data.WaySheetsInterval,
+	}
+}
+
+// ReportData Converts snapshot back to the report data, e.g. for re-rendering the report from history
+func (s *RouteSnapshot) ReportData() *reports.GeneralRoutesReportData {
+	return &reports.GeneralRoutesReportData{
+		RouteID:                          s.RouteID,
+		Parking:                          s.Parking,
+		Tares:                            s.Tares,
+		VolumeLiters:                     s.VolumeLiters,
+		VolumeNormativeLiters:            s.VolumeNormativeLiters,
+		VolumeNormativeLitersPercent:     s.VolumeNormativeLitersPercent,
+		Barcodes:                         s.Barcodes,
+		ChangeBarcodes:                   s.ChangeBarcodes,
+		Rating:                           s.Rating,
+		RemainsBarcodes:                  s.RemainsBarcodes,
+		ShipmentID:                       s.ShipmentID,
+		ShipmentCreateDate:               s.ShipmentCreateDate,
+		ShipmentCloseDate:                s.ShipmentCloseDate,
+		WaySheetID:                       s.WaySheetID,
+		WaySheetDateLastOperation:        s.WaySheetDateLastOperation,
+		WaySheetTotalAddresses:           s.WaySheetTotalAddresses,
+		WaySheetCurrentAddresses:         s.WaySheetCurrentAddresses,
+		WaySheetTotalReturnedTares:       s.WaySheetTotalReturnedTares,
+		WaySheetCurrentReturnedTares:     s.WaySheetCurrentReturnedTares,
+		PrevWaySheetID:                   s.PrevWaySheetID,
+		PrevWaySheetDateLastOperation:    s.PrevWaySheetDateLastOperation,
+		PrevWaySheetTotalAddresses:       s.PrevWaySheetTotalAddresses,
+		PrevWaySheetCurrentAddresses:     s.PrevWaySheetCurrentAddresses,
+		PrevWaySheetTotalReturnedTares:   s.PrevWaySheetTotalReturnedTares,
+		PrevWaySheetCurrentReturnedTares: s.PrevWaySheetCurrentReturnedTares,
+		WaySheetsInterval:                s.WaySheetsInterval,
+	}
+}
@@ -0,0 +1,136 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+const routesDir = "routes"
+
+type RouteStore interface {
+	// Append Persists snapshots of one report cycle
+	Append(snapshots []*RouteSnapshot) error
+	// Query Returns snapshots of the route in [from, to] ordered by time. If routeID <= 0, returns all routes
+	Query(routeID int, from, to time.Time) ([]*RouteSnapshot, error)
+	// At Returns the last snapshot of the route taken not later than t, nil if there is none
+	At(routeID int, t time.Time) (*RouteSnapshot, error)
+}
+
+// FileRouteStore Append-only log of route snapshots, one JSON line per snapshot and one file per UTC day
+type FileRouteStore struct {
+	mtx sync.RWMutex
+	log *dayLog[RouteSnapshot]
+}
+
+func NewFileRouteStore(path string, retentionDays int) (*FileRouteStore, error) {
+	log, err := newDayLog[RouteSnapshot](filepath.Join(path, routesDir), retentionDays)
+	if err != nil {
+		return nil, errors.Wrap(err, "FileRouteStore.New()", "")
+	}
+	return &FileRouteStore{log: log}, nil
+}
+
+func (s *FileRouteStore) Append(snapshots []*RouteSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	// snapshots of one cycle can cross midnight only in theory, group them by file anyway
+	buffers := map[string]*bytes.Buffer{}
+	for _, snapshot := range snapshots {
+		if snapshot == nil {
+			continue
+		}
+		name := s.log.dayFileName(snapshot.Time)
+		buf := buffers[name]
+		if buf == nil {
+			buf = &bytes.Buffer{}
+			buffers[name] = buf
+		}
+		line, err := json.Marshal(snapshot)
+		if err != nil {
+			return errors.Wrapf(err, "FileRouteStore.Append()", "failed to encode snapshot of route %d", snapshot.RouteID)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for name, buf := range buffers {
+		if err := s.log.appendFile(name, buf.Bytes()); err != nil {
+			return errors.Wrap(err, "FileRouteStore.Append()", "")
+		}
+	}
+
+	s.log.prune(time.Now())
+	return nil
+}
+
+func (s *FileRouteStore) Query(routeID int, from, to time.Time) ([]*RouteSnapshot, error) {
+	if to.Before(from) {
+		return nil, errors.Newf("FileRouteStore.Query()", "invalid period %s - %s", from, to)
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	result := make([]*RouteSnapshot, 0)
+	err := s.log.readRange(from, to, func(snapshot *RouteSnapshot) {
+		if routeID > 0 && snapshot.RouteID != routeID {
+			return
+		}
+		if snapshot.Time.Before(from) || snapshot.Time.After(to) {
+			return
+		}
+		result = append(result, snapshot)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "FileRouteStore.Query()", "")
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result, nil
+}
+
+func (s *FileRouteStore) At(routeID int, t time.Time) (*RouteSnapshot, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	files, err := s.log.dayFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "FileRouteStore.At()", "")
+	}
+
+	// from the newest file to the oldest, the first file with a matching snapshot contains the answer
+	last := s.log.dayFileName(t)
+	for i := len(files) - 1; i >= 0; i-- {
+		if files[i] > last {
+			continue
+		}
+		var found *RouteSnapshot
+		err = s.log.readFile(files[i], func(snapshot *RouteSnapshot) {
+			if snapshot.RouteID != routeID || snapshot.Time.After(t) {
+				return
+			}
+			if found == nil || !snapshot.Time.Before(found.Time) {
+				found = snapshot
+			}
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "FileRouteStore.At()", "")
+		}
+		if found != nil {
+			return found, nil
+		}
+	}
+	return nil, nil
+}
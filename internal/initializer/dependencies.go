@@ -6,19 +6,37 @@ import (
 	"wb_logistic_assistant/internal/scheduler"
 	"wb_logistic_assistant/internal/services"
 	"wb_logistic_assistant/internal/storage"
+	"wb_logistic_assistant/internal/telegram_commands"
 )
 
 type AppDependencies struct {
-	Config                           *config.Config
-	Storage                          storage.Storage
-	Services                         *services.Container
-	Scheduler                        scheduler.Scheduler
-	SchedulerGeneralRoutesTaskConfig *scheduler.TaskConfig
-	SchedulerShipmentCloseTaskConfig *scheduler.TaskConfig
-	SchedulerFinanceRoutesTaskConfig *scheduler.TaskConfig
-	SchedulerFinanceDailyTaskConfig  *scheduler.TaskConfig
-	GeneralRoutesReporter            reporters.Reporter
-	ShipmentCloseReporter            reporters.Reporter
-	FinanceRoutesReporter            reporters.Reporter
-	FinanceDailyReporter             reporters.Reporter
+	Config                               *config.Config
+	Storage                              storage.Storage
+	Services                             *services.Container
+	Scheduler                            scheduler.Scheduler
+	SchedulerGeneralRoutesTaskConfig     *scheduler.TaskConfig
+	SchedulerShipmentCloseTaskConfig     *scheduler.TaskConfig
+	SchedulerFinanceRoutesTaskConfig     *scheduler.TaskConfig
+	SchedulerFinanceDailyTaskConfig      *scheduler.TaskConfig
+	SchedulerFinanceWeeklyTaskConfig     *scheduler.TaskConfig
+	SchedulerFinanceMonthlyTaskConfig    *scheduler.TaskConfig
+	SchedulerDriverPerformanceTaskConfig *scheduler.TaskConfig
+	SchedulerGeneralRoutesSchedule       scheduler.Schedule // nil if the report runs by the polling interval
+	SchedulerShipmentCloseSchedule       scheduler.Schedule
+	SchedulerFinanceRoutesSchedule       scheduler.Schedule
+	SchedulerFinanceDailySchedule        scheduler.Schedule
+	SchedulerFinanceWeeklySchedule       scheduler.Schedule
+	SchedulerFinanceMonthlySchedule      scheduler.Schedule
+	SchedulerDriverPerformanceSchedule   scheduler.Schedule
+	SchedulerCircuitBreaker              *scheduler.CircuitBreakerConfig // nil if disabled, shared by the report tasks
+	GeneralRoutesReporter                reporters.Reporter
+	ShipmentCloseReporter                reporters.Reporter
+	FinanceRoutesReporter                reporters.Reporter
+	FinanceDailyReporter                 reporters.Reporter
+	FinanceWeeklyReporter                reporters.Reporter
+	FinanceMonthlyReporter               reporters.Reporter
+	DriverPerformanceReporter            reporters.Reporter
+	FinanceDailyBackfiller               reporters.Backfiller
+	TelegramCommands                     *telegram_commands.Dispatcher
+	WBAuthPrompter                       *telegram_commands.WBAuthPrompter // nil if the commands or the admin chat are disabled
 }
@@ -1,18 +1,24 @@
 package initializer
 
 import (
+	"context"
+	"wb_logistic_assistant/internal/alerts"
 	"wb_logistic_assistant/internal/config"
 	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/history"
 	"wb_logistic_assistant/internal/initializer/google_sheets"
 	"wb_logistic_assistant/internal/initializer/telegram_bot"
 	"wb_logistic_assistant/internal/initializer/wb_logistic"
 	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/metrics"
 	"wb_logistic_assistant/internal/models"
+	"wb_logistic_assistant/internal/outbox"
 	"wb_logistic_assistant/internal/prompters"
 	"wb_logistic_assistant/internal/reporters"
 	"wb_logistic_assistant/internal/scheduler"
 	"wb_logistic_assistant/internal/services"
 	"wb_logistic_assistant/internal/storage"
+	"wb_logistic_assistant/internal/telegram_commands"
 )
 
 type Initializer struct {
@@ -62,117 +68,411 @@ func (i *Initializer) Init() (*AppDependencies, error) {
 		return nil, errors.Wrap(err, "Initializer.Init()", "")
 	}
 
-	i.initScheduler()
+	err = i.initHistory()
+	if err != nil {
+		return nil, errors.Wrap(err, "Initializer.Init()", "")
+	}
+
+	err = i.initOutbox()
+	if err != nil {
+		return nil, errors.Wrap(err, "Initializer.Init()", "")
+	}
+
+	err = i.initAlerts()
+	if err != nil {
+		return nil, errors.Wrap(err, "Initializer.Init()", "")
+	}
+
+	err = i.initScheduler()
+	if err != nil {
+		return nil, errors.Wrap(err, "Initializer.Init()", "")
+	}
 
 	i.initReporters()
 
+	i.initTelegramCommands()
+
 	logger.Log(logger.INFO, "Initializer.Init()", "Finish init application dependencies, successfully initialized")
 	i.prompter.PromptInitFinish()
 	return i.dependencies, nil
 }
 
+// InitBackfill init only the services needed to recompute reports, without the scheduler
+func (i *Initializer) InitBackfill(isTelegramBot, isGoogleSheets bool) (*AppDependencies, error) {
+	logger.Log(logger.INFO, "Initializer.InitBackfill()", "Start init backfill dependencies")
+
+	err := i.initWBLogisticService(i.config.Logistic().CacheTTL())
+	if err != nil {
+		return nil, errors.Wrap(err, "Initializer.InitBackfill()", "")
+	}
+
+	if isGoogleSheets {
+		err = i.initGoogleSheetsService()
+		if err != nil {
+			return nil, errors.Wrap(err, "Initializer.InitBackfill()", "")
+		}
+	}
+
+	if isTelegramBot {
+		err = i.initTelegramBotService()
+		if err != nil {
+			return nil, errors.Wrap(err, "Initializer.InitBackfill()", "")
+		}
+	}
+
+	if i.config.History().IsEnabled() {
+		financeLedger, err := history.NewFileFinanceLedger(i.config.History().Path())
+		if err != nil {
+			return nil, errors.Wrap(err, "Initializer.InitBackfill()", "Failed to init finance ledger")
+		}
+		i.services.FinanceLedger = financeLedger
+	}
+
+	financeDailyReporter := reporters.NewFinanceDailyReporter(i.config, i.storage, i.services, &prompters.CLIReporterFinanceDailyPrompter{})
+	i.dependencies.FinanceDailyReporter = financeDailyReporter
+	i.dependencies.FinanceDailyBackfiller = financeDailyReporter
+
+	logger.Log(logger.INFO, "Initializer.InitBackfill()", "Finish init backfill dependencies, successfully initialized")
+	i.prompter.PromptInitFinish()
+	return i.dependencies, nil
+}
+
 func (i *Initializer) initWBLogistic(ttl *config.LogisticCacheTTL) error {
 	if i.config.Reports().GeneralRoutes().IsEnabled() ||
 		i.config.Reports().ShipmentClose().IsEnabled() ||
 		i.config.Reports().FinanceRoutes().IsEnabled() ||
-		i.config.Reports().FinanceDaily().IsEnabled() {
+		i.config.Reports().FinanceDaily().IsEnabled() ||
+		i.config.Reports().FinanceWeekly().IsEnabled() ||
+		i.config.Reports().FinanceMonthly().IsEnabled() ||
+		i.config.Reports().DriverPerformance().IsEnabled() {
 
-		wbLogisticClient, wbLogisticSession, err := i.wbLogistic.Init()
+		err := i.initWBLogisticService(ttl)
 		if err != nil {
-			return errors.Wrap(err, "Initializer.initWBLogistic()", "Failed to init WB Logistic client")
+			return errors.Wrap(err, "Initializer.initWBLogistic()", "")
 		}
-		i.services.WBLogisticService = services.NewBaseWBLogisticService(wbLogisticClient, wbLogisticSession, &models.WBLogisticTTlParams{
-			UserInfo:                        ttl.UserInfo(),
-			RemainsLastMileReports:          ttl.RemainsLastMileReports(),
-			RemainsLastMileReportsRouteInfo: ttl.RemainsLastMileReportsRouteInfo(),
-			JobsScheduling:                  ttl.JobsScheduling(),
-			ShipmentInfo:                    ttl.ShipmentInfo(),
-			ShipmentTransfers:               ttl.ShipmentTransfers(),
-			WaySheetInfo:                    ttl.WaySheetInfo(),
-			WaySheetFinanceDetails:          ttl.WaySheetFinanceDetails(),
-		})
 	} else {
 		return errors.New("Initializer.initWBLogistic()", "All reports are disabled")
 	}
 	return nil
 }
 
+func (i *Initializer) initWBLogisticService(ttl *config.LogisticCacheTTL) error {
+	wbLogisticClient, wbLogisticSession, err := i.wbLogistic.Init()
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initWBLogisticService()", "Failed to init WB Logistic client")
+	}
+	i.services.WBLogisticService = services.NewBaseWBLogisticService(wbLogisticClient, wbLogisticSession, &models.WBLogisticTTlParams{
+		UserInfo:                        ttl.UserInfo(),
+		RemainsLastMileReports:          ttl.RemainsLastMileReports(),
+		RemainsLastMileReportsRouteInfo: ttl.RemainsLastMileReportsRouteInfo(),
+		JobsScheduling:                  ttl.JobsScheduling(),
+		ShipmentInfo:                    ttl.ShipmentInfo(),
+		ShipmentTransfers:               ttl.ShipmentTransfers(),
+		WaySheetInfo:                    ttl.WaySheetInfo(),
+		WaySheetFinanceDetails:          ttl.WaySheetFinanceDetails(),
+	})
+	metrics.WBSessionExpiry.Set(func() (float64, bool) {
+		expiresAt := i.services.WBLogisticService.SessionTokenExpiresAt()
+		return float64(expiresAt.Unix()), !expiresAt.IsZero()
+	})
+	return nil
+}
+
 // InitDirectWBLogistic init without storage data
 func (i *Initializer) InitDirectWBLogistic() error {
+	return i.initDirectWBLogistic(i.wbLogistic)
+}
+
+// InitDirectWBLogisticWith init without storage data, the auth steps are asked by the prompter, e.g. in the Telegram admin chat
+func (i *Initializer) InitDirectWBLogisticWith(prompter prompters.InitializeWBLogisticPrompter) error {
+	return i.initDirectWBLogistic(wb_logistic.NewInitializer(i.config, i.storage, prompter))
+}
+
+func (i *Initializer) initDirectWBLogistic(wbLogistic *wb_logistic.Initializer) error {
 	if i.config.Reports().GeneralRoutes().IsEnabled() ||
 		i.config.Reports().ShipmentClose().IsEnabled() ||
 		i.config.Reports().FinanceRoutes().IsEnabled() ||
-		i.config.Reports().FinanceDaily().IsEnabled() {
+		i.config.Reports().FinanceDaily().IsEnabled() ||
+		i.config.Reports().FinanceWeekly().IsEnabled() ||
+		i.config.Reports().FinanceMonthly().IsEnabled() ||
+		i.config.Reports().DriverPerformance().IsEnabled() {
 
-		wbLogisticClient, wbLogisticSession, err := i.wbLogistic.InitDirect()
+		wbLogisticClient, wbLogisticSession, err := wbLogistic.InitDirect()
 		if err != nil {
-			return errors.Wrap(err, "Initializer.InitDirectWBLogistic()", "Failed to init WB Logistic client")
+			return errors.Wrap(err, "Initializer.initDirectWBLogistic()", "Failed to init WB Logistic client")
 		}
 		i.services.WBLogisticService.SetClient(wbLogisticClient)
 		i.services.WBLogisticService.SetSession(wbLogisticSession)
 	} else {
-		return errors.New("Initializer.InitDirectWBLogistic()", "All reports are disabled")
+		return errors.New("Initializer.initDirectWBLogistic()", "All reports are disabled")
 	}
 	return nil
 }
 
 func (i *Initializer) initGoogleSheets() error {
 	if (i.config.Reports().GeneralRoutes().IsEnabled() && i.config.Reports().GeneralRoutes().IsRenderGoogleSheets()) ||
-		(i.config.Reports().ShipmentClose().IsEnabled() && i.config.Reports().ShipmentClose().IsRenderGoogleSheets()) {
-		googleSheetsClient, googleSheetsActor, err := i.googleSheets.Init()
+		(i.config.Reports().ShipmentClose().IsEnabled() && i.config.Reports().ShipmentClose().IsRenderGoogleSheets()) ||
+		(i.config.Reports().FinanceWeekly().IsEnabled() && i.config.Reports().FinanceWeekly().IsRenderGoogleSheets()) ||
+		(i.config.Reports().FinanceMonthly().IsEnabled() && i.config.Reports().FinanceMonthly().IsRenderGoogleSheets()) ||
+		(i.config.Reports().DriverPerformance().IsEnabled() && i.config.Reports().DriverPerformance().IsRenderGoogleSheets()) {
+		err := i.initGoogleSheetsService()
 		if err != nil {
-			return errors.Wrap(err, "Initializer.initGoogleSheets()", "Failed to init Google Sheets client")
+			return errors.Wrap(err, "Initializer.initGoogleSheets()", "")
 		}
-		i.services.GoogleSheetsService = services.NewBaseGoogleSheetsService(googleSheetsClient, googleSheetsActor)
 	}
 	return nil
 }
 
+func (i *Initializer) initGoogleSheetsService() error {
+	googleSheetsClient, googleSheetsActor, err := i.googleSheets.Init()
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initGoogleSheetsService()", "Failed to init Google Sheets client")
+	}
+	i.services.GoogleSheetsService = services.NewBaseGoogleSheetsService(googleSheetsClient, googleSheetsActor)
+	return nil
+}
+
 func (i *Initializer) initTelegramBot() error {
-	if (i.config.Reports().ShipmentClose().IsEnabled() && i.config.Reports().ShipmentClose().IsRenderTelegramBot()) ||
+	if i.config.Alerts().IsEnabled() ||
+		i.config.Telegram().Commands().IsEnabled() ||
+		(i.config.Reports().GeneralRoutes().IsEnabled() && i.config.Reports().GeneralRoutes().RatingAlerts().IsEnabled()) ||
+		(i.config.Reports().ShipmentClose().IsEnabled() && i.config.Reports().ShipmentClose().IsRenderTelegramBot()) ||
 		(i.config.Reports().FinanceRoutes().IsEnabled() && i.config.Reports().FinanceRoutes().IsRenderTelegramBot()) ||
-		(i.config.Reports().FinanceDaily().IsEnabled() && i.config.Reports().FinanceDaily().IsRenderTelegramBot()) {
-		telegramBot, err := i.telegramBot.Init()
+		(i.config.Reports().FinanceDaily().IsEnabled() && i.config.Reports().FinanceDaily().IsRenderTelegramBot()) ||
+		(i.config.Reports().FinanceWeekly().IsEnabled() && i.config.Reports().FinanceWeekly().IsRenderTelegramBot()) ||
+		(i.config.Reports().FinanceMonthly().IsEnabled() && i.config.Reports().FinanceMonthly().IsRenderTelegramBot()) ||
+		(i.config.Reports().DriverPerformance().IsEnabled() && i.config.Reports().DriverPerformance().IsRenderTelegramBot()) {
+		err := i.initTelegramBotService()
+		if err != nil {
+			return errors.Wrap(err, "Initializer.initTelegramBot()", "")
+		}
+	}
+	return nil
+}
+
+func (i *Initializer) initTelegramBotService() error {
+	telegramBot, err := i.telegramBot.Init()
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initTelegramBotService()", "Failed to init Telegram Bot client")
+	}
+	i.services.TelegramBotService = services.NewTelegramBotAPIService(telegramBot)
+	return nil
+}
+
+func (i *Initializer) initHistory() error {
+	if i.config.History().IsEnabled() && i.config.Reports().GeneralRoutes().IsEnabled() {
+		routeHistory, err := history.NewFileRouteStore(i.config.History().Path(), i.config.History().RetentionDays())
+		if err != nil {
+			return errors.Wrap(err, "Initializer.initHistory()", "Failed to init route history store")
+		}
+		i.services.RouteHistory = routeHistory
+
+		ratingHistory, err := history.NewFileRatingStore(i.config.History().Path(), i.config.History().RetentionDays())
+		if err != nil {
+			return errors.Wrap(err, "Initializer.initHistory()", "Failed to init rating history store")
+		}
+		i.services.RatingHistory = ratingHistory
+	}
+	if i.config.History().IsEnabled() && (i.config.Reports().FinanceRoutes().IsEnabled() || i.config.Reports().FinanceDaily().IsEnabled()) {
+		financeLedger, err := history.NewFileFinanceLedger(i.config.History().Path())
 		if err != nil {
-			return errors.Wrap(err, "Initializer.initTelegramBot()", "Failed to init Telegram Bot client")
+			return errors.Wrap(err, "Initializer.initHistory()", "Failed to init finance ledger")
 		}
-		i.services.TelegramBotService = services.NewTelegramBotAPIService(telegramBot)
+		i.services.FinanceLedger = financeLedger
 	}
 	return nil
 }
 
-func (i *Initializer) initScheduler() {
+func (i *Initializer) initOutbox() error {
+	retryPolicy := &scheduler.ExponentialRetryPolicy{
+		BaseBackoff: i.config.Outbox().BaseBackoff(),
+		MaxBackoff:  i.config.Outbox().MaxBackoff(),
+		Multiplier:  2,
+		Jitter:      0.2,
+		Retryable: func(err error) bool {
+			return !services.IsTelegramPermanentError(err)
+		},
+	}
+
+	// the service is taken at the time of sending, since the commands wrap it with the access control later
+	sender := func(ctx context.Context, message *outbox.Message) error {
+		if i.services.TelegramBotService == nil {
+			return errors.New("Initializer.initOutbox()", "Telegram bot service is not initialized")
+		}
+		return i.services.TelegramBotService.SendMessage(message.Destination, message.Text, message.ParseMode)
+	}
+
+	messages, err := outbox.NewFileOutbox(i.config.Outbox().Path(), sender, outbox.Options{
+		MaxAttempts: i.config.Outbox().MaxAttempts(),
+		RetryPolicy: retryPolicy,
+		RetryAfter:  services.TelegramRetryAfter,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initOutbox()", "Failed to init outbox")
+	}
+	i.services.Outbox = messages
+	return nil
+}
+
+func (i *Initializer) initScheduler() error {
 	logger.Log(logger.INFO, "Initializer.initScheduler()", "Start init application scheduler")
 	i.dependencies.Scheduler = scheduler.NewBaseScheduler(i.config.Internal().SchedulerMaxWorkers(), i.config.Internal().SchedulerRetryTaskLimit())
 
+	retryPolicy := &scheduler.ExponentialRetryPolicy{
+		BaseBackoff: i.config.Scheduler().Retry().BaseBackoff(),
+		MaxBackoff:  i.config.Scheduler().Retry().MaxBackoff(),
+		Multiplier:  i.config.Scheduler().Retry().Multiplier(),
+		Jitter:      i.config.Scheduler().Retry().Jitter(),
+		// there is no sense to retry until the session is restored or the request which cannot succeed, e.g. 404
+		Retryable: func(err error) bool {
+			if !services.IsWBLogisticRetryable(err) {
+				return false
+			}
+			return i.services.WBLogisticService == nil || !i.services.WBLogisticService.IsSessionExpired()
+		},
+	}
+
+	if i.config.Scheduler().CircuitBreaker().IsEnabled() {
+		i.dependencies.SchedulerCircuitBreaker = &scheduler.CircuitBreakerConfig{
+			FailureThreshold: i.config.Scheduler().CircuitBreaker().FailureThreshold(),
+			Cooldown:         i.config.Scheduler().CircuitBreaker().Cooldown(),
+		}
+	}
+
+	generalRoutesSchedule, generalRoutesWindow, err := parseReportSchedule(i.config.Reports().GeneralRoutes().Schedule())
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initScheduler()", "invalid 'general_routes.schedule'")
+	}
+	i.dependencies.SchedulerGeneralRoutesSchedule = generalRoutesSchedule
 	i.dependencies.SchedulerGeneralRoutesTaskConfig = &scheduler.TaskConfig{
 		RetryTaskLimit:        i.config.Reports().GeneralRoutes().ErrRetryTaskLimit(),
 		Timeout:               i.config.Reports().GeneralRoutes().TaskTimeout(),
 		IsWaitForPrevious:     true,
 		IsIntervalAfterFinish: true,
+		Window:                generalRoutesWindow,
+		RetryPolicy:           retryPolicy,
+		CircuitBreaker:        i.dependencies.SchedulerCircuitBreaker,
 	}
 
+	shipmentCloseSchedule, shipmentCloseWindow, err := parseReportSchedule(i.config.Reports().ShipmentClose().Schedule())
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initScheduler()", "invalid 'shipment_close.schedule'")
+	}
+	i.dependencies.SchedulerShipmentCloseSchedule = shipmentCloseSchedule
 	i.dependencies.SchedulerShipmentCloseTaskConfig = &scheduler.TaskConfig{
 		RetryTaskLimit:        i.config.Reports().ShipmentClose().ErrRetryTaskLimit(),
 		Timeout:               i.config.Reports().ShipmentClose().TaskTimeout(),
 		IsWaitForPrevious:     true,
 		IsIntervalAfterFinish: true,
+		Window:                shipmentCloseWindow,
+		RetryPolicy:           retryPolicy,
+		CircuitBreaker:        i.dependencies.SchedulerCircuitBreaker,
 	}
 
+	financeRoutesSchedule, financeRoutesWindow, err := parseReportSchedule(i.config.Reports().FinanceRoutes().Schedule())
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initScheduler()", "invalid 'finance_routes.schedule'")
+	}
+	i.dependencies.SchedulerFinanceRoutesSchedule = financeRoutesSchedule
 	i.dependencies.SchedulerFinanceRoutesTaskConfig = &scheduler.TaskConfig{
 		RetryTaskLimit:        i.config.Reports().FinanceRoutes().ErrRetryTaskLimit(),
 		Timeout:               i.config.Reports().FinanceRoutes().TaskTimeout(),
 		IsWaitForPrevious:     true,
 		IsIntervalAfterFinish: true,
+		Window:                financeRoutesWindow,
+		RetryPolicy:           retryPolicy,
+		CircuitBreaker:        i.dependencies.SchedulerCircuitBreaker,
 	}
 
+	financeDailySchedule, financeDailyWindow, err := parseReportSchedule(i.config.Reports().FinanceDaily().Schedule())
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initScheduler()", "invalid 'finance_daily.schedule'")
+	}
+	i.dependencies.SchedulerFinanceDailySchedule = financeDailySchedule
 	i.dependencies.SchedulerFinanceDailyTaskConfig = &scheduler.TaskConfig{
 		RetryTaskLimit:        i.config.Reports().FinanceDaily().ErrRetryTaskLimit(),
 		Timeout:               i.config.Reports().FinanceDaily().TaskTimeout(),
 		IsWaitForPrevious:     true,
 		IsIntervalAfterFinish: true,
+		Window:                financeDailyWindow,
+		RetryPolicy:           retryPolicy,
+		CircuitBreaker:        i.dependencies.SchedulerCircuitBreaker,
+	}
+
+	financeWeeklySchedule, financeWeeklyWindow, err := parseReportSchedule(i.config.Reports().FinanceWeekly().Schedule())
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initScheduler()", "invalid 'finance_weekly.schedule'")
+	}
+	i.dependencies.SchedulerFinanceWeeklySchedule = financeWeeklySchedule
+	i.dependencies.SchedulerFinanceWeeklyTaskConfig = &scheduler.TaskConfig{
+		RetryTaskLimit:        i.config.Reports().FinanceWeekly().ErrRetryTaskLimit(),
+		Timeout:               i.config.Reports().FinanceWeekly().TaskTimeout(),
+		IsWaitForPrevious:     true,
+		IsIntervalAfterFinish: true,
+		Window:                financeWeeklyWindow,
+		RetryPolicy:           retryPolicy,
+		CircuitBreaker:        i.dependencies.SchedulerCircuitBreaker,
+	}
+
+	financeMonthlySchedule, financeMonthlyWindow, err := parseReportSchedule(i.config.Reports().FinanceMonthly().Schedule())
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initScheduler()", "invalid 'finance_monthly.schedule'")
+	}
+	i.dependencies.SchedulerFinanceMonthlySchedule = financeMonthlySchedule
+	i.dependencies.SchedulerFinanceMonthlyTaskConfig = &scheduler.TaskConfig{
+		RetryTaskLimit:        i.config.Reports().FinanceMonthly().ErrRetryTaskLimit(),
+		Timeout:               i.config.Reports().FinanceMonthly().TaskTimeout(),
+		IsWaitForPrevious:     true,
+		IsIntervalAfterFinish: true,
+		Window:                financeMonthlyWindow,
+		RetryPolicy:           retryPolicy,
+		CircuitBreaker:        i.dependencies.SchedulerCircuitBreaker,
+	}
+
+	driverPerformanceSchedule, driverPerformanceWindow, err := parseReportSchedule(i.config.Reports().DriverPerformance().Schedule())
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initScheduler()", "invalid 'driver_performance.schedule'")
+	}
+	i.dependencies.SchedulerDriverPerformanceSchedule = driverPerformanceSchedule
+	i.dependencies.SchedulerDriverPerformanceTaskConfig = &scheduler.TaskConfig{
+		RetryTaskLimit:        i.config.Reports().DriverPerformance().ErrRetryTaskLimit(),
+		Timeout:               i.config.Reports().DriverPerformance().TaskTimeout(),
+		IsWaitForPrevious:     true,
+		IsIntervalAfterFinish: true,
+		Window:                driverPerformanceWindow,
+		RetryPolicy:           retryPolicy,
+		CircuitBreaker:        i.dependencies.SchedulerCircuitBreaker,
 	}
 	logger.Log(logger.INFO, "Initializer.initScheduler()", "Finish init application scheduler, successfully initialized")
+	return nil
+}
+
+// parseReportSchedule Returns the calendar schedule and the business-hours window of the report, nil if they are not set
+func parseReportSchedule(config *config.ReportsSchedule) (scheduler.Schedule, *scheduler.TimeWindow, error) {
+	var window *scheduler.TimeWindow
+	if config.IsWindow() {
+		var err error
+		window, err = scheduler.ParseTimeWindow(config.From(), config.To(), config.Location())
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Initializer.parseReportSchedule()", "")
+		}
+	}
+
+	switch {
+	case config.Cron() != "":
+		schedule, err := scheduler.ParseCron(config.Cron(), config.Location())
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Initializer.parseReportSchedule()", "")
+		}
+		return schedule, window, nil
+	case len(config.At()) > 0:
+		schedule, err := scheduler.ParseDaily(config.At(), config.Location())
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Initializer.parseReportSchedule()", "")
+		}
+		return schedule, window, nil
+	}
+	return nil, window, nil
 }
 
 func (i *Initializer) initReporters() {
@@ -181,6 +481,53 @@ func (i *Initializer) initReporters() {
 	i.dependencies.ShipmentCloseReporter = reporters.NewShipmentCloseReporter(i.config, i.storage, i.services, &prompters.CLIReporterShipmentClosePrompter{})
 	i.dependencies.FinanceRoutesReporter = reporters.NewFinanceRoutesReporter(i.config, i.storage, i.services, &prompters.CLIReporterFinanceRoutesPrompter{})
 	i.dependencies.FinanceDailyReporter = reporters.NewFinanceDailyReporter(i.config, i.storage, i.services, &prompters.CLIReporterFinanceDailyPrompter{})
+	i.dependencies.FinanceWeeklyReporter = reporters.NewFinanceWeeklyReporter(i.config, i.storage, i.services, prompters.NewCLIReporterFinanceWeeklyPrompter())
+	i.dependencies.FinanceMonthlyReporter = reporters.NewFinanceMonthlyReporter(i.config, i.storage, i.services, prompters.NewCLIReporterFinanceMonthlyPrompter())
+	i.dependencies.DriverPerformanceReporter = reporters.NewDriverPerformanceReporter(i.config, i.storage, i.services, &prompters.CLIReporterDriverPerformancePrompter{})
 	logger.Log(logger.INFO, "Initializer.initReporters()", "Finish init application reporters, successfully initialized")
 
 }
+
+func (i *Initializer) initAlerts() error {
+	if !i.config.Alerts().IsEnabled() {
+		return nil
+	}
+	engine, err := alerts.NewEngine(i.config.Alerts())
+	if err != nil {
+		return errors.Wrap(err, "Initializer.initAlerts()", "Failed to init alert rules")
+	}
+	i.services.Alerts = engine
+	return nil
+}
+
+func (i *Initializer) initTelegramCommands() {
+	if !i.config.Telegram().Commands().IsEnabled() {
+		return
+	}
+	if i.config.Telegram().Access().IsEnabled() {
+		i.services.TelegramBotService = services.NewTelegramBotAccessService(i.services.TelegramBotService, i.config.Telegram().Access(), telegram_commands.CommandRoles)
+	} else {
+		logger.Log(logger.WARN, "Initializer.initTelegramCommands()", "Access control of Telegram bot commands is disabled, the admin and finance commands are answered only in the admin chat")
+	}
+	generalRoutesReporter, _ := i.dependencies.GeneralRoutesReporter.(reporters.GeneralRoutesSnapshotter)
+	// separate instance, so the commands do not interfere with the scheduled report
+	financeDailyReporter := reporters.NewFinanceDailyReporter(i.config, i.storage, i.services, &prompters.CLIReporterFinanceDailyPrompter{})
+	// the WB logistic session is restored in the admin chat, so nobody is needed at the console
+	if adminChatID := i.config.Telegram().Access().AdminChatID(); adminChatID != 0 {
+		i.dependencies.WBAuthPrompter = telegram_commands.NewWBAuthPrompter(
+			i.services,
+			adminChatID,
+			i.config.Telegram().Commands().AuthTimeout(),
+			i.storage.ConfigStore().GetWBLogisticLogin,
+		)
+	}
+	i.dependencies.TelegramCommands = telegram_commands.NewDispatcher(
+		i.config,
+		i.services,
+		i.dependencies.Scheduler,
+		generalRoutesReporter,
+		financeDailyReporter,
+		i.dependencies.WBAuthPrompter,
+		&prompters.CLITelegramCommandsPrompter{},
+	)
+}
@@ -2,11 +2,13 @@ package wb_logistic
 
 import (
 	"wb_logistic_assistant/external/wb_logistic_api"
+	"wb_logistic_assistant/external/wb_logistic_api/request"
 	"wb_logistic_assistant/external/wb_logistic_api/session"
 	"wb_logistic_assistant/external/wb_logistic_api/transport"
 	"wb_logistic_assistant/internal/config"
 	"wb_logistic_assistant/internal/errors"
 	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/metrics"
 	"wb_logistic_assistant/internal/prompters"
 	"wb_logistic_assistant/internal/storage"
 )
@@ -14,10 +16,10 @@ import (
 type Initializer struct {
 	config   *config.Config
 	storage  storage.Storage
-	prompter prompters.InitializeAppPrompter
+	prompter prompters.InitializeWBLogisticPrompter
 }
 
-func NewInitializer(config *config.Config, storage storage.Storage, prompter prompters.InitializeAppPrompter) *Initializer {
+func NewInitializer(config *config.Config, storage storage.Storage, prompter prompters.InitializeWBLogisticPrompter) *Initializer {
 	return &Initializer{
 		config:   config,
 		storage:  storage,
@@ -28,17 +30,13 @@ func NewInitializer(config *config.Config, storage storage.Storage, prompter pro
 func (i *Initializer) Init() (*wb_logistic_api.Client, *session.Session, error) {
 	logger.Log(logger.INFO, "Initializer.WBLogistic.Init()", "Start init wb logistic")
 
-	cfg := i.config.Logistic().WBClient()
-	httpClient := transport.NewBaseHTTPClientWithParams(&transport.HTTPClientParameters{
-		UserAgent:    cfg.UserAgent(),
-		Platform:     cfg.Platform(),
-		SecUserAgent: cfg.SecUserAgent(),
-	})
-	client := wb_logistic_api.NewClient(httpClient)
+	client, err := i.newClient()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Initializer.WBLogistic.Init()", "")
+	}
 
 	i.prompter.PromptWBLogisticAuthStart()
 	var s *session.Session
-	var err error
 	if i.prompter.PromptWBLogisticQuestionAuthNewUser() {
 		s, err = i.AuthSession(client)
 		if err != nil {
@@ -69,13 +67,10 @@ func (i *Initializer) Init() (*wb_logistic_api.Client, *session.Session, error)
 func (i *Initializer) InitDirect() (*wb_logistic_api.Client, *session.Session, error) {
 	logger.Log(logger.INFO, "Initializer.WBLogistic.InitDirect()", "Start init wb logistic")
 
-	cfg := i.config.Logistic().WBClient()
-	httpClient := transport.NewBaseHTTPClientWithParams(&transport.HTTPClientParameters{
-		UserAgent:    cfg.UserAgent(),
-		Platform:     cfg.Platform(),
-		SecUserAgent: cfg.SecUserAgent(),
-	})
-	client := wb_logistic_api.NewClient(httpClient)
+	client, err := i.newClient()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Initializer.WBLogistic.InitDirect()", "")
+	}
 
 	i.prompter.PromptWBLogisticAuthStart()
 	s, err := i.AuthSession(client)
@@ -88,3 +83,50 @@ func (i *Initializer) InitDirect() (*wb_logistic_api.Client, *session.Session, e
 	logger.Log(logger.INFO, "Initializer.WBLogistic.InitDirect()", "Finish init wb logistic")
 	return client, s, nil
 }
+
+// newClient Client with the parameters and the base URLs of the services from the config
+func (i *Initializer) newClient() (*wb_logistic_api.Client, error) {
+	cfg := i.config.Logistic().WBClient()
+	limits := make(map[string]transport.RateLimit, len(cfg.RateLimits()))
+	for service, limit := range cfg.RateLimits() {
+		limits[service] = transport.RateLimit{
+			Rate:        limit.Rate(),
+			Burst:       limit.Burst(),
+			Concurrency: limit.Concurrency(),
+		}
+	}
+	httpClient := transport.NewBaseHTTPClientWithParams(&transport.HTTPClientParameters{
+		UserAgent:    cfg.UserAgent(),
+		Platform:     cfg.Platform(),
+		SecUserAgent: cfg.SecUserAgent(),
+		Observer:     metrics.ObserveWBRequest,
+		Limiter:      transport.NewRateLimiter(limits, request.ServiceByPath),
+	})
+	client := wb_logistic_api.NewClient(httpClient)
+
+	switch cfg.CassetteMode() {
+	case config.LogisticCassetteRecord:
+		recorder, err := transport.NewCassetteRecorder(httpClient, cfg.CassetteDir())
+		if err != nil {
+			return nil, errors.Wrap(err, "Initializer.WBLogistic.newClient()", "failed to init cassette recorder")
+		}
+		client = wb_logistic_api.NewClient(recorder)
+		logger.Logf(logger.WARN, "Initializer.WBLogistic.newClient()", "WB logistic traffic is recorded to %s", cfg.CassetteDir())
+	case config.LogisticCassetteReplay:
+		// without the origin a request missing in the cassette fails instead of going to WB
+		player, err := transport.NewCassettePlayer(cfg.CassetteDir(), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "Initializer.WBLogistic.newClient()", "failed to init cassette player")
+		}
+		client = wb_logistic_api.NewClient(player)
+		logger.Logf(logger.WARN, "Initializer.WBLogistic.newClient()", "WB logistic traffic is replayed from %s", cfg.CassetteDir())
+	}
+
+	for service, baseURL := range cfg.Endpoints() {
+		if err := client.Endpoints().Set(service, baseURL); err != nil {
+			return nil, errors.Wrap(err, "Initializer.WBLogistic.newClient()", "invalid endpoint")
+		}
+		logger.Logf(logger.INFO, "Initializer.WBLogistic.newClient()", "WB logistic service '%s' uses base URL %s", service, baseURL)
+	}
+	return client, nil
+}
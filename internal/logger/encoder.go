@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry Single log record
+type Entry struct {
+	Time     time.Time
+	Level    Level
+	Location string
+	Message  string
+	Fields   []Field
+}
+
+// Encoder Writes the entry into the buffer as one line
+type Encoder interface {
+	Encode(buf *bytes.Buffer, entry *Entry)
+}
+
+// TextEncoder Format "ts: [LEVEL] location: msg key=value ...", without fields it is the legacy format of the logger
+type TextEncoder struct {
+	TimeFormat string // if empty, the time format of the logger is used
+}
+
+func (e *TextEncoder) Encode(buf *bytes.Buffer, entry *Entry) {
+	format := e.TimeFormat
+	if format == "" {
+		format = getTimeFormat()
+	}
+	buf.WriteString(entry.Time.Format(format))
+	buf.WriteString(": [")
+	buf.WriteString(entry.Level.String())
+	buf.WriteString("] ")
+	buf.WriteString(entry.Location)
+	buf.WriteString(": ")
+	buf.WriteString(entry.Message)
+	for _, field := range entry.Fields {
+		buf.WriteByte(' ')
+		buf.WriteString(field.Key)
+		buf.WriteByte('=')
+		value := field.String()
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		buf.WriteString(value)
+	}
+	buf.WriteByte('\n')
+}
+
+// JSONEncoder One JSON object per line with the keys time, level, location, msg and the fields
+type JSONEncoder struct{}
+
+func (e *JSONEncoder) Encode(buf *bytes.Buffer, entry *Entry) {
+	buf.WriteString(`{"time":`)
+	writeJSONString(buf, entry.Time.Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSONString(buf, entry.Level.String())
+	buf.WriteString(`,"location":`)
+	writeJSONString(buf, entry.Location)
+	buf.WriteString(`,"msg":`)
+	writeJSONString(buf, entry.Message)
+	for _, field := range entry.Fields {
+		buf.WriteByte(',')
+		writeJSONString(buf, field.Key)
+		buf.WriteByte(':')
+		writeJSONValue(buf, field)
+	}
+	buf.WriteString("}\n")
+}
+
+// NewEncoder Returns the encoder by the format name: "text" or "json"
+func NewEncoder(format string) (Encoder, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "text":
+		return &TextEncoder{}, nil
+	case "json":
+		return &JSONEncoder{}, nil
+	}
+	return nil, fmt.Errorf("unknown log format '%s'", format)
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s)
+	buf.Write(b)
+}
+
+// writeJSONValue Numbers and booleans are written as is, errors and other values as strings
+func writeJSONValue(buf *bytes.Buffer, field Field) {
+	switch field.Value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+		if b, err := json.Marshal(field.Value); err == nil {
+			buf.Write(b)
+			return
+		}
+	case nil:
+		buf.WriteString("null")
+		return
+	}
+	writeJSONString(buf, field.String())
+}
+
+func sprint(v interface{}) string {
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,51 @@
+package logger
+
+import "strconv"
+
+// Keys of the common fields
+const (
+	FieldTask       = "task"
+	FieldRouteID    = "route_id"
+	FieldShipmentID = "shipment_id"
+	FieldWaySheetID = "waysheet_id"
+)
+
+// Field Key/value pair attached to the log entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func F(key string, value interface{}) Field { return Field{Key: key, Value: value} }
+
+func Task(name string) Field    { return Field{Key: FieldTask, Value: name} }
+func RouteID(id int) Field      { return Field{Key: FieldRouteID, Value: id} }
+func ShipmentID(id int) Field   { return Field{Key: FieldShipmentID, Value: id} }
+func WaySheetID(id int) Field   { return Field{Key: FieldWaySheetID, Value: id} }
+func Err(err error) Field       { return Field{Key: "error", Value: err} }
+func TaskID(id uint64) Field    { return Field{Key: "task_id", Value: id} }
+func Attempt(attempt int) Field { return Field{Key: "attempt", Value: attempt} }
+
+// String Text form of the value, errors are represented by their message
+func (f Field) String() string {
+	switch v := f.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case error:
+		return v.Error()
+	case interface{ String() string }:
+		return v.String()
+	default:
+		return sprint(v)
+	}
+}
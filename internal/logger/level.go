@@ -1,5 +1,10 @@
 package logger
 
+import (
+	"errors"
+	"strings"
+)
+
 type Level int
 
 const (
@@ -13,3 +18,20 @@ const (
 func (l Level) String() string {
 	return [...]string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL"}[l]
 }
+
+// ParseLevel Case-insensitive name of the level, e.g. "info"
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "DEBUG":
+		return DEBUG, nil
+	case "INFO":
+		return INFO, nil
+	case "WARN", "WARNING":
+		return WARN, nil
+	case "ERROR":
+		return ERROR, nil
+	case "FATAL":
+		return FATAL, nil
+	}
+	return DEBUG, errors.New("unknown log level '" + s + "'")
+}
@@ -1,25 +1,36 @@
 package logger
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 )
 
-var timeFormat = "2006.01.02 15:04:05 -0700"
-var fatalHandler = func() {}
+var (
+	timeFormat    = "2006.01.02 15:04:05 -0700"
+	timeFormatMtx = sync.RWMutex{}
+	fatalHandler  = func() {}
+)
 
 func SetTimeFormat(format string) error {
 	if format == "" {
 		return errors.New("time format is empty")
 	}
-	mtx.Lock()
+	timeFormatMtx.Lock()
 	timeFormat = format
-	mtx.Unlock()
+	timeFormatMtx.Unlock()
 	return nil
 }
 
+func getTimeFormat() string {
+	timeFormatMtx.RLock()
+	defer timeFormatMtx.RUnlock()
+	return timeFormat
+}
+
 func SetFatalHandler(handler func()) {
 	if handler != nil {
 		fatalHandler = handler
@@ -27,19 +38,61 @@ func SetFatalHandler(handler func()) {
 }
 
 func Log(level Level, location, msg string) {
+	write(level, location, msg, nil)
+}
+
+func Logf(level Level, location, format string, args ...interface{}) {
+	write(level, location, fmt.Sprintf(format, args...), nil)
+}
+
+// Logger Writes the entries with the attached fields
+type Logger struct {
+	fields []Field
+}
+
+// With Returns the logger which attaches the fields to every entry
+func With(fields ...Field) *Logger {
+	return &Logger{fields: fields}
+}
+
+// With Returns the child logger with the fields added to the fields of the parent
+func (l *Logger) With(fields ...Field) *Logger {
+	merged := make([]Field, 0, len(l.fields)+len(fields))
+	merged = append(merged, l.fields...)
+	merged = append(merged, fields...)
+	return &Logger{fields: merged}
+}
+
+func (l *Logger) Log(level Level, location, msg string) {
+	write(level, location, msg, l.fields)
+}
+
+func (l *Logger) Logf(level Level, location, format string, args ...interface{}) {
+	write(level, location, fmt.Sprintf(format, args...), l.fields)
+}
+
+func write(level Level, location, msg string, fields []Field) {
+	entry := &Entry{Time: time.Now(), Level: level, Location: location, Message: msg, Fields: fields}
+
 	mtx.RLock()
-	defer mtx.RUnlock()
-	outs := getOutputs(level)
-	ts := time.Now().Format(timeFormat)
-	for i := 0; i < len(outs); i++ {
-		_, _ = fmt.Fprintf(outs[i], "%s: [%s] %s: %s\n", ts, level, location, msg)
+	encoded := make(map[Encoder][]byte, 2) // the sinks usually share a few encoders
+	for _, sink := range sinks {
+		if !sink.accepts(level) {
+			continue
+		}
+		line, ok := encoded[sink.encoder]
+		if !ok {
+			var buf bytes.Buffer
+			sink.encoder.Encode(&buf, entry)
+			line = buf.Bytes()
+			encoded[sink.encoder] = line
+		}
+		_, _ = sink.writer.Write(line)
 	}
+	mtx.RUnlock()
+
 	if level == FATAL {
 		fatalHandler()
 		os.Exit(1)
 	}
 }
-
-func Logf(level Level, location, format string, args ...interface{}) {
-	Log(level, location, fmt.Sprintf(format, args...))
-}
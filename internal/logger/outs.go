@@ -6,75 +6,116 @@ import (
 	"sync"
 )
 
+type sinkKind int
+
+const (
+	sinkCustom    sinkKind = iota
+	sinkOutput             // legacy AddOutput, DEBUG and INFO
+	sinkErrOutput          // legacy AddOutputErr, WARN and above
+)
+
+// Sink Output with the minimum level and the encoder of its entries
+type Sink struct {
+	writer   io.Writer
+	minLevel Level
+	maxLevel Level
+	encoder  Encoder
+	kind     sinkKind
+}
+
+// NewSink If the encoder is nil, TextEncoder is used
+func NewSink(writer io.Writer, minLevel Level, encoder Encoder) *Sink {
+	if encoder == nil {
+		encoder = &TextEncoder{}
+	}
+	return &Sink{writer: writer, minLevel: minLevel, maxLevel: FATAL, encoder: encoder}
+}
+
+func (s *Sink) accepts(level Level) bool {
+	return level >= s.minLevel && level <= s.maxLevel
+}
+
 var (
-	outputs      = make([]io.Writer, 0)
-	errorOutputs = make([]io.Writer, 0)
-	mtx          = sync.RWMutex{}
+	sinks = make([]*Sink, 0)
+	mtx   = sync.RWMutex{}
 )
 
-func AddOutput(writer io.Writer) error {
-	if writer == nil {
-		return errors.New("writer is nil")
+func AddSink(sink *Sink) error {
+	if sink == nil || sink.writer == nil {
+		return errors.New("sink or its writer is nil")
 	}
 	mtx.Lock()
 	defer mtx.Unlock()
-	for _, w := range outputs {
-		if w == writer {
-			return errors.New("writer already exists")
+	for _, s := range sinks {
+		if s == sink {
+			return errors.New("sink already exists")
 		}
 	}
-	outputs = append(outputs, writer)
+	sinks = append(sinks, sink)
 	return nil
 }
 
-func RemoveOutput(writer io.Writer) {
-	if writer == nil {
+func RemoveSink(sink *Sink) {
+	if sink == nil {
 		return
 	}
 	mtx.Lock()
 	defer mtx.Unlock()
-	for i, w := range outputs {
-		if w == writer {
-			outputs = append(outputs[:i], outputs[i+1:]...)
-			return
-		}
-	}
+	removeSinks(func(s *Sink) bool { return s == sink })
 }
 
+// AddOutput Adds the writer of the DEBUG and INFO entries in the text format
+func AddOutput(writer io.Writer) error {
+	return addLegacy(writer, sinkOutput, DEBUG, INFO)
+}
+
+func RemoveOutput(writer io.Writer) {
+	removeLegacy(writer, sinkOutput)
+}
+
+// AddOutputErr Adds the writer of the WARN, ERROR and FATAL entries in the text format
 func AddOutputErr(writer io.Writer) error {
+	return addLegacy(writer, sinkErrOutput, WARN, FATAL)
+}
+
+func RemoveOutputErr(writer io.Writer) {
+	removeLegacy(writer, sinkErrOutput)
+}
+
+func addLegacy(writer io.Writer, kind sinkKind, minLevel, maxLevel Level) error {
 	if writer == nil {
 		return errors.New("writer is nil")
 	}
 	mtx.Lock()
 	defer mtx.Unlock()
-	for _, o := range errorOutputs {
-		if o == writer {
+	for _, s := range sinks {
+		if s.kind == kind && s.writer == writer {
 			return errors.New("writer already exists")
 		}
 	}
-	errorOutputs = append(errorOutputs, writer)
+	sinks = append(sinks, &Sink{writer: writer, minLevel: minLevel, maxLevel: maxLevel, encoder: &TextEncoder{}, kind: kind})
 	return nil
 }
 
-func RemoveOutputErr(writer io.Writer) {
+func removeLegacy(writer io.Writer, kind sinkKind) {
 	if writer == nil {
 		return
 	}
 	mtx.Lock()
 	defer mtx.Unlock()
-	for i, o := range errorOutputs {
-		if o == writer {
-			errorOutputs = append(errorOutputs[:i], errorOutputs[i+1:]...)
-			return
-		}
-	}
+	removeSinks(func(s *Sink) bool { return s.kind == kind && s.writer == writer })
 }
 
-func getOutputs(level Level) []io.Writer {
-	switch level {
-	case FATAL, ERROR, WARN:
-		return errorOutputs
-	default:
-		return outputs
+// removeSinks Must be called under the lock
+func removeSinks(match func(s *Sink) bool) {
+	kept := sinks[:0]
+	for _, s := range sinks {
+		if !match(s) {
+			kept = append(kept, s)
+		}
+	}
+	for i := len(kept); i < len(sinks); i++ {
+		sinks[i] = nil
 	}
+	sinks = kept
 }
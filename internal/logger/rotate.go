@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rotatedTimeFormat = "20060102-150405"
+
+type RotationOptions struct {
+	MaxSize    int64         // bytes, if 0, the file is not rotated by the size
+	Interval   time.Duration // if 0, the file is not rotated by the time
+	MaxBackups int           // rotated files to keep, if 0, unlimited
+	MaxAge     time.Duration // rotated files older than that are removed, if 0, unlimited
+}
+
+// RotatingFile Appending file writer which rotates the file by the size and the time.
+// The rotated files are named 'path.YYYYMMDD-HHMMSS' and removed by the retention options
+type RotatingFile struct {
+	mtx      sync.Mutex
+	path     string
+	options  RotationOptions
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func NewRotatingFile(path string, options RotationOptions) (*RotatingFile, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is empty")
+	}
+	f := &RotatingFile{path: path, options: options}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.isRotationNeeded(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			// keep writing into the current file rather than losing the entries
+			_, _ = fmt.Fprintf(os.Stderr, "logger: failed rotate file %s: %v\n", f.path, err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) Close() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) isRotationNeeded(size int64) bool {
+	if f.size == 0 {
+		return false // rotating the empty file does not help
+	}
+	if f.options.MaxSize > 0 && f.size+size > f.options.MaxSize {
+		return true
+	}
+	return f.options.Interval > 0 && time.Since(f.openedAt) >= f.options.Interval
+}
+
+// open Continues the existing file, its age is counted from the last modification
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	if f.size > 0 {
+		f.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	rotated := f.path + "." + time.Now().Format(rotatedTimeFormat)
+	for i := 1; fileExists(rotated); i++ {
+		rotated = fmt.Sprintf("%s.%s.%d", f.path, time.Now().Format(rotatedTimeFormat), i)
+	}
+	renameErr := os.Rename(f.path, rotated)
+
+	// the file must be reopened even if the rename failed
+	if err := f.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return renameErr
+	}
+	return f.removeOld()
+}
+
+// removeOld Removes the rotated files beyond the retention, the newest are kept
+func (f *RotatingFile) removeOld() error {
+	if f.options.MaxBackups <= 0 && f.options.MaxAge <= 0 {
+		return nil
+	}
+
+	dir := filepath.Dir(f.path)
+	prefix := filepath.Base(f.path) + "."
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		path    string
+		modTime time.Time
+	}
+	backups := make([]backup, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		suffix := strings.TrimPrefix(entry.Name(), prefix)
+		if len(suffix) < len(rotatedTimeFormat) {
+			continue
+		}
+		if _, err := time.Parse(rotatedTimeFormat, suffix[:len(rotatedTimeFormat)]); err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, entry.Name()), modTime: info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.After(backups[j].modTime) })
+
+	var lastErr error
+	for i, b := range backups {
+		isExcess := f.options.MaxBackups > 0 && i >= f.options.MaxBackups
+		isExpired := f.options.MaxAge > 0 && time.Since(b.modTime) > f.options.MaxAge
+		if isExcess || isExpired {
+			if err := os.Remove(b.path); err != nil {
+				lastErr = err
+			}
+		}
+	}
+	return lastErr
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"wb_logistic_assistant/internal/logger"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler Serves the registry in the Prometheus text format
+func Handler(registry *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := registry.WriteText(&buf); err != nil {
+			logger.Logf(logger.ERROR, "metrics.Handler()", "failed write metrics: %v", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(buf.Bytes())
+	})
+}
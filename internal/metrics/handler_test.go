@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"wb_logistic_assistant/external/wb_logistic_api/transport"
+)
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	runs := registry.NewCounterVec("test_runs_total", "Runs of the task.", "task")
+	remains := registry.NewGaugeVec("test_remains", "Remains of the route.", "route")
+	duration := registry.NewHistogramVec("test_duration_seconds", "Duration of the run.", []float64{1, 5}, "task")
+	expiry := registry.NewGaugeFunc("test_expiry_timestamp_seconds", "Expiry of the token.")
+
+	runs.With("general_routes").Inc()
+	runs.With("general_routes").Add(2)
+	runs.With("general_routes").Add(-1) // ignored
+	remains.With(`route "7"`).Set(12.5)
+	duration.With("general_routes").Observe(0.5)
+	duration.With("general_routes").Observe(3)
+	duration.With("general_routes").Observe(10)
+	expiry.Set(func() (float64, bool) { return 1700000000, true })
+
+	server := httptest.NewServer(Handler(registry))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status %d, want 200", res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != contentType {
+		t.Fatalf("content type %q, want %q", ct, contentType)
+	}
+
+	want := `# HELP test_duration_seconds Duration of the run.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{task="general_routes",le="1"} 1
+test_duration_seconds_bucket{task="general_routes",le="5"} 2
+test_duration_seconds_bucket{task="general_routes",le="+Inf"} 3
+test_duration_seconds_sum{task="general_routes"} 13.5
+test_duration_seconds_count{task="general_routes"} 3
+# HELP test_expiry_timestamp_seconds Expiry of the token.
+# TYPE test_expiry_timestamp_seconds gauge
+test_expiry_timestamp_seconds 1.7e+09
+# HELP test_remains Remains of the route.
+# TYPE test_remains gauge
+test_remains{route="route \"7\""} 12.5
+# HELP test_runs_total Runs of the task.
+# TYPE test_runs_total counter
+test_runs_total{task="general_routes"} 3
+`
+	if string(body) != want {
+		t.Fatalf("body:\n%s\nwant:\n%s", body, want)
+	}
+}
+
+func TestHandlerMethods(t *testing.T) {
+	server := httptest.NewServer(Handler(NewRegistry()))
+	defer server.Close()
+
+	res, err := http.Post(server.URL, "text/plain", strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusMethodNotAllowed || res.Header.Get("Allow") != "GET, HEAD" {
+		t.Fatalf("POST: status %d, allow %q", res.StatusCode, res.Header.Get("Allow"))
+	}
+
+	res, err = http.Head(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("HEAD: status %d, want 200", res.StatusCode)
+	}
+}
+
+func TestObserveWBRequest(t *testing.T) {
+	wb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer wb.Close()
+
+	client := transport.NewBaseHTTPClientWithParams(&transport.HTTPClientParameters{Observer: ObserveWBRequest})
+	res, err := client.Get(context.Background(), wb.URL+"/api/v1/shipments/123/info", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	metricsServer := httptest.NewServer(Handler(Default))
+	defer metricsServer.Close()
+	res, err = http.Get(metricsServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	u, _ := url.Parse(wb.URL)
+	endpoint := u.Host + "/api/v1/shipments/:id/info"
+	for _, line := range []string{
+		`wb_assistant_wb_api_requests_total{method="GET",endpoint="` + endpoint + `",status="404"} 1`,
+		`wb_assistant_wb_api_request_duration_seconds_count{method="GET",endpoint="` + endpoint + `"} 1`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Fatalf("missing sample %s in:\n%s", line, body)
+		}
+	}
+}
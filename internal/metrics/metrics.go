@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const namespace = "wb_assistant_"
+
+// Default Registry of the application metrics
+var Default = NewRegistry()
+
+// Scheduler
+var (
+	SchedulerTaskRuns     = Default.NewCounterVec(namespace+"scheduler_task_runs_total", "Attempts of the scheduled task runs.", "task")
+	SchedulerTaskFailures = Default.NewCounterVec(namespace+"scheduler_task_failures_total", "Failed attempts of the scheduled task runs, the cancelled runs are not counted.", "task")
+	SchedulerTaskDuration = Default.NewHistogramVec(namespace+"scheduler_task_duration_seconds", "Duration of the scheduled task run attempts.", []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}, "task")
+)
+
+// WB logistic API
+var (
+	WBRequests        = Default.NewCounterVec(namespace+"wb_api_requests_total", "Requests to the WB logistic API, status is the HTTP code or 'error' if there is no response.", "method", "endpoint", "status")
+	WBRequestDuration = Default.NewHistogramVec(namespace+"wb_api_request_duration_seconds", "Latency of the requests to the WB logistic API.", nil, "method", "endpoint")
+	WBSessionExpiry   = Default.NewGaugeFunc(namespace+"wb_session_token_expiry_timestamp_seconds", "Unix time when the WB logistic session token expires.")
+)
+
+// Caches
+var (
+	CacheRequests = Default.NewCounterVec(namespace+"cache_requests_total", "Lookups of the WB logistic service caches, result is 'hit' or 'miss'.", "cache", "result")
+)
+
+// Telegram and Google Sheets
+var (
+	TelegramSends       = Default.NewCounterVec(namespace+"telegram_sends_total", "Telegram bot sends, status is 'success' or 'failure'.", "method", "status")
+	GoogleSheetsLatency = Default.NewHistogramVec(namespace+"google_sheets_write_duration_seconds", "Latency of the Google Sheets writes.", nil, "operation")
+)
+
+// Business gauges of the general routes report
+var (
+	RouteRemainsBarcodes = Default.NewGaugeVec(namespace+"route_remains_barcodes", "Barcodes remaining in the open shipment of the route.", "route")
+	RouteBarcodes        = Default.NewGaugeVec(namespace+"route_barcodes", "Barcodes on the route.", "route")
+	RouteTares           = Default.NewGaugeVec(namespace+"route_tares", "Tares on the route.", "route")
+)
+
+// Status values of the labels
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusError   = "error"
+	CacheHit      = "hit"
+	CacheMiss     = "miss"
+)
+
+// ObserveWBRequest Records the finished request to the WB logistic API, it matches transport.RequestObserver
+func ObserveWBRequest(req *http.Request, res *http.Response, err error, duration time.Duration) {
+	if req == nil || req.URL == nil {
+		return
+	}
+	endpoint := NormalizeEndpoint(req.URL.Host, req.URL.Path)
+	status := StatusError
+	if err == nil && res != nil {
+		status = strconv.Itoa(res.StatusCode)
+	}
+	WBRequests.With(req.Method, endpoint, status).Inc()
+	WBRequestDuration.With(req.Method, endpoint).Observe(duration.Seconds())
+}
+
+// NormalizeEndpoint Replaces the numeric path segments (ids) with ':id' to keep the count of the label values bounded
+func NormalizeEndpoint(host, path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isNumeric(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return host + strings.Join(segments, "/")
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Since Seconds since the start, for the histograms
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
@@ -0,0 +1,379 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// DefaultBuckets Upper bounds of the histogram buckets in seconds, suitable for the latency of the network calls
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type family interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry Set of the metric families which is exposed in the Prometheus text format
+type Registry struct {
+	mtx      sync.RWMutex
+	families map[string]family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]family)}
+}
+
+// NewCounterVec Registers the counter, it panics if the name is already registered
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	v := &CounterVec{vec: newVec(name, help, typeCounter, labels)}
+	r.register(v)
+	return v
+}
+
+// NewGaugeVec Registers the gauge, it panics if the name is already registered
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	v := &GaugeVec{vec: newVec(name, help, typeGauge, labels)}
+	r.register(v)
+	return v
+}
+
+// NewHistogramVec Registers the histogram, if buckets are empty DefaultBuckets are used. It panics if the name is already registered
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	v := &HistogramVec{vec: newVec(name, help, typeHistogram, labels), buckets: sorted}
+	r.register(v)
+	return v
+}
+
+// NewGaugeFunc Registers the gauge whose value is read at the scrape, it panics if the name is already registered
+func (r *Registry) NewGaugeFunc(name, help string) *GaugeFunc {
+	g := &GaugeFunc{metricName: name, help: help}
+	r.register(g)
+	return g
+}
+
+func (r *Registry) register(f family) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.families[f.name()]; ok {
+		panic("metrics: duplicate registration of '" + f.name() + "'")
+	}
+	r.families[f.name()] = f
+}
+
+// WriteText Writes all metrics in the Prometheus text exposition format ordered by name
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mtx.RLock()
+	families := make([]family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mtx.RUnlock()
+
+	sort.Slice(families, func(i, j int) bool { return families[i].name() < families[j].name() })
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// vec Children of the metric family by the label values
+type vec struct {
+	metricName string
+	help       string
+	metricType string
+	labels     []string
+
+	mtx      sync.RWMutex
+	children map[string]*child
+}
+
+type child struct {
+	labelValues []string
+
+	mtx   sync.Mutex
+	value float64  // counter and gauge
+	count uint64   // histogram
+	sum   float64  // histogram
+	hits  []uint64 // histogram, not cumulative
+}
+
+func newVec(name, help, metricType string, labels []string) *vec {
+	return &vec{
+		metricName: name,
+		help:       help,
+		metricType: metricType,
+		labels:     labels,
+		children:   make(map[string]*child),
+	}
+}
+
+func (v *vec) name() string { return v.metricName }
+
+// with Returns the child of the label values, missing values are empty and extra ones are ignored
+func (v *vec) with(values []string, buckets int) *child {
+	labelValues := make([]string, len(v.labels))
+	copy(labelValues, values)
+	key := strings.Join(labelValues, "\xff")
+
+	v.mtx.RLock()
+	c, ok := v.children[key]
+	v.mtx.RUnlock()
+	if ok {
+		return c
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if c, ok = v.children[key]; ok {
+		return c
+	}
+	c = &child{labelValues: labelValues}
+	if buckets > 0 {
+		c.hits = make([]uint64, buckets)
+	}
+	v.children[key] = c
+	return c
+}
+
+// Reset Removes all children, e.g. the routes which are gone from the report
+func (v *vec) Reset() {
+	v.mtx.Lock()
+	v.children = make(map[string]*child)
+	v.mtx.Unlock()
+}
+
+// sortedChildren Ordered by the label values for the stable output
+func (v *vec) sortedChildren() []*child {
+	v.mtx.RLock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	children := make([]*child, 0, len(keys))
+	for _, key := range keys {
+		children = append(children, v.children[key])
+	}
+	v.mtx.RUnlock()
+	return children
+}
+
+func (v *vec) writeHeader(w *bufio.Writer) {
+	writeHeader(w, v.metricName, v.help, v.metricType)
+}
+
+// CounterVec Monotonically increasing value
+type CounterVec struct {
+	*vec
+}
+
+func (v *CounterVec) With(labelValues ...string) *Counter {
+	return &Counter{c: v.with(labelValues, 0)}
+}
+
+func (v *CounterVec) write(w *bufio.Writer) {
+	v.writeHeader(w)
+	for _, c := range v.sortedChildren() {
+		c.mtx.Lock()
+		value := c.value
+		c.mtx.Unlock()
+		writeSample(w, v.metricName, v.labels, c.labelValues, "", "", value)
+	}
+}
+
+type Counter struct {
+	c *child
+}
+
+func (c *Counter) Inc() { c.Add(1) }
+
+// Add Negative values are ignored, the counter never decreases
+func (c *Counter) Add(value float64) {
+	if value < 0 {
+		return
+	}
+	c.c.mtx.Lock()
+	c.c.value += value
+	c.c.mtx.Unlock()
+}
+
+// GaugeVec Value which goes up and down
+type GaugeVec struct {
+	*vec
+}
+
+func (v *GaugeVec) With(labelValues ...string) *Gauge {
+	return &Gauge{c: v.with(labelValues, 0)}
+}
+
+func (v *GaugeVec) write(w *bufio.Writer) {
+	v.writeHeader(w)
+	for _, c := range v.sortedChildren() {
+		c.mtx.Lock()
+		value := c.value
+		c.mtx.Unlock()
+		writeSample(w, v.metricName, v.labels, c.labelValues, "", "", value)
+	}
+}
+
+type Gauge struct {
+	c *child
+}
+
+func (g *Gauge) Set(value float64) {
+	g.c.mtx.Lock()
+	g.c.value = value
+	g.c.mtx.Unlock()
+}
+
+func (g *Gauge) Add(value float64) {
+	g.c.mtx.Lock()
+	g.c.value += value
+	g.c.mtx.Unlock()
+}
+
+func (g *Gauge) Inc() { g.Add(1) }
+func (g *Gauge) Dec() { g.Add(-1) }
+
+// HistogramVec Distribution of the observed values over the buckets
+type HistogramVec struct {
+	*vec
+	buckets []float64
+}
+
+func (v *HistogramVec) With(labelValues ...string) *Histogram {
+	return &Histogram{c: v.with(labelValues, len(v.buckets)), buckets: v.buckets}
+}
+
+func (v *HistogramVec) write(w *bufio.Writer) {
+	v.writeHeader(w)
+	for _, c := range v.sortedChildren() {
+		c.mtx.Lock()
+		hits := append([]uint64(nil), c.hits...)
+		count, sum := c.count, c.sum
+		c.mtx.Unlock()
+
+		var cumulative uint64
+		for i, bound := range v.buckets {
+			cumulative += hits[i]
+			writeSample(w, v.metricName+"_bucket", v.labels, c.labelValues, "le", formatFloat(bound), float64(cumulative))
+		}
+		writeSample(w, v.metricName+"_bucket", v.labels, c.labelValues, "le", "+Inf", float64(count))
+		writeSample(w, v.metricName+"_sum", v.labels, c.labelValues, "", "", sum)
+		writeSample(w, v.metricName+"_count", v.labels, c.labelValues, "", "", float64(count))
+	}
+}
+
+type Histogram struct {
+	c       *child
+	buckets []float64
+}
+
+func (h *Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.buckets, value) // the first bucket with the bound >= value
+	h.c.mtx.Lock()
+	if i < len(h.c.hits) {
+		h.c.hits[i]++
+	}
+	h.c.count++
+	h.c.sum += value
+	h.c.mtx.Unlock()
+}
+
+// GaugeFunc Gauge whose value is provided by the function at the scrape, the sample is omitted while the function is not set
+type GaugeFunc struct {
+	metricName string
+	help       string
+
+	mtx sync.RWMutex
+	f   func() (float64, bool)
+}
+
+func (g *GaugeFunc) name() string { return g.metricName }
+
+// Set The function returns the value and whether it is known
+func (g *GaugeFunc) Set(f func() (float64, bool)) {
+	g.mtx.Lock()
+	g.f = f
+	g.mtx.Unlock()
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	writeHeader(w, g.metricName, g.help, typeGauge)
+	g.mtx.RLock()
+	f := g.f
+	g.mtx.RUnlock()
+	if f == nil {
+		return
+	}
+	if value, ok := f(); ok {
+		writeSample(w, g.metricName, nil, nil, "", "", value)
+	}
+}
+
+func writeHeader(w *bufio.Writer, name, help, metricType string) {
+	if help != "" {
+		w.WriteString("# HELP " + name + " " + escapeHelp(help) + "\n")
+	}
+	w.WriteString("# TYPE " + name + " " + metricType + "\n")
+}
+
+// writeSample The extra label is used for the histogram bucket bound
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraLabel, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraLabel != "" {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label + "=\"" + escapeLabelValue(values[i]) + "\"")
+		}
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(extraLabel + "=\"" + extraValue + "\"")
+		}
+		w.WriteByte('}')
+	}
+	w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string       { return helpReplacer.Replace(s) }
+func escapeLabelValue(s string) string { return labelReplacer.Replace(s) }
@@ -0,0 +1,446 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/scheduler"
+)
+
+const (
+	outboxFile   = "outbox.jsonl"
+	maxLineSize  = 1024 * 1024
+	compactLines = 1000        // the journal is compacted at runtime only after this count of lines
+	idleWait     = time.Minute // wait if there are no pending messages, enqueueing wakes the delivery earlier
+	sendTimeout  = 2 * time.Minute
+)
+
+// record Line of the journal, either the actual state of the message or its removal after the delivery
+type record struct {
+	Message   *Message `json:"message,omitempty"`
+	DeletedID uint64   `json:"deleted_id,omitempty"`
+}
+
+// FileOutbox Outbox kept in memory and persisted as append-only JSON lines journal, the last line of a message wins
+type FileOutbox struct {
+	mtx      sync.Mutex
+	path     string
+	sender   Sender
+	options  Options
+	lastID   uint64
+	lines    int
+	messages map[uint64]*Message // message id -> pending or dead message
+	readyAt  map[int64]time.Time // destination -> time when the next message may be sent
+	wake     chan struct{}
+}
+
+func NewFileOutbox(path string, sender Sender, options Options) (*FileOutbox, error) {
+	if sender == nil {
+		return nil, errors.New("FileOutbox.New()", "sender is nil")
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 1
+	}
+	if options.RetryPolicy == nil {
+		options.RetryPolicy = scheduler.DefaultRetryPolicy()
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, errors.Wrapf(err, "FileOutbox.New()", "failed to create outbox directory %s", path)
+	}
+
+	o := &FileOutbox{
+		path:     filepath.Join(path, outboxFile),
+		sender:   sender,
+		options:  options,
+		messages: map[uint64]*Message{},
+		readyAt:  map[int64]time.Time{},
+		wake:     make(chan struct{}, 1),
+	}
+
+	if err := o.load(); err != nil {
+		return nil, errors.Wrap(err, "FileOutbox.New()", "")
+	}
+	if o.lines > 2*len(o.messages) {
+		if err := o.compact(); err != nil {
+			return nil, errors.Wrap(err, "FileOutbox.New()", "")
+		}
+	}
+
+	if pending := len(o.pending()); pending > 0 {
+		logger.Logf(logger.INFO, "FileOutbox.New()", "restore %d pending messages", pending)
+	}
+	return o, nil
+}
+
+func (o *FileOutbox) Enqueue(message *Message) error {
+	if message == nil || message.Text == "" {
+		return errors.New("FileOutbox.Enqueue()", "message or its text is empty")
+	}
+	if message.Destination == 0 {
+		return errors.New("FileOutbox.Enqueue()", "destination is not set")
+	}
+
+	o.mtx.Lock()
+	msg := *message
+	msg.ID = o.lastID + 1
+	msg.Attempts = 0
+	msg.LastError = ""
+	msg.IsDead = false
+	msg.CreatedAt = time.Now()
+	msg.NextAttempt = time.Time{}
+	if err := o.write(&record{Message: &msg}); err != nil {
+		o.mtx.Unlock()
+		return errors.Wrapf(err, "FileOutbox.Enqueue()", "failed to persist message to %d", msg.Destination)
+	}
+	o.lastID = msg.ID
+	o.messages[msg.ID] = &msg
+	o.mtx.Unlock()
+
+	o.notify()
+	return nil
+}
+
+func (o *FileOutbox) Pending() []*Message {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return copyMessages(o.pending())
+}
+
+func (o *FileOutbox) DeadLetters() []*Message {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	result := make([]*Message, 0)
+	for _, msg := range o.messages {
+		if msg.IsDead {
+			result = append(result, msg)
+		}
+	}
+	sortMessages(result)
+	return copyMessages(result)
+}
+
+func (o *FileOutbox) Replay(id uint64) error {
+	o.mtx.Lock()
+	msg, ok := o.messages[id]
+	if !ok || !msg.IsDead {
+		o.mtx.Unlock()
+		return errors.Newf("FileOutbox.Replay()", "dead letter %d not found", id)
+	}
+	err := o.revive(msg)
+	o.mtx.Unlock()
+	if err != nil {
+		return errors.Wrap(err, "FileOutbox.Replay()", "")
+	}
+
+	o.notify()
+	return nil
+}
+
+func (o *FileOutbox) ReplayAll() (int, error) {
+	o.mtx.Lock()
+	count := 0
+	var err error
+	for _, msg := range o.messages {
+		if !msg.IsDead {
+			continue
+		}
+		if err = o.revive(msg); err != nil {
+			break
+		}
+		count++
+	}
+	o.mtx.Unlock()
+
+	if count > 0 {
+		o.notify()
+	}
+	if err != nil {
+		return count, errors.Wrap(err, "FileOutbox.ReplayAll()", "")
+	}
+	return count, nil
+}
+
+func (o *FileOutbox) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.wake:
+		case <-timer.C:
+		}
+
+		wait := o.deliver(ctx)
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+	}
+}
+
+// deliver Sends the due messages one by one, returns the wait until the next due message
+func (o *FileOutbox) deliver(ctx context.Context) time.Duration {
+	for ctx.Err() == nil {
+		msg, wait := o.next(time.Now())
+		if msg == nil {
+			return wait
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := o.sender(sendCtx, msg)
+		cancel()
+
+		if err != nil && ctx.Err() != nil {
+			return 0 // the attempt is not counted, the message is sent again after the restart
+		}
+		o.complete(msg, err)
+	}
+	return 0
+}
+
+// next Returns a copy of the earliest due message among the heads of the destinations
+func (o *FileOutbox) next(now time.Time) (*Message, time.Duration) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	heads := make(map[int64]*Message)
+	for _, msg := range o.messages {
+		if msg.IsDead {
+			continue
+		}
+		if head, ok := heads[msg.Destination]; !ok || msg.ID < head.ID {
+			heads[msg.Destination] = msg
+		}
+	}
+
+	var due *Message
+	wait := idleWait
+	for destination, head := range heads {
+		readyAt := head.NextAttempt
+		if o.readyAt[destination].After(readyAt) {
+			readyAt = o.readyAt[destination]
+		}
+		if readyAt.After(now) {
+			wait = min(wait, readyAt.Sub(now))
+			continue
+		}
+		if due == nil || head.ID < due.ID {
+			due = head
+		}
+	}
+	if due == nil {
+		return nil, wait
+	}
+	msg := *due
+	return &msg, 0
+}
+
+func (o *FileOutbox) complete(sent *Message, err error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	msg, ok := o.messages[sent.ID]
+	if !ok {
+		return
+	}
+	now := time.Now()
+
+	if err == nil {
+		o.readyAt[msg.Destination] = now.Add(msg.Delay)
+		if werr := o.write(&record{DeletedID: msg.ID}); werr != nil {
+			// the message may be sent again after the restart, it is better than losing the next ones
+			logger.Logf(logger.ERROR, "FileOutbox.complete()", "failed to persist delivery of message %d: %v", msg.ID, werr)
+		}
+		delete(o.messages, msg.ID)
+		o.compactIfNeeded()
+		return
+	}
+
+	msg.Attempts++
+	msg.LastError = err.Error()
+	if !o.options.RetryPolicy.IsRetryable(err) || msg.Attempts >= o.options.MaxAttempts {
+		msg.IsDead = true
+		logger.Logf(logger.ERROR, "FileOutbox.complete()", "message %d from %s to %d moved to dead letters after %d attempts: %v", msg.ID, msg.Source, msg.Destination, msg.Attempts, err)
+	} else {
+		backoff := o.options.RetryPolicy.Backoff(msg.Attempts)
+		if o.options.RetryAfter != nil {
+			if retryAfter := o.options.RetryAfter(err); retryAfter > 0 {
+				backoff = retryAfter
+			}
+		}
+		msg.NextAttempt = now.Add(backoff)
+		logger.Logf(logger.WARN, "FileOutbox.complete()", "failed to send message %d from %s to %d, attempt %d/%d, retry in %s: %v", msg.ID, msg.Source, msg.Destination, msg.Attempts, o.options.MaxAttempts, backoff, err)
+	}
+
+	if werr := o.write(&record{Message: msg}); werr != nil {
+		logger.Logf(logger.ERROR, "FileOutbox.complete()", "failed to persist state of message %d: %v", msg.ID, werr)
+	}
+}
+
+// revive Returns the dead letter to the delivery, the caller holds the lock
+func (o *FileOutbox) revive(msg *Message) error {
+	revived := *msg
+	revived.IsDead = false
+	revived.Attempts = 0
+	revived.LastError = ""
+	revived.NextAttempt = time.Time{}
+	if err := o.write(&record{Message: &revived}); err != nil {
+		return errors.Wrapf(err, "FileOutbox.revive()", "failed to persist replay of message %d", msg.ID)
+	}
+	*msg = revived
+	return nil
+}
+
+// pending The caller holds the lock
+func (o *FileOutbox) pending() []*Message {
+	result := make([]*Message, 0, len(o.messages))
+	for _, msg := range o.messages {
+		if !msg.IsDead {
+			result = append(result, msg)
+		}
+	}
+	sortMessages(result)
+	return result
+}
+
+func (o *FileOutbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// write Appends the record to the journal, the caller holds the lock
+func (o *FileOutbox) write(rec *record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return errors.Wrap(err, "FileOutbox.write()", "failed to encode record")
+	}
+
+	file, err := os.OpenFile(o.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "FileOutbox.write()", "failed to open outbox file %s", o.path)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		return errors.Wrapf(err, "FileOutbox.write()", "failed to write outbox file %s", o.path)
+	}
+	if err = file.Sync(); err != nil {
+		return errors.Wrapf(err, "FileOutbox.write()", "failed to sync outbox file %s", o.path)
+	}
+	o.lines++
+	return nil
+}
+
+// load Replays the journal
+func (o *FileOutbox) load() error {
+	file, err := os.Open(o.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "FileOutbox.load()", "failed to open outbox file %s", o.path)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		o.lines++
+		rec := &record{}
+		if err = json.Unmarshal(scanner.Bytes(), rec); err != nil || (rec.Message == nil && rec.DeletedID == 0) {
+			// the tail of the file may be broken if the process was killed during the write
+			logger.Logf(logger.WARN, "FileOutbox.load()", "skip broken line %d in outbox file %s: %v", o.lines, o.path, err)
+			continue
+		}
+		if rec.Message != nil {
+			o.messages[rec.Message.ID] = rec.Message
+			o.lastID = max(o.lastID, rec.Message.ID)
+		} else {
+			delete(o.messages, rec.DeletedID)
+			o.lastID = max(o.lastID, rec.DeletedID)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return errors.Wrapf(err, "FileOutbox.load()", "failed to read outbox file %s", o.path)
+	}
+	return nil
+}
+
+// compactIfNeeded Delivered messages only take up space, the caller holds the lock
+func (o *FileOutbox) compactIfNeeded() {
+	if o.lines < compactLines || o.lines <= 2*len(o.messages) {
+		return
+	}
+	if err := o.compact(); err != nil {
+		logger.Logf(logger.ERROR, "FileOutbox.compactIfNeeded()", "failed to compact outbox: %v", err)
+	}
+}
+
+// compact Rewrites the journal with only actual messages, the last id is kept by the removal record so the ids are not reused
+func (o *FileOutbox) compact() error {
+	messages := make([]*Message, 0, len(o.messages))
+	for _, msg := range o.messages {
+		messages = append(messages, msg)
+	}
+	sortMessages(messages)
+
+	var buf bytes.Buffer
+	for _, msg := range messages {
+		line, err := json.Marshal(&record{Message: msg})
+		if err != nil {
+			return errors.Wrapf(err, "FileOutbox.compact()", "failed to encode message %d", msg.ID)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if _, ok := o.messages[o.lastID]; !ok && o.lastID > 0 {
+		line, err := json.Marshal(&record{DeletedID: o.lastID})
+		if err != nil {
+			return errors.Wrap(err, "FileOutbox.compact()", "failed to encode last id")
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmpPath := o.path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0600); err != nil {
+		return errors.Wrapf(err, "FileOutbox.compact()", "failed to write temporary file %s", tmpPath)
+	}
+	if err := os.Rename(tmpPath, o.path); err != nil {
+		return errors.Wrapf(err, "FileOutbox.compact()", "failed to rename temporary file %s to %s", tmpPath, o.path)
+	}
+	o.lines = bytes.Count(buf.Bytes(), []byte{'\n'})
+	return nil
+}
+
+func sortMessages(messages []*Message) {
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID < messages[j].ID })
+}
+
+func copyMessages(messages []*Message) []*Message {
+	result := make([]*Message, 0, len(messages))
+	for _, msg := range messages {
+		copyMsg := *msg
+		result = append(result, &copyMsg)
+	}
+	return result
+}
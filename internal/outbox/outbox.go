@@ -0,0 +1,47 @@
+package outbox
+
+import (
+	"context"
+	"time"
+	"wb_logistic_assistant/internal/scheduler"
+)
+
+// Message Outbound message, the messages of one destination are delivered in the order of enqueueing
+type Message struct {
+	ID          uint64        `json:"id"`
+	Destination int64         `json:"destination"` // Telegram chat id
+	Text        string        `json:"text"`
+	ParseMode   string        `json:"parse_mode"`
+	Source      string        `json:"source"`          // component which enqueued the message
+	Delay       time.Duration `json:"delay,omitempty"` // pause before the next message of the destination, antispam
+	Attempts    int           `json:"attempts"`
+	LastError   string        `json:"last_error,omitempty"`
+	IsDead      bool          `json:"is_dead"`
+	CreatedAt   time.Time     `json:"created_at"`
+	NextAttempt time.Time     `json:"next_attempt"`
+}
+
+// Sender Delivers the message to the destination
+type Sender func(ctx context.Context, message *Message) error
+
+type Options struct {
+	MaxAttempts int                   // failed attempts after which the message goes to the dead letters
+	RetryPolicy scheduler.RetryPolicy // backoff between the attempts, the message with a non-retryable error goes to the dead letters at once
+	// RetryAfter Returns the delay demanded by the destination, e.g. Telegram 'retry_after', it overrides the backoff. May be nil
+	RetryAfter func(err error) time.Duration
+}
+
+type Outbox interface {
+	// Enqueue Persists the message before it is sent in the background
+	Enqueue(message *Message) error
+	// Pending Returns the messages waiting for delivery ordered by id
+	Pending() []*Message
+	// DeadLetters Returns the messages which failed to be delivered ordered by id
+	DeadLetters() []*Message
+	// Replay Returns the dead letter to the delivery with reset attempts
+	Replay(id uint64) error
+	// ReplayAll Returns all dead letters to the delivery, returns their count
+	ReplayAll() (int, error)
+	// Run Delivers the messages until the context is done
+	Run(ctx context.Context)
+}
@@ -0,0 +1,35 @@
+package prompters
+
+import (
+	"fmt"
+	"time"
+)
+
+const prefixCLIReporterDriverPerformancePrompter = "[Отчет по водителям]"
+
+type CLIReporterDriverPerformancePrompter struct {
+}
+
+func (p *CLIReporterDriverPerformancePrompter) PromptStart(dateStart, dateEnd time.Time) {
+	fmt.Printf("%s Старт формирования... Период: %s - %s\n", prefixCLIReporterDriverPerformancePrompter, dateStart.Format("02.01.2006"), dateEnd.Format("02.01.2006"))
+}
+
+func (p *CLIReporterDriverPerformancePrompter) PromptFinish(duration time.Duration) {
+	fmt.Println(prefixCLIReporterDriverPerformancePrompter, "Сформирован:", duration)
+}
+
+func (p *CLIReporterDriverPerformancePrompter) PromptCountWaySheet(total, closed, opened int) {
+	fmt.Printf("%s Количество путевых листов: %d  Закрыто: %d  Открыто: %d\n", prefixCLIReporterDriverPerformancePrompter, total, closed, opened)
+}
+
+func (p *CLIReporterDriverPerformancePrompter) PromptCountDrivers(count int) {
+	fmt.Printf("%s Количество водителей: %d\n", prefixCLIReporterDriverPerformancePrompter, count)
+}
+
+func (p *CLIReporterDriverPerformancePrompter) PromptSendReport() {
+	fmt.Println(prefixCLIReporterDriverPerformancePrompter, "Отчет отправлен")
+}
+
+func (p *CLIReporterDriverPerformancePrompter) PromptError(message string) {
+	fmt.Println(prefixCLIReporterDriverPerformancePrompter, "Ошибка:", message)
+}
@@ -22,6 +22,14 @@ func (p *CLIReporterFinanceDailyPrompter) PromptCountWaySheet(total, closed, ope
 	fmt.Printf("%s Количество путевых листов: %d  Закрыто: %d  Открыто: %d\n", prefixCLIReporterFinanceDailyPrompter, total, closed, opened)
 }
 
+func (p *CLIReporterFinanceDailyPrompter) PromptAlert(rule string, routeID int, isResolved bool) {
+	if isResolved {
+		fmt.Printf("%s Тревога решена: %s  Маршрут: %d\n", prefixCLIReporterFinanceDailyPrompter, rule, routeID)
+		return
+	}
+	fmt.Printf("%s Тревога: %s  Маршрут: %d\n", prefixCLIReporterFinanceDailyPrompter, rule, routeID)
+}
+
 func (p *CLIReporterFinanceDailyPrompter) PromptSendReport(routeID int) {
 	fmt.Printf("%s Отчет отправлен. Маршрут: %d\n", prefixCLIReporterFinanceDailyPrompter, routeID)
 }
@@ -0,0 +1,43 @@
+package prompters
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	prefixCLIReporterFinanceWeeklyPrompter  = "[Отчет недельных финансов]"
+	prefixCLIReporterFinanceMonthlyPrompter = "[Отчет месячных финансов]"
+)
+
+type CLIReporterFinancePeriodPrompter struct {
+	prefix string
+}
+
+func NewCLIReporterFinanceWeeklyPrompter() *CLIReporterFinancePeriodPrompter {
+	return &CLIReporterFinancePeriodPrompter{prefix: prefixCLIReporterFinanceWeeklyPrompter}
+}
+
+func NewCLIReporterFinanceMonthlyPrompter() *CLIReporterFinancePeriodPrompter {
+	return &CLIReporterFinancePeriodPrompter{prefix: prefixCLIReporterFinanceMonthlyPrompter}
+}
+
+func (p *CLIReporterFinancePeriodPrompter) PromptStart(dateStart, dateEnd time.Time) {
+	fmt.Printf("%s Старт формирования... Период: %s - %s\n", p.prefix, dateStart.Format("02.01.2006"), dateEnd.Format("02.01.2006"))
+}
+
+func (p *CLIReporterFinancePeriodPrompter) PromptFinish(duration time.Duration) {
+	fmt.Println(p.prefix, "Сформирован:", duration)
+}
+
+func (p *CLIReporterFinancePeriodPrompter) PromptCountWaySheet(total, closed, opened int) {
+	fmt.Printf("%s Количество путевых листов: %d  Закрыто: %d  Открыто: %d\n", p.prefix, total, closed, opened)
+}
+
+func (p *CLIReporterFinancePeriodPrompter) PromptSendReport() {
+	fmt.Println(p.prefix, "Отчет отправлен")
+}
+
+func (p *CLIReporterFinancePeriodPrompter) PromptError(message string) {
+	fmt.Println(p.prefix, "Ошибка:", message)
+}
@@ -38,6 +38,18 @@ func (p *CLIReporterGeneralRoutesPrompter) PromptUpdateWaySheets() {
 	fmt.Println(prefixCLIReporterGeneralRoutesPrompter, "Обновлены путевые листы")
 }
 
+func (p *CLIReporterGeneralRoutesPrompter) PromptRatingDrop(routeID int, component string, from, to float64) {
+	fmt.Printf("%s Падение рейтинга. Маршрут: %d  %s: %.2f -> %.2f\n", prefixCLIReporterGeneralRoutesPrompter, routeID, component, from, to)
+}
+
+func (p *CLIReporterGeneralRoutesPrompter) PromptAlert(rule string, routeID int, isResolved bool) {
+	if isResolved {
+		fmt.Printf("%s Тревога решена: %s  Маршрут: %d\n", prefixCLIReporterGeneralRoutesPrompter, rule, routeID)
+		return
+	}
+	fmt.Printf("%s Тревога: %s  Маршрут: %d\n", prefixCLIReporterGeneralRoutesPrompter, rule, routeID)
+}
+
 func (p *CLIReporterGeneralRoutesPrompter) PromptSendReport(target string) {
 	fmt.Println(prefixCLIReporterGeneralRoutesPrompter, "Отправлен:", target)
 }
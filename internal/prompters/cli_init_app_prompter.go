@@ -205,5 +205,5 @@ func (p *CLIInitAppPrompter) PromptTelegramBotAuthSuccessful(name string) {
 }
 
 func (p *CLIInitAppPrompter) PromptInitFinish() {
-	fmt.Println("****************************************************\n")
+	fmt.Print("****************************************************\n\n")
 }
@@ -0,0 +1,22 @@
+package prompters
+
+import (
+	"fmt"
+)
+
+const prefixCLITelegramCommandsPrompter = "[Команды Telegram бота]"
+
+type CLITelegramCommandsPrompter struct {
+}
+
+func (p *CLITelegramCommandsPrompter) PromptStart(botName string) {
+	fmt.Println(prefixCLITelegramCommandsPrompter, "Ожидание команд... Бот:", botName)
+}
+
+func (p *CLITelegramCommandsPrompter) PromptCommand(command string, chatID int64) {
+	fmt.Printf("%s Команда: /%s  Чат: %d\n", prefixCLITelegramCommandsPrompter, command, chatID)
+}
+
+func (p *CLITelegramCommandsPrompter) PromptError(message string) {
+	fmt.Println(prefixCLITelegramCommandsPrompter, "Ошибка:", message)
+}
@@ -0,0 +1,158 @@
+package prompters
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const headlessHTTPMessagesLimit = 50
+
+// HTTPDelegate Local page which shows the messages of the headless startup and the form of the pending prompt.
+// The server is started on the first use
+type HTTPDelegate struct {
+	address string
+
+	mtx      sync.Mutex
+	server   *http.Server
+	messages []string
+	prompt   string
+	answers  chan string // not nil while the prompt is pending
+}
+
+func NewHTTPDelegate(address string) *HTTPDelegate {
+	return &HTTPDelegate{address: address}
+}
+
+func (d *HTTPDelegate) Notify(message string) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if err := d.start(); err != nil {
+		return err
+	}
+	d.addMessage(message)
+	return nil
+}
+
+// Request Only one prompt is pending at the time, the initializers ask sequentially
+func (d *HTTPDelegate) Request(ctx context.Context, prompt string) (string, error) {
+	d.mtx.Lock()
+	if err := d.start(); err != nil {
+		d.mtx.Unlock()
+		return "", err
+	}
+	if d.answers != nil {
+		d.mtx.Unlock()
+		return "", fmt.Errorf("another prompt is pending")
+	}
+	answers := make(chan string, 1)
+	d.prompt = prompt
+	d.answers = answers
+	d.mtx.Unlock()
+
+	defer func() {
+		d.mtx.Lock()
+		d.prompt = ""
+		d.answers = nil
+		d.mtx.Unlock()
+	}()
+
+	select {
+	case answer := <-answers:
+		return answer, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (d *HTTPDelegate) Close() error {
+	d.mtx.Lock()
+	server := d.server
+	d.server = nil
+	d.mtx.Unlock()
+	if server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+// start Must be called under the lock
+func (d *HTTPDelegate) start() error {
+	if d.server != nil {
+		return nil
+	}
+	listener, err := net.Listen("tcp", d.address)
+	if err != nil {
+		return fmt.Errorf("failed listen %s: %w", d.address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", d.handlePage)
+	mux.HandleFunc("POST /{$}", d.handleAnswer)
+	d.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go d.server.Serve(listener)
+	return nil
+}
+
+// addMessage Must be called under the lock
+func (d *HTTPDelegate) addMessage(message string) {
+	d.messages = append(d.messages, time.Now().Format(time.DateTime)+": "+message)
+	if len(d.messages) > headlessHTTPMessagesLimit {
+		d.messages = d.messages[len(d.messages)-headlessHTTPMessagesLimit:]
+	}
+}
+
+func (d *HTTPDelegate) handlePage(w http.ResponseWriter, _ *http.Request) {
+	d.mtx.Lock()
+	messages := append([]string(nil), d.messages...)
+	prompt := d.prompt
+	isPending := d.answers != nil
+	d.mtx.Unlock()
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html><html><head><meta charset=\"utf-8\"><title>WB logistic assistant</title></head><body>")
+	if isPending {
+		b.WriteString("<pre>" + html.EscapeString(prompt) + "</pre>")
+		b.WriteString("<form method=\"post\"><input name=\"answer\" autofocus autocomplete=\"off\"> <button type=\"submit\">Отправить</button></form>")
+	} else {
+		b.WriteString("<p>Нет ожидающих запросов</p>")
+	}
+	b.WriteString("<hr>")
+	for i := len(messages) - 1; i >= 0; i-- {
+		b.WriteString("<pre>" + html.EscapeString(messages[i]) + "</pre>")
+	}
+	b.WriteString("</body></html>")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func (d *HTTPDelegate) handleAnswer(w http.ResponseWriter, r *http.Request) {
+	answer := strings.TrimSpace(r.FormValue("answer"))
+
+	d.mtx.Lock()
+	answers := d.answers
+	if answers != nil && answer != "" {
+		d.answers = nil // the next answers are not accepted until the next prompt
+		answers <- answer
+	}
+	d.mtx.Unlock()
+
+	if answers == nil {
+		http.Error(w, "no pending prompt", http.StatusConflict)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
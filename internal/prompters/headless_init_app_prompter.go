@@ -0,0 +1,236 @@
+package prompters
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"wb_logistic_assistant/internal/logger"
+)
+
+// Answers of PromptWBLogisticRequestAuthCode which are not the code, see the WB logistic initializer
+const (
+	headlessRepeatCodeRequest = 1
+	headlessExit              = 3
+)
+
+// HeadlessDelegate Channel of the interactive steps in the headless mode, e.g. the local HTTP page or the admin Telegram chat
+type HeadlessDelegate interface {
+	Notify(message string) error
+	// Request Sends the prompt and waits for the answer until the context is done
+	Request(ctx context.Context, prompt string) (string, error)
+	Close() error
+}
+
+// HeadlessInitAppPrompter Never reads stdin: the credentials are taken from the storage, the interactive steps
+// are delegated, and without the delegate they fail at once so the startup fails with a clear error
+type HeadlessInitAppPrompter struct {
+	delegate HeadlessDelegate // if nil, interactive steps fail
+	login    string           // WB logistic login from the storage, the code is requested for it
+	timeout  time.Duration
+}
+
+func NewHeadlessInitAppPrompter(delegate HeadlessDelegate, login string, timeout time.Duration) *HeadlessInitAppPrompter {
+	return &HeadlessInitAppPrompter{
+		delegate: delegate,
+		login:    login,
+		timeout:  timeout,
+	}
+}
+
+//// Google sheets
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsAuthStart() {
+	logger.Log(logger.INFO, "HeadlessInitAppPrompter.PromptGoogleSheetsAuthStart()", "Google Sheets authorization")
+}
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsQuestionAuthNewCredentials() bool {
+	return false
+}
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsRequestAuthCodeAuto(url string, seconds int) {
+	p.notify("HeadlessInitAppPrompter.PromptGoogleSheetsRequestAuthCodeAuto()",
+		fmt.Sprintf("Ссылка авторизации Google Sheets: %s\nОжидание %d секунд", url, seconds))
+}
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsRequestAuthCode(url string) (string, error) {
+	code, err := p.request("HeadlessInitAppPrompter.PromptGoogleSheetsRequestAuthCode()",
+		fmt.Sprintf("Ссылка авторизации Google Sheets: %s\nОтправьте код авторизации", url))
+	if err != nil {
+		return "", err
+	}
+	if code == "" {
+		return "", fmt.Errorf("code is empty")
+	}
+	return code, nil
+}
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsInvalidAuthCode() {
+	p.notify("HeadlessInitAppPrompter.PromptGoogleSheetsInvalidAuthCode()", "Введен невалидный код авторизации Google Sheets")
+}
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsReadCredentialsFailed() {
+	logger.Log(logger.ERROR, "HeadlessInitAppPrompter.PromptGoogleSheetsReadCredentialsFailed()", "failed to read Google Sheets application credentials")
+}
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsAuthAutoFailed() {
+	logger.Log(logger.WARN, "HeadlessInitAppPrompter.PromptGoogleSheetsAuthAutoFailed()", "Google Sheets automatic authorization failed")
+}
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsAuthFailed() {
+	p.notify("HeadlessInitAppPrompter.PromptGoogleSheetsAuthFailed()", "Не удалось провести авторизацию Google Sheets")
+}
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsAuthStorageFailed() {
+	logger.Log(logger.WARN, "HeadlessInitAppPrompter.PromptGoogleSheetsAuthStorageFailed()", "Google Sheets authorization using storage failed")
+}
+
+func (p *HeadlessInitAppPrompter) PromptGoogleSheetsAuthSuccessful() {
+	logger.Log(logger.INFO, "HeadlessInitAppPrompter.PromptGoogleSheetsAuthSuccessful()", "Google Sheets authorization successful")
+}
+
+//// WB logistic
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticAuthStart() {
+	logger.Log(logger.INFO, "HeadlessInitAppPrompter.PromptWBLogisticAuthStart()", "WB logistic authorization")
+}
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticQuestionAuthNewUser() bool {
+	return false
+}
+
+// PromptWBLogisticRequestAuthLogin The login from the storage is used, without the delegate it is empty
+// so the code is not requested when nobody can enter it
+func (p *HeadlessInitAppPrompter) PromptWBLogisticRequestAuthLogin() string {
+	if p.delegate == nil {
+		logger.Log(logger.ERROR, "HeadlessInitAppPrompter.PromptWBLogisticRequestAuthLogin()", "WB logistic session can not be restored in the headless mode without the delegate")
+		return ""
+	}
+	if p.login != "" {
+		return p.login
+	}
+	login, err := p.request("HeadlessInitAppPrompter.PromptWBLogisticRequestAuthLogin()", "Отправьте логин WB logistic (79991112233)")
+	if err != nil {
+		return ""
+	}
+	login = strings.ReplaceAll(login, " ", "")
+	return strings.TrimPrefix(login, "+")
+}
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticInvalidAuthLogin() {
+	p.notify("HeadlessInitAppPrompter.PromptWBLogisticInvalidAuthLogin()", "Невалидный логин WB logistic. Пример: 79991112233")
+}
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticRequestAuthCodeFailed(err string) {
+	p.notify("HeadlessInitAppPrompter.PromptWBLogisticRequestAuthCodeFailed()", "Не удалось получить код доступа WB logistic: "+err)
+}
+
+// PromptWBLogisticRequestAuthCode Exit if the answer is not received
+func (p *HeadlessInitAppPrompter) PromptWBLogisticRequestAuthCode(method string, seconds int) int {
+	answer, err := p.request("HeadlessInitAppPrompter.PromptWBLogisticRequestAuthCode()",
+		fmt.Sprintf("Отправлено %s уведомление с кодом WB logistic. Повторная отправка через %d сек.\nОтправьте код или номер действия:\n1. Повторить отправку кода\n3. Выход", method, seconds))
+	if err != nil {
+		return headlessExit
+	}
+
+	code, err := strconv.Atoi(answer)
+	if err != nil {
+		p.PromptWBLogisticInvalidAuthCode()
+		return headlessRepeatCodeRequest
+	}
+	return code
+}
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticInvalidAuthCode() {
+	p.notify("HeadlessInitAppPrompter.PromptWBLogisticInvalidAuthCode()", "Введен невалидный код авторизации WB logistic")
+}
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticRequestAccessTokenData() string {
+	token, err := p.request("HeadlessInitAppPrompter.PromptWBLogisticRequestAccessTokenData()", "Отправьте данные токена доступа WB logistic")
+	if err != nil {
+		return ""
+	}
+	return strings.ReplaceAll(token, " ", "")
+}
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticInvalidAccessTokenData() {
+	p.notify("HeadlessInitAppPrompter.PromptWBLogisticInvalidAccessTokenData()", "Невалидные данные токена доступа WB logistic")
+}
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticAuthFailed() {
+	p.notify("HeadlessInitAppPrompter.PromptWBLogisticAuthFailed()", "Не удалось провести авторизацию WB logistic")
+}
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticAuthStorageFailed() {
+	logger.Log(logger.WARN, "HeadlessInitAppPrompter.PromptWBLogisticAuthStorageFailed()", "WB logistic authorization using storage failed")
+}
+
+func (p *HeadlessInitAppPrompter) PromptWBLogisticAuthSuccessful(login, username string) {
+	logger.Logf(logger.INFO, "HeadlessInitAppPrompter.PromptWBLogisticAuthSuccessful()", "WB logistic authorization successful, user: %s (%s)", username, login)
+}
+
+//// Telegram bot
+
+func (p *HeadlessInitAppPrompter) PromptTelegramBotAuthStart() {
+	logger.Log(logger.INFO, "HeadlessInitAppPrompter.PromptTelegramBotAuthStart()", "Telegram bot authorization")
+}
+
+func (p *HeadlessInitAppPrompter) PromptTelegramBotQuestionAuthNewBot() bool {
+	return false
+}
+
+func (p *HeadlessInitAppPrompter) PromptTelegramBotRequestToken() (string, error) {
+	token, err := p.request("HeadlessInitAppPrompter.PromptTelegramBotRequestToken()", "Отправьте токен Telegram bot")
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", fmt.Errorf("token is empty")
+	}
+	return token, nil
+}
+
+func (p *HeadlessInitAppPrompter) PromptTelegramBotInitStorageFailed() {
+	logger.Log(logger.WARN, "HeadlessInitAppPrompter.PromptTelegramBotInitStorageFailed()", "Telegram bot init using storage failed")
+}
+
+func (p *HeadlessInitAppPrompter) PromptTelegramBotInitFailed() {
+	logger.Log(logger.ERROR, "HeadlessInitAppPrompter.PromptTelegramBotInitFailed()", "Telegram bot init failed")
+}
+
+func (p *HeadlessInitAppPrompter) PromptTelegramBotAuthSuccessful(name string) {
+	logger.Logf(logger.INFO, "HeadlessInitAppPrompter.PromptTelegramBotAuthSuccessful()", "Telegram bot '%s' authorization successful", name)
+}
+
+func (p *HeadlessInitAppPrompter) PromptInitFinish() {
+	logger.Log(logger.INFO, "HeadlessInitAppPrompter.PromptInitFinish()", "Init finished")
+}
+
+// request Fails at once without the delegate, the answer is trimmed
+func (p *HeadlessInitAppPrompter) request(loc, prompt string) (string, error) {
+	if p.delegate == nil {
+		logger.Logf(logger.ERROR, loc, "input is required, but it is not available in the headless mode without the delegate: %s", prompt)
+		return "", fmt.Errorf("input is not available in the headless mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	answer, err := p.delegate.Request(ctx, prompt)
+	if err != nil {
+		logger.Logf(logger.ERROR, loc, "failed to receive the answer from the delegate: %v", err)
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// notify Logs the message and forwards it to the delegate if any
+func (p *HeadlessInitAppPrompter) notify(loc, message string) {
+	logger.Log(logger.INFO, loc, message)
+	if p.delegate == nil {
+		return
+	}
+	if err := p.delegate.Notify(message); err != nil {
+		logger.Logf(logger.WARN, loc, "failed to notify the delegate: %v", err)
+	}
+}
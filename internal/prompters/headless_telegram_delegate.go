@@ -0,0 +1,95 @@
+package prompters
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const headlessTelegramPollTimeout = 10 // seconds, long polling of the answer
+
+// TelegramDelegate Admin chat which receives the messages of the headless startup and answers the prompts.
+// It polls the updates itself, so it must not be used while the Telegram commands are handled
+type TelegramDelegate struct {
+	token  string
+	chatID int64
+
+	mtx    sync.Mutex
+	bot    *tgbotapi.BotAPI
+	offset int
+}
+
+func NewTelegramDelegate(token string, chatID int64) *TelegramDelegate {
+	return &TelegramDelegate{token: token, chatID: chatID}
+}
+
+func (d *TelegramDelegate) Notify(message string) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.send(message)
+}
+
+// Request The first text message of the chat after the prompt is the answer
+func (d *TelegramDelegate) Request(ctx context.Context, prompt string) (string, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if err := d.send(prompt); err != nil {
+		return "", err
+	}
+	sentAt := time.Now().Add(-time.Second) // the message date is in seconds
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		updates, err := d.bot.GetUpdates(tgbotapi.UpdateConfig{
+			Offset:         d.offset,
+			Timeout:        headlessTelegramPollTimeout,
+			AllowedUpdates: []string{"message"},
+		})
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			d.offset = update.UpdateID + 1
+			message := update.Message
+			if message == nil || message.Chat == nil || message.Chat.ID != d.chatID || message.IsCommand() {
+				continue
+			}
+			if message.Time().Before(sentAt) || message.Text == "" {
+				continue
+			}
+			return message.Text, nil
+		}
+	}
+}
+
+func (d *TelegramDelegate) Close() error {
+	return nil
+}
+
+// send Must be called under the lock, the bot is created on the first use
+func (d *TelegramDelegate) send(message string) error {
+	if d.bot == nil {
+		if d.token == "" {
+			return fmt.Errorf("telegram bot token is empty")
+		}
+		bot, err := tgbotapi.NewBotAPI(d.token)
+		if err != nil {
+			return fmt.Errorf("failed to init telegram bot: %w", err)
+		}
+		d.bot = bot
+	}
+	_, err := d.bot.Send(tgbotapi.NewMessage(d.chatID, message))
+	return err
+}
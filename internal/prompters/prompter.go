@@ -54,6 +54,8 @@ type GeneralRoutesReporterPrompter interface {
 	PromptUpdateRating()
 	PromptCloseShipment(id, remainsBarcodes int)
 	PromptUpdateWaySheets()
+	PromptRatingDrop(routeID int, component string, from, to float64)
+	PromptAlert(rule string, routeID int, isResolved bool)
 	PromptSendReport(target string)
 	PromptError(message string)
 }
@@ -80,6 +82,30 @@ type FinanceDailyReporterPrompter interface {
 	PromptStart(date time.Time)
 	PromptFinish(duration time.Duration)
 	PromptCountWaySheet(total, closed, opened int)
+	PromptAlert(rule string, routeID int, isResolved bool)
 	PromptSendReport(routeID int)
 	PromptError(message string)
 }
+
+type FinancePeriodReporterPrompter interface {
+	PromptStart(dateStart, dateEnd time.Time)
+	PromptFinish(duration time.Duration)
+	PromptCountWaySheet(total, closed, opened int)
+	PromptSendReport()
+	PromptError(message string)
+}
+
+type DriverPerformanceReporterPrompter interface {
+	PromptStart(dateStart, dateEnd time.Time)
+	PromptFinish(duration time.Duration)
+	PromptCountWaySheet(total, closed, opened int)
+	PromptCountDrivers(count int)
+	PromptSendReport()
+	PromptError(message string)
+}
+
+type TelegramCommandsPrompter interface {
+	PromptStart(botName string)
+	PromptCommand(command string, chatID int64)
+	PromptError(message string)
+}
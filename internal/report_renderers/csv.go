@@ -0,0 +1,71 @@
+package report_renderers
+
+import (
+	"wb_logistic_assistant/internal/reports"
+)
+
+// CSVRenderer Lays out the report the same way as GoogleSheetsRenderer, but with plain text cells
+type CSVRenderer struct {
+	out  [][]string
+	posX int
+	posY int
+}
+
+func (r *CSVRenderer) Render(report *reports.ReportData) ([][]string, error) {
+	r.out = [][]string{}
+	r.posX, r.posY = 0, 0
+
+	if report.Header != nil {
+		r.render(report.Header)
+		r.posY++
+		r.posX = 0
+	}
+
+	if report.Body != nil {
+		r.render(report.Body)
+	}
+
+	return r.out, nil
+}
+
+func (r *CSVRenderer) render(item *reports.Item) {
+	for i, child := range item.Children {
+		if child == nil {
+			r.expand(r.posY, r.posX)
+			r.posX++
+			continue
+		}
+		if child.Block && i != 0 {
+			r.posY++
+			r.posX = 0
+		}
+
+		if child.Text != "" || child.Link != "" {
+			val := child.Text
+			if val == "" {
+				val = child.Link
+			}
+
+			r.expand(r.posY, r.posX)
+			r.out[r.posY][r.posX] = val
+			r.posX++
+		}
+
+		if len(child.Children) > 0 {
+			r.render(child)
+		}
+	}
+}
+
+func (r *CSVRenderer) expand(y, x int) {
+	for len(r.out) <= y {
+		r.out = append(r.out, make([]string, 0))
+	}
+
+	row := r.out[y]
+	if len(row) <= x {
+		newRow := make([]string, x+1)
+		copy(newRow, row)
+		r.out[y] = newRow
+	}
+}
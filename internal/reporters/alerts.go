@@ -0,0 +1,74 @@
+package reporters
+
+import (
+	"context"
+	"time"
+	"wb_logistic_assistant/internal/alerts"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/report_renderers"
+	"wb_logistic_assistant/internal/reports"
+	"wb_logistic_assistant/internal/services"
+)
+
+// alertNotifier Evaluates the alert rules of one source and sends fired and resolved alerts to Telegram bot
+type alertNotifier struct {
+	services *services.Container
+	source   string
+	report   *reports.AlertReport
+
+	rendererTG report_renderers.ReportRenderer[[]string]
+	tgChatID   int64
+}
+
+func newAlertNotifier(services *services.Container, source string, tgChatID int64) *alertNotifier {
+	return &alertNotifier{
+		services:   services,
+		source:     source,
+		report:     &reports.AlertReport{},
+		rendererTG: &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
+		tgChatID:   tgChatID,
+	}
+}
+
+// isEnabled There are rules over the source of the notifier
+func (n *alertNotifier) isEnabled() bool {
+	return n.services.Alerts != nil && n.services.Alerts.HasSource(n.source)
+}
+
+func (n *alertNotifier) process(ctx context.Context, now time.Time, samples []*alerts.Sample) ([]*alerts.Event, error) {
+	if !n.isEnabled() {
+		return nil, nil
+	}
+
+	events := n.services.Alerts.Evaluate(n.source, now, samples)
+	if len(events) > 0 {
+		data := &reports.AlertReportData{Alerts: make([]*reports.AlertData, 0, len(events))}
+		for _, event := range events {
+			data.Alerts = append(data.Alerts, &reports.AlertData{
+				Rule:       event.Rule,
+				Metric:     event.Metric,
+				Operator:   event.Operator,
+				Threshold:  event.Threshold,
+				Value:      event.Value,
+				IsDuration: alerts.IsDurationMetric(event.Metric),
+				RouteID:    event.RouteID,
+				Since:      event.Since,
+				Time:       event.Time,
+				IsResolved: event.IsResolved,
+			})
+		}
+
+		report, err := n.report.Render(data)
+		if err != nil {
+			return events, errors.Wrap(err, "alertNotifier.process()", "failed render alerts")
+		}
+		messages, err := n.rendererTG.Render(report)
+		if err != nil {
+			return events, errors.Wrap(err, "alertNotifier.process()", "failed render alerts for Telegram Bot")
+		}
+		if err = enqueueTelegramBot(n.services, "alerts."+n.source, n.tgChatID, messages, 0); err != nil {
+			return events, errors.Wrap(err, "alertNotifier.process()", "failed send alerts to Telegram Bot")
+		}
+	}
+	return events, nil
+}
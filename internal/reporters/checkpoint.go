@@ -0,0 +1,39 @@
+package reporters
+
+import (
+	"encoding/json"
+	"wb_logistic_assistant/internal/config"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/storage"
+)
+
+// saveCheckpoint Puts the reporter state into the cache store and saves the storage, so the state survives the restart.
+// The storage is saved only if the state has changed since the previous checkpoint
+func saveCheckpoint(config *config.Config, storage storage.Storage, key string, state interface{}) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrapf(err, "Reporters.saveCheckpoint()", "failed encode state %s", key)
+	}
+
+	if storage.CacheStore().Get(key) == string(data) {
+		return nil
+	}
+	storage.CacheStore().Set(key, string(data))
+
+	if err = storage.Save(config.Storage().Path()); err != nil {
+		return errors.Wrapf(err, "Reporters.saveCheckpoint()", "failed save storage with state %s", key)
+	}
+	return nil
+}
+
+// loadCheckpoint Restores the reporter state from the cache store, returns false if there is no saved state
+func loadCheckpoint(storage storage.Storage, key string, state interface{}) (bool, error) {
+	data := storage.CacheStore().Get(key)
+	if data == "" {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(data), state); err != nil {
+		return false, errors.Wrapf(err, "Reporters.loadCheckpoint()", "failed decode state %s", key)
+	}
+	return true, nil
+}
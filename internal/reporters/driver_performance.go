@@ -0,0 +1,334 @@
+package reporters
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
+
+	"wb_logistic_assistant/internal/config"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/prompters"
+	"wb_logistic_assistant/internal/report_renderers"
+	"wb_logistic_assistant/internal/reports"
+	"wb_logistic_assistant/internal/services"
+	"wb_logistic_assistant/internal/storage"
+)
+
+// DriverPerformanceReporter Flights, deliveries, returns, fines and completion of addresses grouped by drivers over a period
+type DriverPerformanceReporter struct {
+	config   *config.Config
+	storage  storage.Storage
+	services *services.Container
+	report   *reports.DriverPerformanceReport
+	prompter prompters.DriverPerformanceReporterPrompter
+
+	rendererTG report_renderers.ReportRenderer[[]string]
+	isRenderTG bool
+	tgChatID   int64
+
+	rendererGS    report_renderers.ReportRenderer[[][]interface{}]
+	isRenderGS    bool
+	spreadsheetID string
+	sheetName     string
+	sheetPosition string
+
+	officeID        int
+	suppliers       map[int]struct{} // supplier id -> struct{}
+	skipRoutes      map[int]struct{} // route id -> struct{}
+	period          ReportPeriod
+	periodOffset    int
+	isLoadAddresses bool
+	timeLastRender  time.Time
+	isRender        bool
+	isCalendar      bool // runs by the calendar schedule, so every run renders
+}
+
+func NewDriverPerformanceReporter(config *config.Config, storage storage.Storage, service *services.Container, prompter prompters.DriverPerformanceReporterPrompter) *DriverPerformanceReporter {
+	return &DriverPerformanceReporter{
+		config:   config,
+		storage:  storage,
+		services: service,
+		prompter: prompter,
+		report:   &reports.DriverPerformanceReport{},
+
+		rendererTG: &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
+		isRenderTG: config.Reports().DriverPerformance().IsRenderTelegramBot(),
+		tgChatID:   config.Telegram().DriverPerformance().ChatID(),
+
+		rendererGS:    &report_renderers.GoogleSheetsRenderer{},
+		isRenderGS:    config.Reports().DriverPerformance().IsRenderGoogleSheets(),
+		spreadsheetID: config.GoogleSheets().ReportSheets().DriverPerformance().SpreadsheetID(),
+		sheetName:     config.GoogleSheets().ReportSheets().DriverPerformance().SheetName(),
+		sheetPosition: "A1",
+
+		officeID:        config.Logistic().Office().ID(),
+		suppliers:       config.Logistic().Office().SuppliersMap(),
+		skipRoutes:      config.Logistic().Office().SkipRoutesMap(),
+		period:          NewReportPeriod(config.Reports().DriverPerformance().Period()),
+		periodOffset:    config.Reports().DriverPerformance().PeriodOffset(),
+		isLoadAddresses: config.Reports().DriverPerformance().IsLoadAddresses(),
+		isRender:        config.Reports().DriverPerformance().RenderAtStart(),
+		isCalendar:      config.Reports().DriverPerformance().Schedule().IsCalendar(),
+	}
+}
+
+func (r *DriverPerformanceReporter) Run(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "DriverPerformanceReporter.Run()", "task was preliminarily completed")
+	}
+
+	now := time.Now()
+	dateStart, dateEnd := r.period.Bounds(now, r.periodOffset)
+	r.prompter.PromptStart(dateStart, dateEnd)
+
+	// isRender is true if it was originally set this way in the configuration or if the period has changed since the function was last run
+	if !r.timeLastRender.IsZero() {
+		prevStart, _ := r.period.Bounds(r.timeLastRender, 0)
+		currentStart, _ := r.period.Bounds(now, 0)
+		if !prevStart.Equal(currentStart) {
+			r.isRender = true
+		}
+	}
+
+	if r.isCalendar {
+		r.isRender = true
+	}
+
+	r.timeLastRender = now
+
+	if !r.isRender {
+		r.prompter.PromptFinish(time.Since(now))
+		return nil
+	}
+	r.isRender = false
+
+	data, err := r.processWaySheets(ctx, dateStart, dateEnd)
+	if err != nil {
+		return errors.Wrap(err, "DriverPerformanceReporter.Run()", "failed processing way sheets")
+	}
+
+	report, err := r.report.Render(data)
+	if err != nil {
+		r.prompter.PromptError("Failed render report")
+		return errors.Wrap(err, "DriverPerformanceReporter.Run()", "failed render report")
+	}
+
+	if err = r.sendReport(ctx, report); err != nil {
+		return errors.Wrap(err, "DriverPerformanceReporter.Run()", "failed send report")
+	}
+
+	r.prompter.PromptFinish(time.Since(now))
+	return nil
+}
+
+func (r *DriverPerformanceReporter) processWaySheets(ctx context.Context, dateStart, dateEnd time.Time) (*reports.DriverPerformanceReportData, error) {
+	logger.Logf(logger.INFO, "DriverPerformanceReporter.processWaySheets()", "start process way sheets %s - %s", dateStart.Format(time.DateOnly), dateEnd.Format(time.DateOnly))
+
+	waySheets, err := r.loadWaySheets(ctx, dateStart, dateEnd)
+	if err != nil {
+		return nil, errors.Wrap(err, "DriverPerformanceReporter.processWaySheets()", "failed load way sheets")
+	}
+
+	drivers := map[string]*reports.DriverPerformanceDriverData{} // driver id -> data
+	openings := map[string][]time.Time{}                         // driver id -> open dates of the way sheets
+	vehicles := map[string]map[string]struct{}{}                 // driver id -> vehicle number plates
+	seen := map[string]struct{}{}                                // way sheet id -> struct{}, pages may overlap
+	closedFlights, openedFlights := 0, 0
+
+	for _, waySheet := range waySheets {
+		if ctx.Err() != nil {
+			return nil, errors.Wrap(ctx.Err(), "DriverPerformanceReporter.processWaySheets()", "task was preliminarily completed")
+		}
+
+		if waySheet == nil || waySheet.OpenDt.Before(dateStart) || waySheet.OpenDt.After(dateEnd) {
+			continue
+		}
+		if _, ok := seen[waySheet.WaySheetID]; ok {
+			continue
+		}
+		seen[waySheet.WaySheetID] = struct{}{}
+
+		if _, ok := r.suppliers[atoiSafe(waySheet.SupplierID)]; !ok {
+			continue
+		}
+		if _, ok := r.skipRoutes[atoiSafe(waySheet.RouteCarID)]; ok {
+			continue
+		}
+
+		driverKey := waySheet.DriverID
+		if driverKey == "" {
+			driverKey = waySheet.DriverName
+		}
+		driver := drivers[driverKey]
+		if driver == nil {
+			driver = &reports.DriverPerformanceDriverData{DriverID: waySheet.DriverID, DriverName: waySheet.DriverName}
+			drivers[driverKey] = driver
+			vehicles[driverKey] = map[string]struct{}{}
+		}
+		if waySheet.VehicleNumberPlate != "" {
+			if _, ok := vehicles[driverKey][waySheet.VehicleNumberPlate]; !ok {
+				vehicles[driverKey][waySheet.VehicleNumberPlate] = struct{}{}
+				driver.VehicleNumber = append(driver.VehicleNumber, waySheet.VehicleNumberPlate)
+			}
+		}
+
+		driver.Flights++
+		openings[driverKey] = append(openings[driverKey], waySheet.OpenDt)
+
+		if waySheet.CloseDt.IsZero() {
+			driver.FlightsOpened++
+			openedFlights++
+			continue
+		}
+		closedFlights++
+
+		tare := atoiSafe(waySheet.CountBox)
+		shippedTare := atoiSafe(waySheet.CountArrivalBox)
+		driver.BarcodesDelivered += atoiSafe(waySheet.CountBarcodes)
+		driver.Tare += tare
+		driver.TareShipped += shippedTare
+		driver.TareReturned += tare - shippedTare
+		driver.Fine += waySheet.SumFine
+
+		if r.isLoadAddresses {
+			waySheetID := atoiSafe(waySheet.WaySheetID)
+			info, err := r.loadWaySheetInfo(ctx, waySheetID)
+			if err != nil {
+				r.prompter.PromptError(fmt.Sprintf("Failed loading way sheet info for way sheet %s", waySheet.WaySheetID))
+				logger.Logf(logger.ERROR, "DriverPerformanceReporter.processWaySheets()", "failed load way sheet info for way sheet %d: %v", waySheetID, err)
+				continue
+			}
+			total, completed, inSequence := r.countAddresses(info.DstOffices)
+			driver.AddressesTotal += total
+			driver.AddressesCompleted += completed
+			driver.AddressesInSequence += inSequence
+		}
+	}
+
+	r.prompter.PromptCountWaySheet(len(seen), closedFlights, openedFlights)
+
+	data := &reports.DriverPerformanceReportData{
+		DateStart: dateStart,
+		DateEnd:   dateEnd,
+		Drivers:   make([]*reports.DriverPerformanceDriverData, 0, len(drivers)),
+	}
+	for driverKey, driver := range drivers {
+		driver.WaySheetsInterval = averageInterval(openings[driverKey])
+		data.Drivers = append(data.Drivers, driver)
+	}
+	sort.SliceStable(data.Drivers, func(i, j int) bool {
+		if data.Drivers[i].Flights != data.Drivers[j].Flights {
+			return data.Drivers[i].Flights > data.Drivers[j].Flights
+		}
+		return data.Drivers[i].DriverName < data.Drivers[j].DriverName
+	})
+	r.prompter.PromptCountDrivers(len(data.Drivers))
+
+	return data, nil
+}
+
+// countAddresses Address is completed if it has an actual sequence number, and completed in sequence if the actual number matches the planned one
+func (r *DriverPerformanceReporter) countAddresses(offices []*wb_models.WaySheetDestinationOffice) (total, completed, inSequence int) {
+	for _, office := range offices {
+		if office == nil {
+			continue
+		}
+		total++
+		if office.SequenceFact == "" || office.SequenceFact == "0" {
+			continue
+		}
+		completed++
+		if office.SequenceFact == office.Sequence {
+			inSequence++
+		}
+	}
+	return total, completed, inSequence
+}
+
+// averageInterval Returns the average interval between consecutive times, 0 if there are less than two
+func averageInterval(times []time.Time) time.Duration {
+	if len(times) < 2 {
+		return 0
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	return times[len(times)-1].Sub(times[0]) / time.Duration(len(times)-1)
+}
+
+func (r *DriverPerformanceReporter) loadWaySheets(ctx context.Context, dOpen, dClose time.Time) ([]*wb_models.WaySheet, error) {
+	return loadWaySheetsPages(ctx, r.services.WBLogisticService, r.prompter, "DriverPerformanceReporter", r.officeID, r.suppliers, dOpen, dClose)
+}
+
+func (r *DriverPerformanceReporter) loadWaySheetInfo(ctx context.Context, waySheetID int) (waySheetInfo *wb_models.WaySheetInfo, err error) {
+	err = retryAction(ctx, "DriverPerformanceReporter.loadWaySheetInfo", 3, 1*time.Second, func() error {
+		waySheetInfo, err = r.services.WBLogisticService.GetWaySheetInfo(ctx, waySheetID)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "DriverPerformanceReporter.loadWaySheetInfo()", "failed load way sheet %d info", waySheetID)
+	}
+
+	if waySheetInfo == nil {
+		return nil, errors.Newf("DriverPerformanceReporter.loadWaySheetInfo()", "way sheet %d info returned empty value without error", waySheetID)
+	}
+
+	return waySheetInfo, nil
+}
+
+func (r *DriverPerformanceReporter) sendReport(ctx context.Context, report *reports.ReportData) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "DriverPerformanceReporter.sendReport()", "task was preliminarily completed")
+	}
+
+	if r.isRenderGS {
+		data, err := r.rendererGS.Render(report)
+		if err != nil {
+			r.prompter.PromptError("Failed to render report Google Sheet")
+			logger.Logf(logger.ERROR, "DriverPerformanceReporter.sendReport()", "failed render report for Google Sheets: %v", err)
+		} else {
+			if err = r.sendGoogleSheets(ctx, data); err != nil {
+				r.prompter.PromptError("Failed to send report Google Sheet")
+				logger.Logf(logger.ERROR, "DriverPerformanceReporter.sendReport()", "failed send report to Google Sheets: %v", err)
+			} else {
+				r.prompter.PromptSendReport()
+				logger.Log(logger.INFO, "DriverPerformanceReporter.sendReport()", "send report to Google Sheets")
+			}
+		}
+	}
+
+	if r.isRenderTG {
+		messages, err := r.rendererTG.Render(report)
+		if err != nil {
+			r.prompter.PromptError("failed to render report Telegram Bot")
+			logger.Logf(logger.ERROR, "DriverPerformanceReporter.sendReport()", "failed render report for Telegram Bot: %v", err)
+		} else {
+			if err = enqueueTelegramBot(r.services, "DriverPerformanceReporter", r.tgChatID, messages, 0); err != nil {
+				r.prompter.PromptError("failed to send report Telegram Bot")
+				logger.Logf(logger.ERROR, "DriverPerformanceReporter.sendReport()", "failed send report to Telegram Bot: %v", err)
+			} else {
+				r.prompter.PromptSendReport()
+				logger.Log(logger.INFO, "DriverPerformanceReporter.sendReport()", "send report to Telegram Bot")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *DriverPerformanceReporter) sendGoogleSheets(ctx context.Context, data [][]interface{}) error {
+	err := retryAction(ctx, "DriverPerformanceReporter.sendGoogleSheets", 3, 1*time.Second, func() error {
+		err := r.services.GoogleSheetsService.ClearValues(r.spreadsheetID, r.sheetName, "A:Z")
+		if err != nil {
+			return errors.Wrapf(err, "DriverPerformanceReporter.sendGoogleSheets()", "failed clear sheet %s, page %s", r.spreadsheetID, r.sheetName)
+		}
+		return r.services.GoogleSheetsService.UpdateValues(r.spreadsheetID, r.sheetName, r.sheetPosition, data, false)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "DriverPerformanceReporter.sendGoogleSheets()", "failed update sheet %s, page %s to position %s", r.spreadsheetID, r.sheetName, r.sheetPosition)
+	}
+	return nil
+}
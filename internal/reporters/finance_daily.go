@@ -7,8 +7,10 @@ import (
 	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
 	"wb_logistic_assistant/internal/models"
 
+	"wb_logistic_assistant/internal/alerts"
 	"wb_logistic_assistant/internal/config"
 	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/history"
 	"wb_logistic_assistant/internal/logger"
 	"wb_logistic_assistant/internal/prompters"
 	"wb_logistic_assistant/internal/report_renderers"
@@ -54,10 +56,9 @@ type FinanceDailyReporter struct {
 	reportGeneral *reports.FinanceDailyGeneralReport
 	prompter      prompters.FinanceDailyReporterPrompter
 
-	messageQueueTG *Queue[string]
-	rendererTG     report_renderers.ReportRenderer[[]string]
-	isRenderTG     bool
-	tgChatID       int64
+	rendererTG report_renderers.ReportRenderer[[]string]
+	isRenderTG bool
+	tgChatID   int64
 
 	officeID                int
 	suppliers               map[int]struct{} // supplier id -> struct{}
@@ -75,16 +76,25 @@ type FinanceDailyReporter struct {
 	expensesDaily           float64
 	timeLastRender          time.Time
 	isRender                bool
+	isCalendar              bool         // runs by the calendar schedule, so every run renders
+	output                  ReportOutput // replaces Telegram bot while backfilling
+	isReadOnly              bool         // the finance ledger is not written while the day is rendered on demand
+	alerts                  *alertNotifier
 
 	data map[int]*FinanceDailyReporterData
 }
 
+// financeDailyReporterState Checkpoint of the reporter in the cache store
+type financeDailyReporterState struct {
+	TimeLastRender time.Time `json:"time_last_render"`
+}
+
 func NewFinanceDailyReporter(config *config.Config, storage storage.Storage, service *services.Container, prompter prompters.FinanceDailyReporterPrompter) *FinanceDailyReporter {
 	expensesDaily := 0.0
 	if config.Logistic().Office().Expenses() != 0 && config.Logistic().Office().ExpensesPeriod() != 0 {
 		expensesDaily = config.Logistic().Office().Expenses() / float64(config.Logistic().Office().ExpensesPeriod())
 	}
-	return &FinanceDailyReporter{
+	r := &FinanceDailyReporter{
 		config:        config,
 		storage:       storage,
 		services:      service,
@@ -92,10 +102,9 @@ func NewFinanceDailyReporter(config *config.Config, storage storage.Storage, ser
 		reportRoute:   &reports.FinanceDailyRouteReport{},
 		reportGeneral: &reports.FinanceDailyGeneralReport{},
 
-		messageQueueTG: New[string](300),
-		rendererTG:     &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
-		isRenderTG:     config.Reports().FinanceDaily().IsRenderTelegramBot(),
-		tgChatID:       config.Telegram().FinanceDaily().ChatID(),
+		rendererTG: &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
+		isRenderTG: config.Reports().FinanceDaily().IsRenderTelegramBot(),
+		tgChatID:   config.Telegram().FinanceDaily().ChatID(),
 
 		officeID:          config.Logistic().Office().ID(),
 		suppliers:         config.Logistic().Office().SuppliersMap(),
@@ -110,30 +119,35 @@ func NewFinanceDailyReporter(config *config.Config, storage storage.Storage, ser
 		expensesDaily:     expensesDaily,
 		dayOffset:         config.Reports().FinanceDaily().DayOffset(),
 		isRender:          config.Reports().FinanceDaily().RenderAtStart(),
+		isCalendar:        config.Reports().FinanceDaily().Schedule().IsCalendar(),
+		alerts:            newAlertNotifier(service, alerts.SourceFinanceDaily, config.Telegram().Alerts().ChatID()),
 
 		data: map[int]*FinanceDailyReporterData{},
 	}
+	r.restoreState()
+	return r
 }
 
 func (r *FinanceDailyReporter) Run(ctx context.Context) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "FinanceDailyReporter.Run()", "task was preliminarily completed")
 	}
+	defer r.saveState()
 
 	now := time.Now()
-	r.prompter.PromptStart(time.Date(now.Year(), now.Month(), now.Day()+r.dayOffset, 0, 0, 0, 0, time.UTC))
-
-	err := r.sendRemainingMessagesTelegramBot(ctx)
-	if err != nil {
-		r.prompter.PromptError("Failed send remains messages to Telegram bot")
-		return errors.Wrap(err, "FinanceDailyReporter.Run()", "failed sending remaining messages to Telegram bot")
-	}
+	timeStart := time.Date(now.Year(), now.Month(), now.Day()+r.dayOffset, 0, 0, 0, 0, time.UTC)
+	timeEnd := time.Date(now.Year(), now.Month(), now.Day()+r.dayOffset, 23, 59, 59, 999999999, time.UTC)
+	r.prompter.PromptStart(timeStart)
 
 	// isRender is true if it was originally set this way in the configuration or if the day has changed since the function was last run
 	if !r.timeLastRender.IsZero() && r.timeLastRender.Day() != now.Day() {
 		r.isRender = true
 	}
 
+	if r.isCalendar {
+		r.isRender = true
+	}
+
 	r.timeLastRender = now
 
 	if !r.isRender {
@@ -142,7 +156,7 @@ func (r *FinanceDailyReporter) Run(ctx context.Context) error {
 	}
 	r.isRender = false
 
-	err = r.processWaySheets(ctx)
+	err := r.processWaySheets(ctx, timeStart, timeEnd)
 	if err != nil {
 		return errors.Wrap(err, "FinanceDailyReporter.Run()", "failed processing way sheets")
 	}
@@ -152,20 +166,102 @@ func (r *FinanceDailyReporter) Run(ctx context.Context) error {
 		return errors.Wrap(err, "FinanceDailyReporter.Run()", "failed processing reports")
 	}
 
+	// Alerts are auxiliary, so their failure must not break the report
+	if err = r.processAlerts(ctx, now); err != nil {
+		r.prompter.PromptError("Failed process alerts")
+		logger.Logf(logger.ERROR, "FinanceDailyReporter.Run()", "failed processing alerts: %v", err)
+	}
+
 	r.prompter.PromptFinish(time.Since(now))
 	return nil
 }
 
-func (r *FinanceDailyReporter) processWaySheets(ctx context.Context) error {
+func (r *FinanceDailyReporter) processAlerts(ctx context.Context, now time.Time) error {
+	if !r.alerts.isEnabled() {
+		return nil
+	}
+
+	samples := make([]*alerts.Sample, 0, len(r.data))
+	for routeID, data := range r.data {
+		if data == nil {
+			continue
+		}
+		sample := &alerts.Sample{RouteID: routeID, Values: map[string]float64{
+			alerts.MetricFlights:         float64(data.Flights),
+			alerts.MetricFlightsOpened:   float64(data.FlightsOpened),
+			alerts.MetricBarcodesShipped: float64(data.BarcodesShipped),
+			alerts.MetricTareReturned:    float64(data.TareReturned),
+			alerts.MetricIncome:          data.Income,
+			alerts.MetricFine:            data.Fine,
+			alerts.MetricMargin:          data.Margin,
+		}}
+		// there are no closed flights yet, so the averages are unknown
+		if data.Flights > data.FlightsOpened {
+			sample.Values[alerts.MetricBarcodesAverage] = data.BarcodesAverage
+			if data.BarcodesStandard != 0 {
+				sample.Values[alerts.MetricBarcodesDeviationPercent] = data.BarcodesDeviationPercent
+			}
+		}
+		samples = append(samples, sample)
+	}
+
+	events, err := r.alerts.process(ctx, now, samples)
+	for _, event := range events {
+		r.prompter.PromptAlert(event.Rule, event.RouteID, event.IsResolved)
+		logger.Logf(logger.WARN, "FinanceDailyReporter.processAlerts()", "alert %s of route %d, resolved: %t", event.Rule, event.RouteID, event.IsResolved)
+	}
+	if err != nil {
+		return errors.Wrap(err, "FinanceDailyReporter.processAlerts()", "")
+	}
+	return nil
+}
+
+// Backfill Recomputes reports for every UTC day in [from, to] with the current configuration and sends them to output.
+// Only the way sheets missing in the finance ledger are added to it
+func (r *FinanceDailyReporter) Backfill(ctx context.Context, from, to time.Time, output ReportOutput) error {
+	if output == nil {
+		return errors.New("FinanceDailyReporter.Backfill()", "output is nil")
+	}
+
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(from) {
+		return errors.Newf("FinanceDailyReporter.Backfill()", "invalid period %s - %s", from.Format(time.DateOnly), to.Format(time.DateOnly))
+	}
+
+	r.output = output
+	defer func() { r.output = nil }()
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if ctx.Err() != nil {
+			return errors.Wrap(ctx.Err(), "FinanceDailyReporter.Backfill()", "task was preliminarily completed")
+		}
+
+		now := time.Now()
+		r.prompter.PromptStart(day)
+
+		err := r.processWaySheets(ctx, day, day.Add(24*time.Hour-time.Nanosecond))
+		if err != nil {
+			return errors.Wrapf(err, "FinanceDailyReporter.Backfill()", "failed processing way sheets for %s", day.Format(time.DateOnly))
+		}
+
+		err = r.processReports(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "FinanceDailyReporter.Backfill()", "failed processing reports for %s", day.Format(time.DateOnly))
+		}
+
+		r.prompter.PromptFinish(time.Since(now))
+	}
+
+	return nil
+}
+
+func (r *FinanceDailyReporter) processWaySheets(ctx context.Context, timeStart, timeEnd time.Time) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "FinanceDailyReporter.processWaySheets()", "task was preliminarily completed")
 	}
 
-	logger.Log(logger.INFO, "FinanceDailyReporter.processWaySheets()", "start process way sheets")
-
-	now := time.Now()
-	timeStart := time.Date(now.Year(), now.Month(), now.Day()+r.dayOffset, 0, 0, 0, 0, time.UTC)
-	timeEnd := time.Date(now.Year(), now.Month(), now.Day()+r.dayOffset, 23, 59, 59, 999999999, time.UTC)
+	logger.Logf(logger.INFO, "FinanceDailyReporter.processWaySheets()", "start process way sheets %s", timeStart.Format(time.DateOnly))
 
 	waySheets, err := r.loadWaySheets(ctx, timeStart, timeEnd)
 	if err != nil {
@@ -276,6 +372,29 @@ func (r *FinanceDailyReporter) processWaySheets(ctx context.Context) error {
 		data.PercentTax = r.percentTax
 		data.Margin += totalPriceSubFine - (salaryRate + defect + tax)
 		data.WaySheetIDs = append(data.WaySheetIDs, waySheet.WaySheetID)
+
+		// the cached rate of the route is the salary of its first way sheet if the rate is in percentages
+		entrySalaryRate := salaryRate
+		if _, ok := r.salaryRate[routeID]; !ok {
+			if v, ok := r.salaryRatePercent[routeID]; ok {
+				entrySalaryRate = waySheet.TotalPrice * (v / 100)
+			}
+		}
+
+		entry := history.NewFinanceEntry(waySheet, routeID, supplierID)
+		entry.SalaryRate = entrySalaryRate
+		entry.Defect = defect
+		entry.Tax = tax
+		entry.Margin = totalPriceSubFine - (entrySalaryRate + defect + tax)
+		entry.ConfigSalaryRate = r.salaryRate[routeID]
+		entry.ConfigSalaryRatePercent = r.salaryRatePercent[routeID]
+		entry.ConfigPercentDefect = r.percentDefect
+		entry.ConfigPercentTax = r.percentTax
+		entry.Source = history.FinanceSourceDaily
+		if err = r.saveLedger(entry); err != nil {
+			r.prompter.PromptError(fmt.Sprintf("Failed save finance ledger for way sheet %s", waySheet.WaySheetID))
+			logger.Logf(logger.ERROR, "FinanceDailyReporter.processWaySheets()", "failed save finance ledger for way sheet %s: %v", waySheet.WaySheetID, err)
+		}
 	}
 
 	r.prompter.PromptCountWaySheet(len(waySheets), totalClosedFlights, totalOpenedFlights)
@@ -283,14 +402,54 @@ func (r *FinanceDailyReporter) processWaySheets(ctx context.Context) error {
 	return nil
 }
 
+// restoreState The report of the restored day has already been rendered, so the render at start is skipped and the next render happens when the day changes
+func (r *FinanceDailyReporter) restoreState() {
+	state := &financeDailyReporterState{}
+	ok, err := loadCheckpoint(r.storage, storage.CacheFinanceDailyReporter, state)
+	if err != nil {
+		logger.Logf(logger.WARN, "FinanceDailyReporter.restoreState()", "failed restore state, starting from scratch: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	if !state.TimeLastRender.IsZero() {
+		r.timeLastRender = state.TimeLastRender
+		r.isRender = false
+	}
+	logger.Logf(logger.INFO, "FinanceDailyReporter.restoreState()", "restore state: last render %s", r.timeLastRender.Format(time.DateTime))
+}
+
+func (r *FinanceDailyReporter) saveState() {
+	err := saveCheckpoint(r.config, r.storage, storage.CacheFinanceDailyReporter, &financeDailyReporterState{
+		TimeLastRender: r.timeLastRender,
+	})
+	if err != nil {
+		logger.Logf(logger.ERROR, "FinanceDailyReporter.saveState()", "failed save state: %v", err)
+	}
+}
+
+// saveLedger The backfill only adds the missing entries, the existing ones keep the rates in force when they were recorded
+func (r *FinanceDailyReporter) saveLedger(entry *history.FinanceEntry) error {
+	if r.services.FinanceLedger == nil || r.isReadOnly {
+		return nil
+	}
+	if r.output != nil {
+		if _, err := r.services.FinanceLedger.Insert(entry); err != nil {
+			return errors.Wrapf(err, "FinanceDailyReporter.saveLedger()", "failed insert way sheet %s", entry.WaySheetID)
+		}
+		return nil
+	}
+	if err := r.services.FinanceLedger.Upsert(entry); err != nil {
+		return errors.Wrapf(err, "FinanceDailyReporter.saveLedger()", "failed upsert way sheet %s", entry.WaySheetID)
+	}
+	return nil
+}
+
 func (r *FinanceDailyReporter) processReports(ctx context.Context) error {
 	logger.Log(logger.INFO, "FinanceDailyReporter.processReports()", "start process reports")
 
-	var dateStart, dateEnd time.Time
-	var flights, flightsOpened, barcodesShipped, tareShipped, tare, tareReturned int
-	var income, incomeReturn, fine, salaryRate, extendedSalaryRate, tax, defect, margin float64
-	var openedWaySheets []string
-
 	for routeID, data := range r.data {
 		if ctx.Err() != nil {
 			return errors.Wrap(ctx.Err(), "FinanceDailyReporter.processReports()", "task was preliminarily completed")
@@ -301,29 +460,8 @@ func (r *FinanceDailyReporter) processReports(ctx context.Context) error {
 			continue
 		}
 
-		dateStart = data.DateStart
-		dateEnd = data.DateEnd
-		flights += data.Flights
-		flightsOpened += data.FlightsOpened
-		barcodesShipped += data.BarcodesShipped
-		tare += data.Tare
-		tareShipped += data.TareShipped
-		tareReturned += data.TareReturned
-		income += data.Income
-		incomeReturn += data.IncomeReturn
-		fine += data.Fine
-		salaryRate += data.TotalSalaryRate
-		extendedSalaryRate += data.ExtendedSalaryRate
-		tax += data.Tax
-		defect += data.Defect
-		margin += data.Margin
-
-		if len(data.OpenedWaySheetIDs) > 0 {
-			openedWaySheets = append(openedWaySheets, data.OpenedWaySheetIDs...)
-		}
-
 		renderData, err := r.renderRouteReport(&reports.FinanceDailyRouteReportData{
-			Date:                     dateStart,
+			Date:                     data.DateStart,
 			RouteID:                  routeID,
 			Parking:                  data.Parking,
 			Flights:                  data.Flights,
@@ -364,10 +502,78 @@ func (r *FinanceDailyReporter) processReports(ctx context.Context) error {
 			logger.Logf(logger.INFO, "FinanceDailyReporter.processReports()", "send report for route id %d", routeID)
 		}
 
-		time.Sleep(3 * time.Second)
+		if r.output == nil {
+			time.Sleep(3 * time.Second)
+		}
+	}
+
+	renderData, err := r.renderGeneralReport(r.generalReportData())
+	if err != nil {
+		return errors.Wrap(err, "FinanceDailyReporter.processReports()", "failed render general report")
+	}
+
+	err = r.sendReport(ctx, renderData)
+	if err != nil {
+		return errors.Wrap(err, "FinanceDailyReporter.processReports()", "failed send general report")
+	}
+
+	return nil
+}
+
+// RenderDay Computes the general report of the UTC day without sending it and without writing the finance ledger
+func (r *FinanceDailyReporter) RenderDay(ctx context.Context, date time.Time) (*reports.ReportData, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	r.isReadOnly = true
+	defer func() { r.isReadOnly = false }()
+
+	err := r.processWaySheets(ctx, day, day.Add(24*time.Hour-time.Nanosecond))
+	if err != nil {
+		return nil, errors.Wrapf(err, "FinanceDailyReporter.RenderDay()", "failed processing way sheets for %s", day.Format(time.DateOnly))
+	}
+
+	report, err := r.renderGeneralReport(r.generalReportData())
+	if err != nil {
+		return nil, errors.Wrap(err, "FinanceDailyReporter.RenderDay()", "")
+	}
+	return report, nil
+}
+
+// generalReportData Sums the processed routes data into the general report data
+func (r *FinanceDailyReporter) generalReportData() *reports.FinanceDailyGeneralReportData {
+	var dateStart, dateEnd time.Time
+	var flights, flightsOpened, barcodesShipped, tareShipped, tare, tareReturned int
+	var income, incomeReturn, fine, salaryRate, extendedSalaryRate, tax, defect, margin float64
+	var openedWaySheets []string
+
+	for _, data := range r.data {
+		if data == nil {
+			continue
+		}
+
+		dateStart = data.DateStart
+		dateEnd = data.DateEnd
+		flights += data.Flights
+		flightsOpened += data.FlightsOpened
+		barcodesShipped += data.BarcodesShipped
+		tare += data.Tare
+		tareShipped += data.TareShipped
+		tareReturned += data.TareReturned
+		income += data.Income
+		incomeReturn += data.IncomeReturn
+		fine += data.Fine
+		salaryRate += data.TotalSalaryRate
+		extendedSalaryRate += data.ExtendedSalaryRate
+		tax += data.Tax
+		defect += data.Defect
+		margin += data.Margin
+
+		if len(data.OpenedWaySheetIDs) > 0 {
+			openedWaySheets = append(openedWaySheets, data.OpenedWaySheetIDs...)
+		}
 	}
 
-	renderData, err := r.renderGeneralReport(&reports.FinanceDailyGeneralReportData{
+	return &reports.FinanceDailyGeneralReportData{
 		DateStart:          dateStart,
 		DateEnd:            dateEnd,
 		Flights:            flights,
@@ -390,17 +596,7 @@ func (r *FinanceDailyReporter) processReports(ctx context.Context) error {
 		Expenses:           r.expensesDaily,
 		TotalMargin:        margin - r.expensesDaily,
 		OpenedWaySheets:    openedWaySheets,
-	})
-	if err != nil {
-		return errors.Wrap(err, "FinanceDailyReporter.processReports()", "failed render general report")
-	}
-
-	err = r.sendReport(ctx, renderData)
-	if err != nil {
-		return errors.Wrap(err, "FinanceDailyReporter.processReports()", "failed send general report")
 	}
-
-	return nil
 }
 
 func (r *FinanceDailyReporter) isValidSupplier(supplierID int) bool {
@@ -531,23 +727,20 @@ func (r *FinanceDailyReporter) sendReport(ctx context.Context, data *reports.Rep
 		return errors.Wrap(ctx.Err(), "FinanceDailyReporter.sendReport()", "task was preliminarily completed")
 	}
 
+	if r.output != nil {
+		if err := r.output.Send(ctx, data); err != nil {
+			return errors.Wrap(err, "FinanceDailyReporter.sendReport()", "failed send report to output")
+		}
+		return nil
+	}
+
 	if r.isRenderTG {
 		messages, err := r.rendererTG.Render(data)
 		if err != nil {
 			r.prompter.PromptError("failed to render report Telegram Bot")
 			logger.Logf(logger.ERROR, "FinanceDailyReporter.sendReport()", "failed render report for Telegram Bot: %v", err)
 		} else {
-			if len(messages) == 0 {
-				return nil
-			}
-
-			for _, message := range messages {
-				if message != "" {
-					r.messageQueueTG.Push(message)
-				}
-			}
-
-			if err = r.sendTelegramBot(ctx); err != nil {
+			if err = enqueueTelegramBot(r.services, "FinanceDailyReporter", r.tgChatID, messages, 0); err != nil {
 				r.prompter.PromptError("failed to send report Telegram Bot")
 				logger.Logf(logger.ERROR, "FinanceDailyReporter.sendReport()", "failed send report to Telegram Bot: %v", err)
 			}
@@ -556,35 +749,3 @@ func (r *FinanceDailyReporter) sendReport(ctx context.Context, data *reports.Rep
 
 	return nil
 }
-
-func (r *FinanceDailyReporter) sendTelegramBot(ctx context.Context) error {
-	for r.messageQueueTG.Len() > 0 {
-		err := retryAction(ctx, "FinanceDailyReporter.sendTelegramBot", 3, 1*time.Second, func() error {
-			message, ok := r.messageQueueTG.Peek()
-			if !ok {
-				return errors.New("FinanceDailyReporter.sendTelegramBot()", "failed to get message from telegram message queue")
-			}
-			return r.services.TelegramBotService.SendMessage(r.tgChatID, message, "HTML")
-		})
-		if err != nil {
-			return errors.Wrapf(err, "FinanceDailyReporter.sendTelegramBot()", "failed send data to chat %d", r.tgChatID)
-		}
-		r.messageQueueTG.Pop()
-	}
-
-	return nil
-}
-
-func (r *FinanceDailyReporter) sendRemainingMessagesTelegramBot(ctx context.Context) error {
-	if r.messageQueueTG.Len() <= 0 {
-		return nil
-	}
-
-	err := r.sendTelegramBot(ctx)
-	if err != nil {
-		return errors.Wrap(err, "FinanceDailyReporter.sendRemainingMessagesTelegramBot)", "failed sending remains messages to Telegram bot")
-	}
-	logger.Log(logger.INFO, "FinanceDailyReporter.sendRemainingMessagesTelegramBot()", "send remains messages to Telegram bot")
-
-	return nil
-}
@@ -0,0 +1,194 @@
+package reporters
+
+import (
+	"context"
+	"testing"
+	"time"
+	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
+	"wb_logistic_assistant/internal/history"
+	"wb_logistic_assistant/internal/models"
+	"wb_logistic_assistant/internal/prompters"
+	"wb_logistic_assistant/internal/reports"
+	"wb_logistic_assistant/internal/services"
+)
+
+// waySheetsStub Serves the way sheets of the day, the other methods of the service are not called by the backfill
+type waySheetsStub struct {
+	services.WBLogisticService
+	waySheets []*wb_models.WaySheet
+}
+
+func (s *waySheetsStub) GetWaySheets(context.Context, *models.WBLogisticGetWaySheetsParamsRequest) (*wb_models.WaySheetsPage, error) {
+	return &wb_models.WaySheetsPage{WaySheets: s.waySheets, TotalWaySheets: len(s.waySheets), Page: 1, Pages: 1}, nil
+}
+
+func (s *waySheetsStub) GetWaySheetInfo(_ context.Context, id int) (*wb_models.WaySheetInfo, error) {
+	return &wb_models.WaySheetInfo{}, nil
+}
+
+type discardOutput struct {
+	sent int
+}
+
+func (o *discardOutput) Send(context.Context, *reports.ReportData) error {
+	o.sent++
+	return nil
+}
+
+func (o *discardOutput) Close() error {
+	return nil
+}
+
+func TestFinanceDailyBackfillKeepsLedgerEntries(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	waySheet := func(id string) *wb_models.WaySheet {
+		return &wb_models.WaySheet{
+			WaySheetID:    id,
+			OpenDt:        day.Add(8 * time.Hour),
+			CloseDt:       day.Add(12 * time.Hour),
+			SupplierID:    "300",
+			RouteCarID:    "200",
+			CountBarcodes: "100",
+			TotalPrice:    5000,
+		}
+	}
+
+	dir := t.TempDir()
+	ledger, err := history.NewFileFinanceLedger(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := history.NewFinanceEntry(waySheet("500"), 200, 300)
+	recorded.SalaryRate = 1000
+	recorded.ConfigSalaryRate = 1000
+	recorded.Margin = 4000
+	recorded.Source = history.FinanceSourceDaily
+	if err = ledger.Upsert(recorded); err != nil {
+		t.Fatal(err)
+	}
+
+	// the rate of the route has changed since the entry was recorded
+	r := &FinanceDailyReporter{
+		services: &services.Container{
+			WBLogisticService: &waySheetsStub{waySheets: []*wb_models.WaySheet{waySheet("500"), waySheet("501")}},
+			FinanceLedger:     ledger,
+		},
+		prompter:      &prompters.CLIReporterFinanceDailyPrompter{},
+		reportRoute:   &reports.FinanceDailyRouteReport{},
+		reportGeneral: &reports.FinanceDailyGeneralReport{},
+		suppliers:     map[int]struct{}{300: {}},
+		salaryRate:    map[int]float64{200: 2000},
+		data:          map[int]*FinanceDailyReporterData{},
+	}
+	output := &discardOutput{}
+	if err = r.Backfill(context.Background(), day, day, output); err != nil {
+		t.Fatal(err)
+	}
+	if output.sent == 0 {
+		t.Fatal("backfill sent no reports")
+	}
+
+	// reopened to check the file and not only the memory
+	ledger, err = history.NewFileFinanceLedger(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry := ledger.Get("500"); entry == nil || entry.ConfigSalaryRate != 1000 || entry.SalaryRate != 1000 {
+		t.Fatalf("existing entry is overwritten by the backfill: %+v", entry)
+	}
+	if entry := ledger.Get("501"); entry == nil || entry.ConfigSalaryRate != 2000 {
+		t.Fatalf("missing entry is not added by the backfill: %+v", entry)
+	}
+}
+
+func TestFinanceDailyLedgerPercentSalaryRate(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	waySheet := func(id string, totalPrice float64) *wb_models.WaySheet {
+		return &wb_models.WaySheet{
+			WaySheetID:    id,
+			OpenDt:        day.Add(8 * time.Hour),
+			CloseDt:       day.Add(12 * time.Hour),
+			SupplierID:    "300",
+			RouteCarID:    "200",
+			CountBarcodes: "100",
+			TotalPrice:    totalPrice,
+		}
+	}
+
+	ledger, err := history.NewFileFinanceLedger(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &FinanceDailyReporter{
+		services: &services.Container{
+			WBLogisticService: &waySheetsStub{waySheets: []*wb_models.WaySheet{waySheet("500", 5000), waySheet("501", 8000)}},
+			FinanceLedger:     ledger,
+		},
+		prompter:          &prompters.CLIReporterFinanceDailyPrompter{},
+		reportRoute:       &reports.FinanceDailyRouteReport{},
+		reportGeneral:     &reports.FinanceDailyGeneralReport{},
+		suppliers:         map[int]struct{}{300: {}},
+		salaryRatePercent: map[int]float64{200: 10},
+		data:              map[int]*FinanceDailyReporterData{},
+	}
+	if err = r.Backfill(context.Background(), day, day, &discardOutput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the salary of every way sheet is its own percent and not the one of the first way sheet of the route
+	for id, want := range map[string]float64{"500": 500, "501": 800} {
+		entry := ledger.Get(id)
+		if entry == nil || entry.SalaryRate != want || entry.Margin != entry.TotalPrice-want {
+			t.Fatalf("way sheet %s: entry %+v, want salary rate %v", id, entry, want)
+		}
+	}
+}
+
+func TestFinanceDailyRenderDayKeepsLedger(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	waySheet := func(id string) *wb_models.WaySheet {
+		return &wb_models.WaySheet{
+			WaySheetID:    id,
+			OpenDt:        day.Add(8 * time.Hour),
+			CloseDt:       day.Add(12 * time.Hour),
+			SupplierID:    "300",
+			RouteCarID:    "200",
+			CountBarcodes: "100",
+			TotalPrice:    5000,
+		}
+	}
+
+	ledger, err := history.NewFileFinanceLedger(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := history.NewFinanceEntry(waySheet("500"), 200, 300)
+	recorded.SalaryRate = 1000
+	recorded.ConfigSalaryRate = 1000
+	if err = ledger.Upsert(recorded); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &FinanceDailyReporter{
+		services: &services.Container{
+			WBLogisticService: &waySheetsStub{waySheets: []*wb_models.WaySheet{waySheet("500"), waySheet("501")}},
+			FinanceLedger:     ledger,
+		},
+		prompter:      &prompters.CLIReporterFinanceDailyPrompter{},
+		reportRoute:   &reports.FinanceDailyRouteReport{},
+		reportGeneral: &reports.FinanceDailyGeneralReport{},
+		suppliers:     map[int]struct{}{300: {}},
+		salaryRate:    map[int]float64{200: 2000},
+		data:          map[int]*FinanceDailyReporterData{},
+	}
+	if _, err = r.RenderDay(context.Background(), day); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry := ledger.Get("500"); entry == nil || entry.ConfigSalaryRate != 1000 || entry.SalaryRate != 1000 {
+		t.Fatalf("existing entry is overwritten by the render: %+v", entry)
+	}
+	if entry := ledger.Get("501"); entry != nil {
+		t.Fatalf("entry is added by the render: %+v", entry)
+	}
+}
@@ -0,0 +1,406 @@
+package reporters
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
+
+	"wb_logistic_assistant/internal/config"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/prompters"
+	"wb_logistic_assistant/internal/report_renderers"
+	"wb_logistic_assistant/internal/reports"
+	"wb_logistic_assistant/internal/services"
+	"wb_logistic_assistant/internal/storage"
+)
+
+const financePeriodTopRoutes = 3
+
+// FinancePeriodReporter Finance roll-up over a week or a month grouped by routes and drivers
+type FinancePeriodReporter struct {
+	config   *config.Config
+	storage  storage.Storage
+	services *services.Container
+	report   *reports.FinancePeriodReport
+	prompter prompters.FinancePeriodReporterPrompter
+	period   ReportPeriod
+	title    string
+
+	rendererTG report_renderers.ReportRenderer[[]string]
+	isRenderTG bool
+	tgChatID   int64
+
+	rendererGS    report_renderers.ReportRenderer[[][]interface{}]
+	isRenderGS    bool
+	spreadsheetID string
+	sheetName     string
+	sheetPosition string
+
+	officeID          int
+	suppliers         map[int]struct{} // supplier id -> struct{}
+	skipRoutes        map[int]struct{} // route id -> struct{}
+	salaryRatePercent map[int]float64  // route id -> salary rate
+	salaryRate        map[int]float64  // route id -> salary rate
+	percentTax        float64
+	taxRate           float64
+	percentDefect     float64
+	defectRate        float64
+	expensesDaily     float64
+	periodOffset      int
+	timeLastRender    time.Time
+	isRender          bool
+	isCalendar        bool // runs by the calendar schedule, so every run renders
+}
+
+func NewFinanceWeeklyReporter(config *config.Config, storage storage.Storage, service *services.Container, prompter prompters.FinancePeriodReporterPrompter) *FinancePeriodReporter {
+	r := newFinancePeriodReporter(config, storage, service, prompter, config.Reports().FinanceWeekly())
+	r.period = ReportPeriodWeek
+	r.title = "НЕДЕЛЬНЫЕ РЕЗУЛЬТАТЫ"
+	r.tgChatID = config.Telegram().FinanceWeekly().ChatID()
+	r.spreadsheetID = config.GoogleSheets().ReportSheets().FinanceWeekly().SpreadsheetID()
+	r.sheetName = config.GoogleSheets().ReportSheets().FinanceWeekly().SheetName()
+	return r
+}
+
+func NewFinanceMonthlyReporter(config *config.Config, storage storage.Storage, service *services.Container, prompter prompters.FinancePeriodReporterPrompter) *FinancePeriodReporter {
+	r := newFinancePeriodReporter(config, storage, service, prompter, config.Reports().FinanceMonthly())
+	r.period = ReportPeriodMonth
+	r.title = "МЕСЯЧНЫЕ РЕЗУЛЬТАТЫ"
+	r.tgChatID = config.Telegram().FinanceMonthly().ChatID()
+	r.spreadsheetID = config.GoogleSheets().ReportSheets().FinanceMonthly().SpreadsheetID()
+	r.sheetName = config.GoogleSheets().ReportSheets().FinanceMonthly().SheetName()
+	return r
+}
+
+func newFinancePeriodReporter(config *config.Config, storage storage.Storage, service *services.Container, prompter prompters.FinancePeriodReporterPrompter, reportConfig *config.ReportsFinancePeriod) *FinancePeriodReporter {
+	expensesDaily := 0.0
+	if config.Logistic().Office().Expenses() != 0 && config.Logistic().Office().ExpensesPeriod() != 0 {
+		expensesDaily = config.Logistic().Office().Expenses() / float64(config.Logistic().Office().ExpensesPeriod())
+	}
+	return &FinancePeriodReporter{
+		config:   config,
+		storage:  storage,
+		services: service,
+		prompter: prompter,
+		report:   &reports.FinancePeriodReport{},
+
+		rendererTG: &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
+		isRenderTG: reportConfig.IsRenderTelegramBot(),
+
+		rendererGS:    &report_renderers.GoogleSheetsRenderer{},
+		isRenderGS:    reportConfig.IsRenderGoogleSheets(),
+		sheetPosition: "A1",
+
+		officeID:          config.Logistic().Office().ID(),
+		suppliers:         config.Logistic().Office().SuppliersMap(),
+		skipRoutes:        config.Logistic().Office().SkipRoutesMap(),
+		salaryRatePercent: config.Logistic().Office().SalaryRatePercent(),
+		salaryRate:        config.Logistic().Office().SalaryRate(),
+		percentTax:        config.Logistic().Office().PercentTax(),
+		taxRate:           config.Logistic().Office().PercentTax() / 100,
+		percentDefect:     config.Logistic().Office().PercentDefect(),
+		defectRate:        config.Logistic().Office().PercentDefect() / 100,
+		expensesDaily:     expensesDaily,
+		periodOffset:      reportConfig.PeriodOffset(),
+		isRender:          reportConfig.RenderAtStart(),
+		isCalendar:        reportConfig.Schedule().IsCalendar(),
+	}
+}
+
+func (r *FinancePeriodReporter) Run(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "FinancePeriodReporter.Run()", "task was preliminarily completed")
+	}
+
+	now := time.Now()
+	dateStart, dateEnd := r.period.Bounds(now, r.periodOffset)
+	r.prompter.PromptStart(dateStart, dateEnd)
+
+	// isRender is true if it was originally set this way in the configuration or if the period has changed since the function was last run
+	if !r.timeLastRender.IsZero() {
+		prevStart, _ := r.period.Bounds(r.timeLastRender, 0)
+		currentStart, _ := r.period.Bounds(now, 0)
+		if !prevStart.Equal(currentStart) {
+			r.isRender = true
+		}
+	}
+
+	if r.isCalendar {
+		r.isRender = true
+	}
+
+	r.timeLastRender = now
+
+	if !r.isRender {
+		r.prompter.PromptFinish(time.Since(now))
+		return nil
+	}
+	r.isRender = false
+
+	if err := r.RunPeriod(ctx, dateStart, dateEnd); err != nil {
+		return errors.Wrap(err, "FinancePeriodReporter.Run()", "")
+	}
+
+	r.prompter.PromptFinish(time.Since(now))
+	return nil
+}
+
+// RunPeriod Builds and sends the report for an arbitrary period
+func (r *FinancePeriodReporter) RunPeriod(ctx context.Context, dateStart, dateEnd time.Time) error {
+	if dateEnd.Before(dateStart) {
+		return errors.Newf("FinancePeriodReporter.RunPeriod()", "invalid period %s - %s", dateStart, dateEnd)
+	}
+
+	data, err := r.processWaySheets(ctx, dateStart, dateEnd)
+	if err != nil {
+		return errors.Wrap(err, "FinancePeriodReporter.RunPeriod()", "failed processing way sheets")
+	}
+
+	report, err := r.report.Render(data)
+	if err != nil {
+		r.prompter.PromptError("Failed render report")
+		return errors.Wrap(err, "FinancePeriodReporter.RunPeriod()", "failed render report")
+	}
+
+	if err = r.sendReport(ctx, report); err != nil {
+		return errors.Wrap(err, "FinancePeriodReporter.RunPeriod()", "failed send report")
+	}
+
+	return nil
+}
+
+func (r *FinancePeriodReporter) processWaySheets(ctx context.Context, dateStart, dateEnd time.Time) (*reports.FinancePeriodReportData, error) {
+	logger.Logf(logger.INFO, "FinancePeriodReporter.processWaySheets()", "start process way sheets %s - %s", dateStart.Format(time.DateOnly), dateEnd.Format(time.DateOnly))
+
+	waySheets, err := r.loadWaySheets(ctx, dateStart, dateEnd)
+	if err != nil {
+		return nil, errors.Wrap(err, "FinancePeriodReporter.processWaySheets()", "failed load way sheets")
+	}
+
+	days := int(dateEnd.Sub(dateStart).Round(24*time.Hour) / (24 * time.Hour))
+	if days < 1 {
+		days = 1
+	}
+
+	data := &reports.FinancePeriodReportData{
+		Title:         r.title,
+		DateStart:     dateStart,
+		DateEnd:       dateEnd,
+		Days:          days,
+		PercentDefect: r.percentDefect,
+		PercentTax:    r.percentTax,
+		Expenses:      r.expensesDaily * float64(days),
+	}
+
+	routes := map[int]*reports.FinancePeriodGroupData{}     // route id -> data
+	drivers := map[string]*reports.FinancePeriodGroupData{} // driver id -> data
+	seen := map[string]struct{}{}                           // way sheet id -> struct{}, pages may overlap
+
+	for _, waySheet := range waySheets {
+		if ctx.Err() != nil {
+			return nil, errors.Wrap(ctx.Err(), "FinancePeriodReporter.processWaySheets()", "task was preliminarily completed")
+		}
+
+		if waySheet == nil || waySheet.OpenDt.Before(dateStart) || waySheet.OpenDt.After(dateEnd) {
+			continue
+		}
+		if _, ok := seen[waySheet.WaySheetID]; ok {
+			continue
+		}
+		seen[waySheet.WaySheetID] = struct{}{}
+
+		if !r.isValidSupplier(atoiSafe(waySheet.SupplierID)) {
+			continue
+		}
+
+		routeID := atoiSafe(waySheet.RouteCarID)
+		if _, ok := r.skipRoutes[routeID]; ok {
+			continue
+		}
+
+		route := routes[routeID]
+		if route == nil {
+			route = &reports.FinancePeriodGroupData{RouteID: routeID}
+			routes[routeID] = route
+		}
+
+		driverKey := waySheet.DriverID
+		if driverKey == "" {
+			driverKey = waySheet.DriverName
+		}
+		driver := drivers[driverKey]
+		if driver == nil {
+			driver = &reports.FinancePeriodGroupData{DriverName: waySheet.DriverName}
+			if driver.DriverName == "" {
+				driver.DriverName = driverKey
+			}
+			drivers[driverKey] = driver
+		}
+
+		data.Flights++
+		if waySheet.CloseDt.IsZero() {
+			data.FlightsOpened++
+			continue
+		}
+		route.Flights++
+		driver.Flights++
+
+		barcodes := atoiSafe(waySheet.CountBarcodes)
+		tare := atoiSafe(waySheet.CountBox)
+		shippedTare := atoiSafe(waySheet.CountArrivalBox)
+
+		salaryRate := 0.0
+		if v, ok := r.salaryRate[routeID]; ok {
+			salaryRate = v
+		} else if v, ok := r.salaryRatePercent[routeID]; ok {
+			salaryRate = waySheet.TotalPrice * (v / 100) // calculated if the rate is in percentages and not fixed
+		} else {
+			r.prompter.PromptError(fmt.Sprintf("There is no salary rate for route %d, way sheet %s", routeID, waySheet.WaySheetID))
+			logger.Logf(logger.ERROR, "FinancePeriodReporter.processWaySheets()", "there is no salary rate for route %d, way sheet %s", routeID, waySheet.WaySheetID)
+		}
+
+		totalPriceSubFine := waySheet.TotalPrice - waySheet.SumFine
+		defect := totalPriceSubFine * r.defectRate
+		tax := (totalPriceSubFine - defect) * r.taxRate
+		margin := totalPriceSubFine - (salaryRate + defect + tax)
+
+		data.BarcodesShipped += barcodes
+		data.Tare += tare
+		data.TareShipped += shippedTare
+		data.TareReturned += tare - shippedTare
+		data.Income += waySheet.TotalPrice
+		data.IncomeReturn += waySheet.SumReturn
+		data.Fine += waySheet.SumFine
+		data.SalaryRate += salaryRate
+		data.ExtendedSalaryRate += salaryRate + defect + tax
+		data.Defect += defect
+		data.Tax += tax
+		data.Margin += margin
+
+		for _, group := range []*reports.FinancePeriodGroupData{route, driver} {
+			group.BarcodesShipped += barcodes
+			group.Income += waySheet.TotalPrice
+			group.Fine += waySheet.SumFine
+			group.SalaryRate += salaryRate
+			group.Margin += margin
+		}
+	}
+
+	closedFlights := data.Flights - data.FlightsOpened
+	r.prompter.PromptCountWaySheet(len(seen), closedFlights, data.FlightsOpened)
+
+	data.TotalMargin = data.Margin - data.Expenses
+	if closedFlights > 0 {
+		data.AverageBarcodesPerFlight = float64(data.BarcodesShipped) / float64(closedFlights)
+		data.AverageIncomePerFlight = data.Income / float64(closedFlights)
+		data.AverageMarginPerFlight = data.Margin / float64(closedFlights)
+	}
+
+	data.Routes = sortFinancePeriodGroups(routes)
+	data.Drivers = sortFinancePeriodGroups(drivers)
+
+	closedRoutes := make([]*reports.FinancePeriodGroupData, 0, len(data.Routes))
+	for _, route := range data.Routes {
+		if route.Flights > 0 {
+			closedRoutes = append(closedRoutes, route)
+		}
+	}
+	// the worst routes are taken from the rest, so a route is not both the best and the worst one
+	best := min(financePeriodTopRoutes, len(closedRoutes))
+	worst := min(financePeriodTopRoutes, len(closedRoutes)-best)
+	data.BestRoutes = closedRoutes[:best]
+	for i := len(closedRoutes) - 1; i >= len(closedRoutes)-worst; i-- {
+		data.WorstRoutes = append(data.WorstRoutes, closedRoutes[i])
+	}
+
+	return data, nil
+}
+
+func (r *FinancePeriodReporter) isValidSupplier(supplierID int) bool {
+	if _, ok := r.suppliers[supplierID]; !ok {
+		return false
+	}
+	return true
+}
+
+// sortFinancePeriodGroups Returns groups sorted by margin descending with the margin per closed flight filled in
+func sortFinancePeriodGroups[K comparable](groups map[K]*reports.FinancePeriodGroupData) []*reports.FinancePeriodGroupData {
+	result := make([]*reports.FinancePeriodGroupData, 0, len(groups))
+	for _, group := range groups {
+		if group.Flights > 0 {
+			group.MarginPerFlight = group.Margin / float64(group.Flights)
+		}
+		result = append(result, group)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Margin != result[j].Margin {
+			return result[i].Margin > result[j].Margin
+		}
+		if result[i].RouteID != result[j].RouteID {
+			return result[i].RouteID < result[j].RouteID
+		}
+		return result[i].DriverName < result[j].DriverName
+	})
+	return result
+}
+
+func (r *FinancePeriodReporter) loadWaySheets(ctx context.Context, dOpen, dClose time.Time) ([]*wb_models.WaySheet, error) {
+	return loadWaySheetsPages(ctx, r.services.WBLogisticService, r.prompter, "FinancePeriodReporter", r.officeID, r.suppliers, dOpen, dClose)
+}
+
+func (r *FinancePeriodReporter) sendReport(ctx context.Context, report *reports.ReportData) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "FinancePeriodReporter.sendReport()", "task was preliminarily completed")
+	}
+
+	if r.isRenderGS {
+		data, err := r.rendererGS.Render(report)
+		if err != nil {
+			r.prompter.PromptError("Failed to render report Google Sheet")
+			logger.Logf(logger.ERROR, "FinancePeriodReporter.sendReport()", "failed render report for Google Sheets: %v", err)
+		} else {
+			if err = r.sendGoogleSheets(ctx, data); err != nil {
+				r.prompter.PromptError("Failed to send report Google Sheet")
+				logger.Logf(logger.ERROR, "FinancePeriodReporter.sendReport()", "failed send report to Google Sheets: %v", err)
+			} else {
+				r.prompter.PromptSendReport()
+				logger.Log(logger.INFO, "FinancePeriodReporter.sendReport()", "send report to Google Sheets")
+			}
+		}
+	}
+
+	if r.isRenderTG {
+		messages, err := r.rendererTG.Render(report)
+		if err != nil {
+			r.prompter.PromptError("failed to render report Telegram Bot")
+			logger.Logf(logger.ERROR, "FinancePeriodReporter.sendReport()", "failed render report for Telegram Bot: %v", err)
+		} else {
+			if err = enqueueTelegramBot(r.services, "FinancePeriodReporter", r.tgChatID, messages, 0); err != nil {
+				r.prompter.PromptError("failed to send report Telegram Bot")
+				logger.Logf(logger.ERROR, "FinancePeriodReporter.sendReport()", "failed send report to Telegram Bot: %v", err)
+			} else {
+				r.prompter.PromptSendReport()
+				logger.Log(logger.INFO, "FinancePeriodReporter.sendReport()", "send report to Telegram Bot")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *FinancePeriodReporter) sendGoogleSheets(ctx context.Context, data [][]interface{}) error {
+	err := retryAction(ctx, "FinancePeriodReporter.sendGoogleSheets", 3, 1*time.Second, func() error {
+		err := r.services.GoogleSheetsService.ClearValues(r.spreadsheetID, r.sheetName, "A:Z")
+		if err != nil {
+			return errors.Wrapf(err, "FinancePeriodReporter.sendGoogleSheets()", "failed clear sheet %s, page %s", r.spreadsheetID, r.sheetName)
+		}
+		return r.services.GoogleSheetsService.UpdateValues(r.spreadsheetID, r.sheetName, r.sheetPosition, data, false)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "FinancePeriodReporter.sendGoogleSheets()", "failed update sheet %s, page %s to position %s", r.spreadsheetID, r.sheetName, r.sheetPosition)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"wb_logistic_assistant/internal/config"
 	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/history"
 	"wb_logistic_assistant/internal/logger"
 	"wb_logistic_assistant/internal/prompters"
 	"wb_logistic_assistant/internal/report_renderers"
@@ -24,10 +25,9 @@ type FinanceRoutesReporter struct {
 	report   *reports.FinanceRoutesReport
 	prompter prompters.FinanceRoutesReporterPrompter
 
-	messageQueueTG *Queue[string]
-	rendererTG     report_renderers.ReportRenderer[[]string]
-	tgChatID       int64
-	isRenderTG     bool
+	rendererTG report_renderers.ReportRenderer[[]string]
+	tgChatID   int64
+	isRenderTG bool
 
 	officeID           int
 	suppliers          map[int]struct{} // supplier id -> struct{}
@@ -46,18 +46,23 @@ type FinanceRoutesReporter struct {
 	openedWaySheets map[string]*wb_models.WaySheet // way sheet id -> way sheet
 }
 
+// financeRoutesReporterState Checkpoint of the reporter in the cache store
+type financeRoutesReporterState struct {
+	OpenedWaySheets map[string]*wb_models.WaySheet `json:"opened_way_sheets"`
+	DelayTimes      map[string]time.Time           `json:"delay_times"`
+}
+
 func NewFinanceRoutesReporter(config *config.Config, storage storage.Storage, service *services.Container, prompter prompters.FinanceRoutesReporterPrompter) *FinanceRoutesReporter {
-	return &FinanceRoutesReporter{
+	r := &FinanceRoutesReporter{
 		config:   config,
 		storage:  storage,
 		services: service,
 		prompter: prompter,
 		report:   &reports.FinanceRoutesReport{},
 
-		messageQueueTG: New[string](300),
-		rendererTG:     &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
-		tgChatID:       config.Telegram().FinanceRoutes().ChatID(),
-		isRenderTG:     config.Reports().FinanceRoutes().IsRenderTelegramBot(),
+		rendererTG: &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
+		tgChatID:   config.Telegram().FinanceRoutes().ChatID(),
+		isRenderTG: config.Reports().FinanceRoutes().IsRenderTelegramBot(),
 
 		officeID:           config.Logistic().Office().ID(),
 		suppliers:          config.Logistic().Office().SuppliersMap(),
@@ -75,29 +80,26 @@ func NewFinanceRoutesReporter(config *config.Config, storage storage.Storage, se
 		delayTimeMap:    make(map[string]time.Time),
 		openedWaySheets: make(map[string]*wb_models.WaySheet),
 	}
+	r.restoreState()
+	return r
 }
 
 func (r *FinanceRoutesReporter) Run(ctx context.Context) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "FinanceRoutesReporter.Run()", "task was preliminarily completed")
 	}
+	defer r.saveState()
 
 	r.prompter.PromptStart()
 
-	err := r.sendRemainingMessagesTelegramBot(ctx)
-	if err != nil {
-		r.prompter.PromptError("Failed to send remaining messages to Telegram bot")
-		return errors.Wrap(err, "FinanceRoutesReporter.Run()", "failed sending remaining messages to Telegram bot")
-	}
-
 	now := time.Now()
 
-	if err = r.findOpenedWaySheets(ctx); err != nil {
+	if err := r.findOpenedWaySheets(ctx); err != nil {
 		r.prompter.PromptError("Failed finding opened way sheets")
 		return errors.Wrap(err, "FinanceRoutesReporter.Run()", "failed finding opened way sheets")
 	}
 
-	if err = r.processOpenedWaySheets(ctx); err != nil {
+	if err := r.processOpenedWaySheets(ctx); err != nil {
 		r.prompter.PromptError("Failed processing opened way sheets")
 		return errors.Wrap(err, "FinanceRoutesReporter.Run()", "failed processing opened way sheets")
 	}
@@ -231,6 +233,21 @@ func (r *FinanceRoutesReporter) processOpenedWaySheets(ctx context.Context) erro
 			mileage := atofSafe(info.PlanMileage)
 			incomeMileage := incomeTotal / mileage
 
+			entry := history.NewFinanceEntry(waySheet, routeID, atoiSafe(waySheet.SupplierID))
+			entry.SalaryRate = salaryRate
+			entry.Defect = defect
+			entry.Tax = tax
+			entry.Margin = margin
+			entry.ConfigSalaryRate = r.salaryRate[routeID]
+			entry.ConfigSalaryRatePercent = r.salaryRatePercent[routeID]
+			entry.ConfigPercentDefect = r.percentDefect
+			entry.ConfigPercentTax = r.percentTax
+			entry.Source = history.FinanceSourceRoutes
+			if err = r.saveLedger(entry); err != nil {
+				r.prompter.PromptError(fmt.Sprintf("Failed save finance ledger for way sheet %s", waySheet.WaySheetID))
+				logger.Logf(logger.ERROR, "FinanceRoutesReporter.processOpenedWaySheets()", "failed save finance ledger for way sheet %d: %v", waySheetID, err)
+			}
+
 			r.prompter.PromptCloseWaySheet(routeID, waySheet.WaySheetID, shipmentID)
 			logger.Logf(logger.INFO, "FinanceRoutesReporter.processOpenedWaySheets()", "way sheet %d is closed on route %d, shipment %s", waySheetID, routeID, shipmentID)
 			err = r.sendReport(ctx, &reports.FinanceRoutesReportData{
@@ -274,6 +291,48 @@ func (r *FinanceRoutesReporter) processOpenedWaySheets(ctx context.Context) erro
 	return nil
 }
 
+func (r *FinanceRoutesReporter) restoreState() {
+	state := &financeRoutesReporterState{}
+	ok, err := loadCheckpoint(r.storage, storage.CacheFinanceRoutesReporter, state)
+	if err != nil {
+		logger.Logf(logger.WARN, "FinanceRoutesReporter.restoreState()", "failed restore state, starting from scratch: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	for id, waySheet := range state.OpenedWaySheets {
+		if waySheet != nil {
+			r.openedWaySheets[id] = waySheet
+		}
+	}
+	for id, delay := range state.DelayTimes {
+		r.delayTimeMap[id] = delay
+	}
+	logger.Logf(logger.INFO, "FinanceRoutesReporter.restoreState()", "restore state: opened way sheets %d", len(r.openedWaySheets))
+}
+
+func (r *FinanceRoutesReporter) saveState() {
+	err := saveCheckpoint(r.config, r.storage, storage.CacheFinanceRoutesReporter, &financeRoutesReporterState{
+		OpenedWaySheets: r.openedWaySheets,
+		DelayTimes:      r.delayTimeMap,
+	})
+	if err != nil {
+		logger.Logf(logger.ERROR, "FinanceRoutesReporter.saveState()", "failed save state: %v", err)
+	}
+}
+
+func (r *FinanceRoutesReporter) saveLedger(entry *history.FinanceEntry) error {
+	if r.services.FinanceLedger == nil {
+		return nil
+	}
+	if err := r.services.FinanceLedger.Upsert(entry); err != nil {
+		return errors.Wrapf(err, "FinanceRoutesReporter.saveLedger()", "failed upsert way sheet %s", entry.WaySheetID)
+	}
+	return nil
+}
+
 func (r *FinanceRoutesReporter) isValidSupplier(supplierID int) bool {
 	if _, ok := r.suppliers[supplierID]; !ok {
 		return false
@@ -385,17 +444,7 @@ func (r *FinanceRoutesReporter) sendReport(ctx context.Context, reportData *repo
 			r.prompter.PromptError(fmt.Sprintf("Failed to render report for Telegram bot, route id: %d, way sheet id: %s", reportData.RouteID, reportData.WaySheetID))
 			logger.Logf(logger.ERROR, "FinanceRoutesReporter.sendReport()", "failed render report for Telegram bot, route id: %d shipment id: %s, way sheet id: %s: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
 		} else {
-			if len(messages) == 0 {
-				return nil
-			}
-
-			for _, message := range messages {
-				if message != "" {
-					r.messageQueueTG.Push(message)
-				}
-			}
-
-			if err = r.sendTelegramBot(ctx); err != nil {
+			if err = enqueueTelegramBot(r.services, "FinanceRoutesReporter", r.tgChatID, messages, r.tgSendMessageDelay); err != nil {
 				r.prompter.PromptError(fmt.Sprintf("Failed to send report Telegram Bot, route id: %d way sheet id: %s", reportData.RouteID, reportData.WaySheetID))
 				logger.Logf(logger.ERROR, "FinanceRoutesReporter.sendReport()", "failed send report to Telegram bot, route id: %d, shipment id: %s, way sheet id: %s: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
 			} else {
@@ -407,36 +456,3 @@ func (r *FinanceRoutesReporter) sendReport(ctx context.Context, reportData *repo
 
 	return nil
 }
-
-func (r *FinanceRoutesReporter) sendTelegramBot(ctx context.Context) error {
-	for r.messageQueueTG.Len() > 0 {
-		err := retryAction(ctx, "FinanceRoutesReporter.sendTelegramBot", 3, 1*time.Second, func() error {
-			message, ok := r.messageQueueTG.Peek()
-			if !ok {
-				return errors.New("FinanceRoutesReporter.sendTelegramBot()", "failed to get message from Telegram message queue")
-			}
-			return r.services.TelegramBotService.SendMessage(r.tgChatID, message, "HTML")
-		})
-		if err != nil {
-			return errors.Wrapf(err, "FinanceRoutesReporter.sendTelegramBot()", "failed send data to chat %d", r.tgChatID)
-		}
-		r.messageQueueTG.Pop()
-		time.Sleep(r.tgSendMessageDelay) // antispam
-	}
-
-	return nil
-}
-
-func (r *FinanceRoutesReporter) sendRemainingMessagesTelegramBot(ctx context.Context) error {
-	if r.messageQueueTG.Len() <= 0 {
-		return nil
-	}
-
-	err := r.sendTelegramBot(ctx)
-	if err != nil {
-		return errors.Wrap(err, "FinanceRoutesReporter.sendRemainingMessagesTelegramBot)", "failed sending remains messages to Telegram bot")
-	}
-	logger.Log(logger.INFO, "FinanceRoutesReporter.sendRemainingMessagesTelegramBot()", "send remains messages to Telegram bot")
-
-	return nil
-}
@@ -3,11 +3,16 @@ package reporters
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
+	"wb_logistic_assistant/internal/alerts"
 	"wb_logistic_assistant/internal/config"
 	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/history"
 	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/metrics"
 	"wb_logistic_assistant/internal/models"
 	"wb_logistic_assistant/internal/prompters"
 	"wb_logistic_assistant/internal/report_renderers"
@@ -39,6 +44,16 @@ type GeneralRoutesReporter struct {
 	rendererGS report_renderers.ReportRenderer[[][]interface{}]
 	isRenderGS bool
 
+	rendererTG report_renderers.ReportRenderer[[]string]
+	tgChatID   int64
+
+	reportRatingAlert    *reports.RatingAlertReport
+	ratingTracker        *ratingTracker // nil if rating alerts are disabled
+	ratingAlertThreshold float64
+	isRatingTrackerSeed  bool
+
+	alerts *alertNotifier
+
 	officeID   int
 	suppliers  map[int]struct{} // supplier id -> struct{}
 	skipRoutes map[int]struct{} // route id -> struct{}
@@ -62,6 +77,10 @@ type GeneralRoutesReporter struct {
 	reportDataList []*reports.GeneralRoutesReportData
 	reportData     map[int]*reports.GeneralRoutesReportData // report id -> ReportData
 	routeData      map[int]*generalRoutesRouteData          // report id -> RoutesData
+
+	snapshotMtx  sync.RWMutex
+	snapshotTime time.Time
+	snapshot     []*reports.GeneralRoutesReportData // copy of the last report data for concurrent readers
 }
 
 func NewGeneralRoutesReporter(config *config.Config, storage storage.Storage, services *services.Container, prompter prompters.GeneralRoutesReporterPrompter) *GeneralRoutesReporter {
@@ -80,6 +99,15 @@ func NewGeneralRoutesReporter(config *config.Config, storage storage.Storage, se
 		rendererGS: &report_renderers.GoogleSheetsRenderer{},
 		isRenderGS: config.Reports().GeneralRoutes().IsRenderGoogleSheets(),
 
+		rendererTG: &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
+		tgChatID:   config.Telegram().RatingAlerts().ChatID(),
+
+		reportRatingAlert:    &reports.RatingAlertReport{},
+		ratingTracker:        newGeneralRoutesRatingTracker(config.Reports().GeneralRoutes().RatingAlerts()),
+		ratingAlertThreshold: config.Reports().GeneralRoutes().RatingAlerts().Threshold(),
+
+		alerts: newAlertNotifier(services, alerts.SourceGeneralRoutes, config.Telegram().Alerts().ChatID()),
+
 		officeID:                    config.Logistic().Office().ID(),
 		suppliers:                   config.Logistic().Office().SuppliersMap(),
 		skipRoutes:                  config.Logistic().Office().SkipRoutesMap(),
@@ -120,6 +148,21 @@ func (r *GeneralRoutesReporter) Run(ctx context.Context) error {
 		return errors.Wrap(err, "GeneralRoutesReporter.Run()", "failed processing routes")
 	}
 
+	// History is auxiliary, so its failure must not break the report
+	if err = r.saveHistory(now, r.reportDataList); err != nil {
+		r.prompter.PromptError("Failed save routes history")
+		logger.Logf(logger.ERROR, "GeneralRoutesReporter.Run()", "failed save routes history: %v", err)
+	}
+
+	// Alerts are auxiliary too
+	if err = r.processAlerts(ctx, now, r.reportDataList); err != nil {
+		r.prompter.PromptError("Failed process alerts")
+		logger.Logf(logger.ERROR, "GeneralRoutesReporter.Run()", "failed processing alerts: %v", err)
+	}
+
+	r.saveSnapshot(now, r.reportDataList)
+	r.saveMetrics(r.reportDataList)
+
 	if err = r.sendReport(ctx, r.reportMetaData, r.reportDataList); err != nil {
 		r.prompter.PromptError("Failed send report")
 		return errors.Wrap(err, "GeneralRoutesReporter.Run()", "failed send report")
@@ -129,6 +172,99 @@ func (r *GeneralRoutesReporter) Run(ctx context.Context) error {
 	return nil
 }
 
+// Snapshot Returns the copy of the last report data and its time
+func (r *GeneralRoutesReporter) Snapshot() (time.Time, []*reports.GeneralRoutesReportData) {
+	r.snapshotMtx.RLock()
+	defer r.snapshotMtx.RUnlock()
+	return r.snapshotTime, r.snapshot
+}
+
+func (r *GeneralRoutesReporter) saveSnapshot(now time.Time, list []*reports.GeneralRoutesReportData) {
+	snapshot := make([]*reports.GeneralRoutesReportData, 0, len(list))
+	for _, data := range list {
+		if data == nil {
+			continue
+		}
+		copyData := *data
+		snapshot = append(snapshot, &copyData)
+	}
+
+	r.snapshotMtx.Lock()
+	defer r.snapshotMtx.Unlock()
+	r.snapshotTime = now
+	r.snapshot = snapshot
+}
+
+// saveMetrics Replaces the route gauges, so the routes which are gone from the report are not exposed
+func (r *GeneralRoutesReporter) saveMetrics(list []*reports.GeneralRoutesReportData) {
+	metrics.RouteRemainsBarcodes.Reset()
+	metrics.RouteBarcodes.Reset()
+	metrics.RouteTares.Reset()
+	for _, data := range list {
+		if data == nil {
+			continue
+		}
+		route := strconv.Itoa(data.RouteID)
+		metrics.RouteRemainsBarcodes.With(route).Set(float64(data.RemainsBarcodes))
+		metrics.RouteBarcodes.With(route).Set(float64(data.Barcodes))
+		metrics.RouteTares.With(route).Set(float64(data.Tares))
+	}
+}
+
+func (r *GeneralRoutesReporter) saveHistory(now time.Time, list []*reports.GeneralRoutesReportData) error {
+	if r.services.RouteHistory == nil {
+		return nil
+	}
+	snapshots := make([]*history.RouteSnapshot, 0, len(list))
+	for _, data := range list {
+		if snapshot := history.NewRouteSnapshot(now, data); snapshot != nil {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	if err := r.services.RouteHistory.Append(snapshots); err != nil {
+		return errors.Wrap(err, "GeneralRoutesReporter.saveHistory()", "")
+	}
+	return nil
+}
+
+func (r *GeneralRoutesReporter) processAlerts(ctx context.Context, now time.Time, list []*reports.GeneralRoutesReportData) error {
+	if !r.alerts.isEnabled() {
+		return nil
+	}
+
+	samples := make([]*alerts.Sample, 0, len(list))
+	for _, data := range list {
+		if data == nil {
+			continue
+		}
+		sample := &alerts.Sample{RouteID: data.RouteID, Values: map[string]float64{
+			alerts.MetricRemainsBarcodes:        float64(data.RemainsBarcodes),
+			alerts.MetricBarcodes:               float64(data.Barcodes),
+			alerts.MetricChangeBarcodes:         float64(data.ChangeBarcodes),
+			alerts.MetricTares:                  float64(data.Tares),
+			alerts.MetricVolumeNormativePercent: float64(data.VolumeNormativeLitersPercent),
+		}}
+		// zero values mean that the data has not been loaded yet
+		if data.Rating != 0 {
+			sample.Values[alerts.MetricRating] = float64(data.Rating)
+		}
+		if data.WaySheetsInterval != 0 {
+			sample.Values[alerts.MetricWaySheetInterval] = float64(data.WaySheetsInterval / time.Millisecond)
+		}
+		samples = append(samples, sample)
+	}
+
+	events, err := r.alerts.process(ctx, now, samples)
+	for _, event := range events {
+		r.prompter.PromptAlert(event.Rule, event.RouteID, event.IsResolved)
+		logger.Logf(logger.WARN, "GeneralRoutesReporter.processAlerts()", "alert %s of route %d, resolved: %t", event.Rule, event.RouteID, event.IsResolved)
+	}
+	if err != nil {
+		return errors.Wrap(err, "GeneralRoutesReporter.processAlerts()", "")
+	}
+	return nil
+}
+
 func (r *GeneralRoutesReporter) resetCache() {
 	r.reportMetaData = &reports.GeneralRoutesReportMetaData{}
 	clear(r.reportData)
@@ -186,7 +322,7 @@ func (r *GeneralRoutesReporter) processReport(ctx context.Context, now time.Time
 			routeData.parking, err = r.loadParking(ctx, routeID)
 			if err != nil {
 				r.prompter.PromptError(fmt.Sprintf("Failed load route info for route %d", routeID))
-				logger.Logf(logger.ERROR, "GeneralRoutesReporter.processReport()", "failed load route info for route %d: %v", routeID, err)
+				logger.With(logger.RouteID(routeID)).Logf(logger.ERROR, "GeneralRoutesReporter.processReport()", "failed load route info for route %d: %v", routeID, err)
 			}
 		}
 		reportData.Parking = routeData.parking
@@ -219,7 +355,7 @@ func (r *GeneralRoutesReporter) processReport(ctx context.Context, now time.Time
 			if err = r.processShipment(ctx, route); err != nil {
 				isUpdatedShipments = false
 				r.prompter.PromptError(fmt.Sprintf("Failed process shipment for route %d", routeID))
-				logger.Logf(logger.ERROR, "GeneralRoutesReporter.processReport()", "failed process shipment for route %d: %v", routeID, err)
+				logger.With(logger.RouteID(routeID)).Logf(logger.ERROR, "GeneralRoutesReporter.processReport()", "failed process shipment for route %d: %v", routeID, err)
 			}
 		}
 		reportData.ShipmentID = routeData.shipmentID
@@ -249,7 +385,7 @@ func (r *GeneralRoutesReporter) processReport(ctx context.Context, now time.Time
 
 	// Rating
 	if now.After(r.prevUpdateRating.Add(r.intervalUpdateRating)) {
-		if err = r.processRating(ctx); err != nil {
+		if err = r.processRating(ctx, now); err != nil {
 			r.prompter.PromptError("Failed process rating")
 			logger.Logf(logger.ERROR, "GeneralRoutesReporter.processReport()", "failed processing rating: %v", err)
 		} else {
@@ -514,7 +650,7 @@ func (r *GeneralRoutesReporter) processWaySheet(ctx context.Context, reportData
 	return nil
 }
 
-func (r *GeneralRoutesReporter) processRating(ctx context.Context) error {
+func (r *GeneralRoutesReporter) processRating(ctx context.Context, now time.Time) error {
 	logger.Log(logger.INFO, "GeneralRoutesReporter.processRating()", "start process rating")
 
 	jobsScheduling, err := r.loadJobsScheduling(ctx)
@@ -522,6 +658,7 @@ func (r *GeneralRoutesReporter) processRating(ctx context.Context) error {
 		return err
 	}
 
+	snapshots := make([]*history.RatingSnapshot, 0, len(r.reportData))
 	for _, route := range jobsScheduling.Route {
 		if route == nil || route.Rating == nil || route.SrcOfficeId != r.officeID {
 			continue
@@ -534,7 +671,71 @@ func (r *GeneralRoutesReporter) processRating(ctx context.Context) error {
 		}
 
 		reportData.Rating = float32(route.Rating.OverallRating)
+		snapshots = append(snapshots, history.NewRatingSnapshot(now, route.RouteID, route.Rating))
+	}
+
+	// History and alerts are auxiliary, so their failure must not break the report
+	if r.services.RatingHistory != nil {
+		if err = r.services.RatingHistory.Append(snapshots); err != nil {
+			r.prompter.PromptError("Failed save rating history")
+			logger.Logf(logger.ERROR, "GeneralRoutesReporter.processRating()", "failed save rating history: %v", err)
+		}
+	}
+	if err = r.processRatingAlerts(ctx, now, snapshots); err != nil {
+		r.prompter.PromptError("Failed process rating alerts")
+		logger.Logf(logger.ERROR, "GeneralRoutesReporter.processRating()", "failed processing rating alerts: %v", err)
+	}
+	return nil
+}
+
+func newGeneralRoutesRatingTracker(config *config.ReportsRatingAlerts) *ratingTracker {
+	if !config.IsEnabled() {
+		return nil
+	}
+	return newRatingTracker(config.Threshold(), config.Window())
+}
+
+func (r *GeneralRoutesReporter) processRatingAlerts(ctx context.Context, now time.Time, snapshots []*history.RatingSnapshot) error {
+	if r.ratingTracker == nil {
+		return nil
+	}
+
+	// restore the window after restart, otherwise drops which started before it would be lost
+	if !r.isRatingTrackerSeed && r.services.RatingHistory != nil {
+		r.isRatingTrackerSeed = true
+		prev, err := r.services.RatingHistory.Query(0, now.Add(-r.ratingTracker.window), now.Add(-time.Nanosecond))
+		if err != nil {
+			logger.Logf(logger.WARN, "GeneralRoutesReporter.processRatingAlerts()", "failed load rating history: %v", err)
+		} else {
+			r.ratingTracker.Seed(prev)
+		}
+	}
+
+	var drops []*reports.RatingDropData
+	for _, snapshot := range snapshots {
+		drops = append(drops, r.ratingTracker.Add(snapshot)...)
+	}
+	if len(drops) == 0 {
+		return nil
+	}
+
+	for _, drop := range drops {
+		r.prompter.PromptRatingDrop(drop.RouteID, drop.Component, drop.From, drop.To)
+		logger.Logf(logger.WARN, "GeneralRoutesReporter.processRatingAlerts()", "rating of route %d dropped: %s %.2f -> %.2f", drop.RouteID, drop.Component, drop.From, drop.To)
+	}
+
+	report, err := r.reportRatingAlert.Render(&reports.RatingAlertReportData{Threshold: r.ratingAlertThreshold, Drops: drops})
+	if err != nil {
+		return errors.Wrap(err, "GeneralRoutesReporter.processRatingAlerts()", "failed render rating alert")
+	}
+	messages, err := r.rendererTG.Render(report)
+	if err != nil {
+		return errors.Wrap(err, "GeneralRoutesReporter.processRatingAlerts()", "failed render rating alert for Telegram Bot")
+	}
+	if err = enqueueTelegramBot(r.services, "GeneralRoutesReporter", r.tgChatID, messages, 0); err != nil {
+		return errors.Wrap(err, "GeneralRoutesReporter.processRatingAlerts()", "failed send rating alert to Telegram Bot")
 	}
+	r.prompter.PromptSendReport("Telegram Bot")
 	return nil
 }
 
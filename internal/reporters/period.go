@@ -0,0 +1,44 @@
+package reporters
+
+import (
+	"time"
+	"wb_logistic_assistant/internal/config"
+)
+
+type ReportPeriod int
+
+const (
+	ReportPeriodDay ReportPeriod = iota
+	ReportPeriodWeek
+	ReportPeriodMonth
+)
+
+// NewReportPeriod Converts the period name from the configuration, the week is used by default
+func NewReportPeriod(name string) ReportPeriod {
+	switch name {
+	case config.ReportPeriodDay:
+		return ReportPeriodDay
+	case config.ReportPeriodMonth:
+		return ReportPeriodMonth
+	default:
+		return ReportPeriodWeek
+	}
+}
+
+// Bounds Returns the UTC bounds of the period containing t shifted by offset periods
+func (p ReportPeriod) Bounds(t time.Time, offset int) (start, end time.Time) {
+	t = t.UTC()
+	switch p {
+	case ReportPeriodDay:
+		start = time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	case ReportPeriodMonth:
+		start = time.Date(t.Year(), t.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	default:
+		weekday := (int(t.Weekday()) + 6) % 7 // monday is the first day
+		start = time.Date(t.Year(), t.Month(), t.Day()-weekday+offset*7, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 0, 7).Add(-time.Nanosecond)
+	}
+	return start, end
+}
@@ -0,0 +1,100 @@
+package reporters
+
+import (
+	"time"
+	"wb_logistic_assistant/internal/history"
+	"wb_logistic_assistant/internal/reports"
+)
+
+type ratingComponent struct {
+	name  string
+	value func(s *history.RatingSnapshot) float64
+}
+
+// ratingComponents Sub-scores of the route rating which are watched for drops
+var ratingComponents = []ratingComponent{
+	{name: "Скорость буфера", value: func(s *history.RatingSnapshot) float64 { return s.BufferSpeed }},
+	{name: "Скорость в пути", value: func(s *history.RatingSnapshot) float64 { return s.RoadSpeed }},
+	{name: "Брак", value: func(s *history.RatingSnapshot) float64 { return s.Brak }},
+	{name: "Претензии", value: func(s *history.RatingSnapshot) float64 { return s.Pretensions }},
+	{name: "Активные дни", value: func(s *history.RatingSnapshot) float64 { return s.ActiveDays }},
+	{name: "Без возвратов", value: func(s *history.RatingSnapshot) float64 { return s.NoReturn }},
+	{name: "Авторизация", value: func(s *history.RatingSnapshot) float64 { return s.Authorized }},
+}
+
+type ratingAlertKey struct {
+	routeID   int
+	component string
+}
+
+// ratingTracker Keeps rating snapshots within the window and detects drops of the components against the window maximum
+type ratingTracker struct {
+	threshold float64
+	window    time.Duration
+	recent    map[int][]*history.RatingSnapshot // route id -> snapshots ordered by time
+	alerted   map[ratingAlertKey]time.Time      // route and component -> time of the last alert
+}
+
+func newRatingTracker(threshold float64, window time.Duration) *ratingTracker {
+	return &ratingTracker{
+		threshold: threshold,
+		window:    window,
+		recent:    map[int][]*history.RatingSnapshot{},
+		alerted:   map[ratingAlertKey]time.Time{},
+	}
+}
+
+// Seed Restores the window from history without raising alerts, snapshots must be ordered by time
+func (t *ratingTracker) Seed(snapshots []*history.RatingSnapshot) {
+	for _, snapshot := range snapshots {
+		if snapshot != nil {
+			t.recent[snapshot.RouteID] = append(t.recent[snapshot.RouteID], snapshot)
+		}
+	}
+}
+
+// Add Appends the snapshot to the window and returns drops of the components not alerted within the window
+func (t *ratingTracker) Add(snapshot *history.RatingSnapshot) []*reports.RatingDropData {
+	if snapshot == nil {
+		return nil
+	}
+
+	border := snapshot.Time.Add(-t.window)
+	recent := t.recent[snapshot.RouteID]
+	first := 0
+	for first < len(recent) && recent[first].Time.Before(border) {
+		first++
+	}
+	recent = recent[first:]
+
+	var drops []*reports.RatingDropData
+	for _, component := range ratingComponents {
+		var peak *history.RatingSnapshot
+		for _, prev := range recent {
+			if peak == nil || component.value(prev) > component.value(peak) {
+				peak = prev
+			}
+		}
+		if peak == nil || component.value(peak)-component.value(snapshot) < t.threshold {
+			continue
+		}
+
+		key := ratingAlertKey{routeID: snapshot.RouteID, component: component.name}
+		if alertedAt, ok := t.alerted[key]; ok && snapshot.Time.Sub(alertedAt) < t.window {
+			continue
+		}
+		t.alerted[key] = snapshot.Time
+
+		drops = append(drops, &reports.RatingDropData{
+			RouteID:   snapshot.RouteID,
+			Component: component.name,
+			From:      component.value(peak),
+			To:        component.value(snapshot),
+			FromTime:  peak.Time,
+			ToTime:    snapshot.Time,
+		})
+	}
+
+	t.recent[snapshot.RouteID] = append(recent, snapshot)
+	return drops
+}
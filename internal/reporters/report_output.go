@@ -0,0 +1,143 @@
+package reporters
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/report_renderers"
+	"wb_logistic_assistant/internal/reports"
+	"wb_logistic_assistant/internal/services"
+)
+
+// ReportOutput Destination of the rendered reports which replaces the reporter's own channels, e.g. for backfill
+type ReportOutput interface {
+	Send(ctx context.Context, report *reports.ReportData) error
+	Close() error
+}
+
+type TelegramBotReportOutput struct {
+	service  services.TelegramBotService
+	renderer report_renderers.ReportRenderer[[]string]
+	chatID   int64
+}
+
+func NewTelegramBotReportOutput(service services.TelegramBotService, chatID int64) *TelegramBotReportOutput {
+	return &TelegramBotReportOutput{
+		service:  service,
+		renderer: &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
+		chatID:   chatID,
+	}
+}
+
+func (o *TelegramBotReportOutput) Send(ctx context.Context, report *reports.ReportData) error {
+	messages, err := o.renderer.Render(report)
+	if err != nil {
+		return errors.Wrap(err, "TelegramBotReportOutput.Send()", "failed render report for Telegram Bot")
+	}
+
+	for _, message := range messages {
+		if message == "" {
+			continue
+		}
+		err = retryAction(ctx, "TelegramBotReportOutput.Send", 3, 1*time.Second, func() error {
+			return o.service.SendMessage(o.chatID, message, "HTML")
+		})
+		if err != nil {
+			return errors.Wrapf(err, "TelegramBotReportOutput.Send()", "failed send data to chat %d", o.chatID)
+		}
+	}
+
+	time.Sleep(3 * time.Second) // Telegram limits the rate of messages to one chat
+	return nil
+}
+
+func (o *TelegramBotReportOutput) Close() error {
+	return nil
+}
+
+// GoogleSheetsReportOutput Appends reports one after another to the end of the sheet
+type GoogleSheetsReportOutput struct {
+	service       services.GoogleSheetsService
+	renderer      report_renderers.ReportRenderer[[][]interface{}]
+	spreadsheetID string
+	sheetName     string
+}
+
+func NewGoogleSheetsReportOutput(service services.GoogleSheetsService, spreadsheetID, sheetName string) *GoogleSheetsReportOutput {
+	return &GoogleSheetsReportOutput{
+		service:       service,
+		renderer:      &report_renderers.GoogleSheetsRenderer{},
+		spreadsheetID: spreadsheetID,
+		sheetName:     sheetName,
+	}
+}
+
+func (o *GoogleSheetsReportOutput) Send(ctx context.Context, report *reports.ReportData) error {
+	data, err := o.renderer.Render(report)
+	if err != nil {
+		return errors.Wrap(err, "GoogleSheetsReportOutput.Send()", "failed render report for Google Sheets")
+	}
+	data = append(data, []interface{}{}) // empty row between reports
+
+	err = retryAction(ctx, "GoogleSheetsReportOutput.Send", 3, 1*time.Second, func() error {
+		return o.service.AppendValues(o.spreadsheetID, o.sheetName, "A:Z", data, false, false)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "GoogleSheetsReportOutput.Send()", "failed append to sheet %s, page %s", o.spreadsheetID, o.sheetName)
+	}
+	return nil
+}
+
+func (o *GoogleSheetsReportOutput) Close() error {
+	return nil
+}
+
+// CSVReportOutput Writes reports one after another to the CSV file, separated by an empty row
+type CSVReportOutput struct {
+	file     *os.File
+	writer   *csv.Writer
+	renderer report_renderers.ReportRenderer[[][]string]
+}
+
+func NewCSVReportOutput(path string) (*CSVReportOutput, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "CSVReportOutput.New()", "failed to create file %s", path)
+	}
+	return &CSVReportOutput{
+		file:     file,
+		writer:   csv.NewWriter(file),
+		renderer: &report_renderers.CSVRenderer{},
+	}, nil
+}
+
+func (o *CSVReportOutput) Send(ctx context.Context, report *reports.ReportData) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "CSVReportOutput.Send()", "task was preliminarily completed")
+	}
+
+	data, err := o.renderer.Render(report)
+	if err != nil {
+		return errors.Wrap(err, "CSVReportOutput.Send()", "failed render report for CSV")
+	}
+
+	data = append(data, []string{})
+	if err = o.writer.WriteAll(data); err != nil {
+		return errors.Wrapf(err, "CSVReportOutput.Send()", "failed write to file %s", o.file.Name())
+	}
+	return nil
+}
+
+func (o *CSVReportOutput) Close() error {
+	o.writer.Flush()
+	if err := o.writer.Error(); err != nil {
+		o.file.Close()
+		return errors.Wrapf(err, "CSVReportOutput.Close()", "failed flush file %s", o.file.Name())
+	}
+	if err := o.file.Close(); err != nil {
+		return errors.Wrapf(err, "CSVReportOutput.Close()", "failed close file %s", o.file.Name())
+	}
+	return nil
+}
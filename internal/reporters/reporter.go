@@ -1,7 +1,26 @@
 package reporters
 
-import "context"
+import (
+	"context"
+	"time"
+	"wb_logistic_assistant/internal/reports"
+)
 
 type Reporter interface {
 	Run(ctx context.Context) error
 }
+
+// Backfiller Reporter which can recompute reports for a past period
+type Backfiller interface {
+	Backfill(ctx context.Context, from, to time.Time, output ReportOutput) error
+}
+
+// GeneralRoutesSnapshotter Reporter which keeps the data of its last report, safe for concurrent use
+type GeneralRoutesSnapshotter interface {
+	Snapshot() (time.Time, []*reports.GeneralRoutesReportData)
+}
+
+// DayRenderer Reporter which can render a report of the day on demand
+type DayRenderer interface {
+	RenderDay(ctx context.Context, date time.Time) (*reports.ReportData, error)
+}
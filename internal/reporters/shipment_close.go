@@ -24,17 +24,14 @@ type ShipmentCloseReporter struct {
 	report   *reports.ShipmentCloseReport
 	prompter prompters.ShipmentCloseReporterPrompter
 
-	messageQueueTG        *Queue[string]
-	counterErrMessageSend int
-	limitErrMessageSend   int
-	rendererGS            report_renderers.ReportRenderer[[][]interface{}]
-	rendererTG            report_renderers.ReportRenderer[[]string]
-	isRenderGS            bool
-	isRenderTG            bool
-	spreadsheetID         string
-	sheetName             string
-	sheetPosition         string
-	tgChatID              int64
+	rendererGS    report_renderers.ReportRenderer[[][]interface{}]
+	rendererTG    report_renderers.ReportRenderer[[]string]
+	isRenderGS    bool
+	isRenderTG    bool
+	spreadsheetID string
+	sheetName     string
+	sheetPosition string
+	tgChatID      int64
 
 	officeID                int
 	suppliers               map[int]struct{} // supplier id -> struct{}
@@ -46,24 +43,27 @@ type ShipmentCloseReporter struct {
 	openedShipments map[int]int // shipment id -> route id
 }
 
+// shipmentCloseReporterState Checkpoint of the reporter in the cache store
+type shipmentCloseReporterState struct {
+	OpenedShipments map[int]int `json:"opened_shipments"`
+}
+
 func NewShipmentCloseReporter(config *config.Config, storage storage.Storage, service *services.Container, prompter prompters.ShipmentCloseReporterPrompter) *ShipmentCloseReporter {
-	return &ShipmentCloseReporter{
+	r := &ShipmentCloseReporter{
 		config:   config,
 		storage:  storage,
 		services: service,
 		prompter: prompter,
 		report:   &reports.ShipmentCloseReport{},
 
-		messageQueueTG:      New[string](300),
-		limitErrMessageSend: 3,
-		rendererGS:          &report_renderers.GoogleSheetsRenderer{},
-		rendererTG:          &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
-		isRenderGS:          config.Reports().ShipmentClose().IsRenderGoogleSheets(),
-		isRenderTG:          config.Reports().ShipmentClose().IsRenderTelegramBot(),
-		spreadsheetID:       config.GoogleSheets().ReportSheets().GeneralRoutes().SpreadsheetID(),
-		sheetName:           config.GoogleSheets().ReportSheets().GeneralRoutes().SheetName(),
-		sheetPosition:       "A1",
-		tgChatID:            config.Telegram().ShipmentClose().ChatID(),
+		rendererGS:    &report_renderers.GoogleSheetsRenderer{},
+		rendererTG:    &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
+		isRenderGS:    config.Reports().ShipmentClose().IsRenderGoogleSheets(),
+		isRenderTG:    config.Reports().ShipmentClose().IsRenderTelegramBot(),
+		spreadsheetID: config.GoogleSheets().ReportSheets().GeneralRoutes().SpreadsheetID(),
+		sheetName:     config.GoogleSheets().ReportSheets().GeneralRoutes().SheetName(),
+		sheetPosition: "A1",
+		tgChatID:      config.Telegram().ShipmentClose().ChatID(),
 
 		officeID:                config.Logistic().Office().ID(),
 		suppliers:               config.Logistic().Office().SuppliersMap(),
@@ -73,26 +73,20 @@ func NewShipmentCloseReporter(config *config.Config, storage storage.Storage, se
 
 		openedShipments: map[int]int{},
 	}
+	r.restoreState()
+	return r
 }
 
 func (r *ShipmentCloseReporter) Run(ctx context.Context) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "ShipmentCloseReporter.Run()", "task was preliminarily completed")
 	}
+	defer r.saveState()
 
 	r.prompter.PromptStart()
 
 	timeStart := time.Now()
 
-	if r.messageQueueTG.Len() > 0 {
-		err := r.sendTelegramBot(ctx)
-		if err != nil {
-			r.prompter.PromptError("Failed sending remains messages to Telegram bot")
-			return errors.Wrap(err, "ShipmentCloseReporter.Run()", "failed sending remains messages to Telegram bot")
-		}
-		logger.Log(logger.INFO, "ShipmentCloseReporter.Run()", "send remains messages to Telegram bot")
-	}
-
 	if timeStart.After(r.prevTimeUpdateShipments.Add(r.intervalUpdateShipments)) {
 		if err := r.findOpenedShipments(ctx); err != nil {
 			r.prompter.PromptError("Failed finding opened shipments")
@@ -164,11 +158,12 @@ func (r *ShipmentCloseReporter) processOpenedShipments(ctx context.Context) erro
 	logger.Log(logger.INFO, "ShipmentCloseReporter.processOpenedShipments()", "start process opened shipments")
 
 	for shipmentID, routeID := range r.openedShipments {
+		log := logger.With(logger.RouteID(routeID), logger.ShipmentID(shipmentID))
 		info, err := r.loadShipmentInfo(ctx, shipmentID)
 		if err != nil {
 			delete(r.openedShipments, shipmentID)
 			r.prompter.PromptError(fmt.Sprintf("Failed loading shipment info for shipment %d", shipmentID))
-			logger.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "failed load shipment info, route %d, shipment %d: %v", routeID, shipmentID, err)
+			log.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "failed load shipment info, route %d, shipment %d: %v", routeID, shipmentID, err)
 			continue
 		}
 
@@ -177,13 +172,13 @@ func (r *ShipmentCloseReporter) processOpenedShipments(ctx context.Context) erro
 			transferBoxes, err := r.loadShipmentTransfersBoxes(ctx, shipmentID)
 			if err != nil {
 				r.prompter.PromptError(fmt.Sprintf("Failed loading shipment transfers boxes for shipment %d", shipmentID))
-				logger.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "failed load shipment transfer boxes, shipment %d: %v", shipmentID, err)
+				log.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "failed load shipment transfer boxes, shipment %d: %v", shipmentID, err)
 			}
 
 			totalTransferBarcodes := 0
 			for _, box := range transferBoxes {
 				if box == nil {
-					logger.Logf(logger.WARN, "ShipmentCloseReporter.processOpenedShipments()", "transfer box is nil, shipment %d", shipmentID)
+					log.Logf(logger.WARN, "ShipmentCloseReporter.processOpenedShipments()", "transfer box is nil, shipment %d", shipmentID)
 					continue
 				}
 				totalTransferBarcodes += box.CountBarcodes
@@ -193,7 +188,7 @@ func (r *ShipmentCloseReporter) processOpenedShipments(ctx context.Context) erro
 			barcodesStandard, ok := r.barcodesStandard[routeID]
 			if !ok {
 				r.prompter.PromptError(fmt.Sprintf("There is no barcode standard for route %d, shipment %d", routeID, shipmentID))
-				logger.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "there is no barcode standard for route %d, shipment %d", routeID, shipmentID)
+				log.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "there is no barcode standard for route %d, shipment %d", routeID, shipmentID)
 			}
 
 			var barcodesDeviationPercent float64
@@ -214,14 +209,14 @@ func (r *ShipmentCloseReporter) processOpenedShipments(ctx context.Context) erro
 			if err != nil {
 				remainsTares = make([]*wb_models.TareForOffice, 0)
 				r.prompter.PromptError(fmt.Sprintf("Failed loading remains tares for shipment %d", shipmentID))
-				logger.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "failed load remains tares, shipment %d: %v", shipmentID, err)
+				log.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "failed load remains tares, shipment %d: %v", shipmentID, err)
 			}
 
 			remainsTaresInfo := make([]*reports.ShipmentCloseRemainsTareInfo, len(remainsTares))
 			totalRemainsBarcodes := 0
 			for i, t := range remainsTares {
 				if t == nil {
-					logger.Logf(logger.WARN, "ShipmentCloseReporter.processOpenedShipments()", "remains tare is nil for shipment %d, total tares %d", shipmentID, len(remainsTares))
+					log.Logf(logger.WARN, "ShipmentCloseReporter.processOpenedShipments()", "remains tare is nil for shipment %d, total tares %d", shipmentID, len(remainsTares))
 					remainsTaresInfo[i] = &reports.ShipmentCloseRemainsTareInfo{}
 					continue
 				}
@@ -244,7 +239,7 @@ func (r *ShipmentCloseReporter) processOpenedShipments(ctx context.Context) erro
 			}
 
 			r.prompter.PromptShipmentClose(routeID, shipmentID)
-			logger.Logf(logger.INFO, "ShipmentCloseReporter.processOpenedShipments()", "shipment %d is closed on route %d, waysheet %d", shipmentID, routeID, info.WaySheetID)
+			log.With(logger.WaySheetID(info.WaySheetID)).Logf(logger.INFO, "ShipmentCloseReporter.processOpenedShipments()", "shipment %d is closed on route %d, waysheet %d", shipmentID, routeID, info.WaySheetID)
 			err = r.sendReport(ctx, &reports.ShipmentCloseReportData{
 				RouteID:                  routeID,
 				ShipmentID:               shipmentID,
@@ -266,7 +261,7 @@ func (r *ShipmentCloseReporter) processOpenedShipments(ctx context.Context) erro
 			})
 			if err != nil {
 				r.prompter.PromptError(fmt.Sprintf("Failed send report on route %d, shipment: %d", routeID, shipmentID))
-				logger.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "failed send report on route %d, shipment %d: %v", routeID, shipmentID, err)
+				log.Logf(logger.ERROR, "ShipmentCloseReporter.processOpenedShipments()", "failed send report on route %d, shipment %d: %v", routeID, shipmentID, err)
 				continue
 			}
 			delete(r.openedShipments, shipmentID)
@@ -275,6 +270,32 @@ func (r *ShipmentCloseReporter) processOpenedShipments(ctx context.Context) erro
 	return nil
 }
 
+func (r *ShipmentCloseReporter) restoreState() {
+	state := &shipmentCloseReporterState{}
+	ok, err := loadCheckpoint(r.storage, storage.CacheShipmentCloseReporter, state)
+	if err != nil {
+		logger.Logf(logger.WARN, "ShipmentCloseReporter.restoreState()", "failed restore state, starting from scratch: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	for shipmentID, routeID := range state.OpenedShipments {
+		r.openedShipments[shipmentID] = routeID
+	}
+	logger.Logf(logger.INFO, "ShipmentCloseReporter.restoreState()", "restore state: opened shipments %d", len(r.openedShipments))
+}
+
+func (r *ShipmentCloseReporter) saveState() {
+	err := saveCheckpoint(r.config, r.storage, storage.CacheShipmentCloseReporter, &shipmentCloseReporterState{
+		OpenedShipments: r.openedShipments,
+	})
+	if err != nil {
+		logger.Logf(logger.ERROR, "ShipmentCloseReporter.saveState()", "failed save state: %v", err)
+	}
+}
+
 func (r *ShipmentCloseReporter) isValidRoute(route *wb_models.Route) bool {
 	if route == nil || len(route.Suppliers) == 0 {
 		return false
@@ -420,19 +441,20 @@ func (r *ShipmentCloseReporter) sendReport(ctx context.Context, reportData *repo
 		r.prompter.PromptError("Failed to render report")
 		return errors.Wrapf(err, "ShipmentCloseReporter.sendReport()", "failed render report, route id: %d shipment id: %d, way sheet id: %d", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID)
 	}
+	log := logger.With(logger.RouteID(reportData.RouteID), logger.ShipmentID(reportData.ShipmentID), logger.WaySheetID(reportData.WaySheetID))
 
 	if r.isRenderGS {
 		data, err := r.rendererGS.Render(report)
 		if err != nil {
 			r.prompter.PromptError("Failed to render report Google Sheet")
-			logger.Logf(logger.ERROR, "ShipmentCloseReporter.sendReport()", "failed render report for Google Sheets, route id: %d shipment id: %d, way sheet id: %d: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
+			log.Logf(logger.ERROR, "ShipmentCloseReporter.sendReport()", "failed render report for Google Sheets, route id: %d shipment id: %d, way sheet id: %d: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
 		} else {
 			if err = r.sendGoogleSheets(ctx, data); err != nil {
 				r.prompter.PromptError("Failed to send report Google Sheet")
-				logger.Logf(logger.ERROR, "ShipmentCloseReporter.sendReport()", "failed send report to Google Sheets, route id: %d shipment id: %d, way sheet id: %d: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
+				log.Logf(logger.ERROR, "ShipmentCloseReporter.sendReport()", "failed send report to Google Sheets, route id: %d shipment id: %d, way sheet id: %d: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
 			} else {
 				r.prompter.PromptSendReport(reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID)
-				logger.Logf(logger.INFO, "ShipmentCloseReporter.sendReport()", "send report to Google Sheets, route id: %d, shipment id: %d, waysheet id: %d", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID)
+				log.Logf(logger.INFO, "ShipmentCloseReporter.sendReport()", "send report to Google Sheets, route id: %d, shipment id: %d, waysheet id: %d", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID)
 			}
 		}
 	}
@@ -441,24 +463,14 @@ func (r *ShipmentCloseReporter) sendReport(ctx context.Context, reportData *repo
 		messages, err := r.rendererTG.Render(report)
 		if err != nil {
 			r.prompter.PromptError("failed to render report Telegram Bot")
-			logger.Logf(logger.ERROR, "ShipmentCloseReporter.sendReport()", "failed render report for Telegram Bot, route id: %d shipment id: %d, way sheet id: %d: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
+			log.Logf(logger.ERROR, "ShipmentCloseReporter.sendReport()", "failed render report for Telegram Bot, route id: %d shipment id: %d, way sheet id: %d: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
 		} else {
-			if len(messages) == 0 {
-				return nil
-			}
-
-			for _, message := range messages {
-				if message != "" {
-					r.messageQueueTG.Push(message)
-				}
-			}
-
-			if err = r.sendTelegramBot(ctx); err != nil {
+			if err = enqueueTelegramBot(r.services, "ShipmentCloseReporter", r.tgChatID, messages, 0); err != nil {
 				r.prompter.PromptError("failed to send report Telegram Bot")
-				logger.Logf(logger.ERROR, "ShipmentCloseReporter.sendReport()", "failed send report to Telegram Bot, route id: %d shipment id: %d, way sheet id: %d: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
+				log.Logf(logger.ERROR, "ShipmentCloseReporter.sendReport()", "failed send report to Telegram Bot, route id: %d shipment id: %d, way sheet id: %d: %v", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID, err)
 			} else {
 				r.prompter.PromptSendReport(reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID)
-				logger.Logf(logger.INFO, "ShipmentCloseReporter.sendReport()", "send report to Telegram Bot, route id: %d, shipment id: %d, waysheet id: %d", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID)
+				log.Logf(logger.INFO, "ShipmentCloseReporter.sendReport()", "send report to Telegram Bot, route id: %d, shipment id: %d, waysheet id: %d", reportData.RouteID, reportData.ShipmentID, reportData.WaySheetID)
 			}
 		}
 	}
@@ -479,34 +491,3 @@ func (r *ShipmentCloseReporter) sendGoogleSheets(ctx context.Context, data [][]i
 	}
 	return nil
 }
-
-func (r *ShipmentCloseReporter) sendTelegramBot(ctx context.Context) error {
-	if r.messageQueueTG.Len() <= 0 {
-		return nil
-	}
-
-	for r.messageQueueTG.Len() > 0 {
-		err := retryAction(ctx, "ShipmentCloseReporter.sendTelegramBot", 3, 1*time.Second, func() error {
-			message, ok := r.messageQueueTG.Peek()
-			if !ok {
-				return errors.New("ShipmentCloseReporter.sendTelegramBot()", "failed to get message from Telegram message queue")
-			}
-
-			return r.services.TelegramBotService.SendMessage(r.tgChatID, message, "HTML")
-		})
-		if err != nil {
-			r.counterErrMessageSend++
-			// if an error occurs in any of the messages or Telegram refuses to accept the message, it is better to reset the message queue
-			if r.counterErrMessageSend >= r.limitErrMessageSend {
-				r.messageQueueTG.Reset()
-				r.counterErrMessageSend = 0
-			}
-			return errors.Wrapf(err, "ShipmentCloseReporter.sendTelegramBot()", "failed send data to chat %d", r.tgChatID)
-		}
-
-		r.counterErrMessageSend = 0
-		r.messageQueueTG.Pop()
-	}
-
-	return nil
-}
@@ -6,6 +6,8 @@ import (
 	"time"
 	"wb_logistic_assistant/internal/errors"
 	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/outbox"
+	"wb_logistic_assistant/internal/services"
 )
 
 var NullTime = time.Time{}
@@ -23,6 +25,7 @@ func atofSafe(s string) float64 {
 	return f
 }
 
+// retryAction The WB logistic errors which are not retryable, e.g. 401, 404 or the decode error, are returned at once
 func retryAction(ctx context.Context, source string, attempts int, delay time.Duration, fn func() error) error {
 	var err error
 	for i := 0; i < attempts; i++ {
@@ -30,6 +33,9 @@ func retryAction(ctx context.Context, source string, attempts int, delay time.Du
 		if err == nil {
 			return nil
 		}
+		if !services.IsWBLogisticRetryable(err) {
+			return errors.Wrapf(err, "Reporters.retryAction()", "not retryable failure of %s", source)
+		}
 		logger.Logf(logger.WARN, "Reporters.retryAction()", "failed action %s, attempt %d/%d: %v", source, i+1, attempts, err)
 
 		delay *= time.Duration(1 << i)
@@ -42,6 +48,30 @@ func retryAction(ctx context.Context, source string, attempts int, delay time.Du
 	return errors.Wrapf(err, "Reporters.retryAction()", "all %d attempts failed for %s", attempts, source)
 }
 
+// enqueueTelegramBot Puts the messages into the outbox, which delivers them to the chat in the background.
+// Delay is the pause after each message before the next one to the chat
+func enqueueTelegramBot(services *services.Container, source string, chatID int64, messages []string, delay time.Duration) error {
+	if services.Outbox == nil {
+		return errors.New("Reporters.enqueueTelegramBot()", "outbox is not initialized")
+	}
+	for _, message := range messages {
+		if message == "" {
+			continue
+		}
+		err := services.Outbox.Enqueue(&outbox.Message{
+			Destination: chatID,
+			Text:        message,
+			ParseMode:   "HTML",
+			Source:      source,
+			Delay:       delay,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Reporters.enqueueTelegramBot()", "failed enqueue message of %s to chat %d", source, chatID)
+		}
+	}
+	return nil
+}
+
 // SpNameToGateParking parse sp name and returns values.
 //
 //	Input example: шуш143.вор89-92.буфер303, Буфер_Маршрут 62, СЦМШ143_Буфер в МШ 177
@@ -0,0 +1,42 @@
+package reporters
+
+import (
+	"context"
+	std_errors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+	wb_errors "wb_logistic_assistant/external/wb_logistic_api/errors"
+)
+
+func TestRetryAction(t *testing.T) {
+	const url = "https://logistic.wb.ru/shipments-service/api/v1/shipments"
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{"unauthorized", wb_errors.NewRequestError(url, http.StatusUnauthorized, nil), 1},
+		{"not found", fmt.Errorf("failed get shipments: %w", wb_errors.NewRequestError(url, http.StatusNotFound, nil)), 1},
+		{"decode", wb_errors.NewDecodeError(url, http.StatusOK, std_errors.New("invalid JSON")), 1},
+		{"rate limited", wb_errors.NewRequestError(url, http.StatusTooManyRequests, nil), 3},
+		{"server error", wb_errors.NewRequestError(url, http.StatusBadGateway, nil), 3},
+		{"unknown error", std_errors.New("sheet is locked"), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retryAction(context.Background(), "Test", 3, time.Millisecond, func() error {
+				calls++
+				return tt.err
+			})
+			if !std_errors.Is(err, tt.err) {
+				t.Fatalf("error %v does not wrap %v", err, tt.err)
+			}
+			if calls != tt.calls {
+				t.Fatalf("%d calls, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
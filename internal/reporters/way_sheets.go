@@ -0,0 +1,71 @@
+package reporters
+
+import (
+	"context"
+	"fmt"
+	"time"
+	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/models"
+	"wb_logistic_assistant/internal/services"
+)
+
+const waySheetsPageLimit = 1000
+
+// errorPrompter Part of the reporter prompters used by the shared loaders
+type errorPrompter interface {
+	PromptError(message string)
+}
+
+// loadWaySheetsPages Loads every page of the way sheets of the suppliers opened in [dOpen, dClose].
+// Fails if any page is not loaded, the totals of a partially loaded supplier would be understated
+func loadWaySheetsPages(
+	ctx context.Context,
+	service services.WBLogisticService,
+	prompter errorPrompter,
+	source string,
+	officeID int,
+	suppliers map[int]struct{},
+	dOpen, dClose time.Time,
+) ([]*wb_models.WaySheet, error) {
+	location := source + ".loadWaySheets()"
+
+	var waySheets []*wb_models.WaySheet
+	var errs []error
+	for supplierID := range suppliers {
+		// the period may contain more way sheets than one page holds
+		for offset := 0; ; offset += waySheetsPageLimit {
+			var page *wb_models.WaySheetsPage
+			err := retryAction(ctx, source+".loadWaySheets", 3, 1*time.Second, func() (err error) {
+				page, err = service.GetWaySheets(ctx, &models.WBLogisticGetWaySheetsParamsRequest{
+					DateOpen:    dOpen,
+					DateClose:   dClose,
+					SupplierID:  supplierID,
+					SrcOfficeID: officeID,
+					Offset:      offset,
+					Limit:       waySheetsPageLimit,
+					WayTypeID:   0,
+				})
+				return err
+			})
+			if err == nil && page == nil {
+				err = errors.New(location, "way sheets page is nil")
+			}
+			if err != nil {
+				prompter.PromptError(fmt.Sprintf("failed load way sheets for supplier %d", supplierID))
+				logger.Logf(logger.ERROR, location, "failed load way sheets for supplier %d, offset %d: %v", supplierID, offset, err)
+				errs = append(errs, fmt.Errorf("supplier %d, offset %d: %w", supplierID, offset, err))
+				break
+			}
+			waySheets = append(waySheets, page.WaySheets...)
+			if len(page.WaySheets) < waySheetsPageLimit {
+				break
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Wrap(errors.Join(errs...), location, "failed load way sheets")
+	}
+	return waySheets, nil
+}
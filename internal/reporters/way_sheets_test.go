@@ -0,0 +1,60 @@
+package reporters
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
+	"wb_logistic_assistant/internal/models"
+	"wb_logistic_assistant/internal/services"
+)
+
+// pagedWaySheetsStub Serves 'total' way sheets of every supplier, the page at 'failOffset' of 'failSupplier' fails
+type pagedWaySheetsStub struct {
+	services.WBLogisticService
+	total        int
+	failSupplier int
+	failOffset   int
+}
+
+func (s *pagedWaySheetsStub) GetWaySheets(_ context.Context, params *models.WBLogisticGetWaySheetsParamsRequest) (*wb_models.WaySheetsPage, error) {
+	if params.SupplierID == s.failSupplier && params.Offset == s.failOffset {
+		return nil, errors.New("bad gateway")
+	}
+	page := &wb_models.WaySheetsPage{TotalWaySheets: s.total}
+	for i := params.Offset; i < min(params.Offset+params.Limit, s.total); i++ {
+		page.WaySheets = append(page.WaySheets, &wb_models.WaySheet{WaySheetID: strconv.Itoa(params.SupplierID*10000 + i)})
+	}
+	return page, nil
+}
+
+type silentPrompter struct{}
+
+func (silentPrompter) PromptError(string) {}
+
+func TestLoadWaySheetsPages(t *testing.T) {
+	suppliers := map[int]struct{}{1: {}, 2: {}}
+
+	waySheets, err := loadWaySheetsPages(context.Background(), &pagedWaySheetsStub{total: 2500, failSupplier: -1},
+		silentPrompter{}, "Test", 100, suppliers, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(waySheets) != 5000 {
+		t.Fatalf("loaded %d way sheets, want 5000", len(waySheets))
+	}
+
+	// the failed page is retried, so the context ends the wait between the attempts
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	waySheets, err = loadWaySheetsPages(ctx, &pagedWaySheetsStub{total: 2500, failSupplier: 2, failOffset: waySheetsPageLimit},
+		silentPrompter{}, "Test", 100, suppliers, time.Time{}, time.Time{})
+	if err == nil {
+		t.Fatalf("partially loaded supplier is not an error, loaded %d way sheets", len(waySheets))
+	}
+	if waySheets != nil {
+		t.Fatalf("way sheets are returned with the error: %d", len(waySheets))
+	}
+}
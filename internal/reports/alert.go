@@ -0,0 +1,79 @@
+package reports
+
+import (
+	"fmt"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+// AlertData Fired or resolved alert rule for the route
+type AlertData struct {
+	Rule       string
+	Metric     string
+	Operator   string
+	Threshold  float64
+	Value      float64
+	IsDuration bool // threshold and value are durations in ms
+	RouteID    int
+	Since      time.Time
+	Time       time.Time
+	IsResolved bool
+}
+
+type AlertReportData struct {
+	Alerts []*AlertData
+}
+
+type AlertReport struct{}
+
+func (r *AlertReport) Render(data *AlertReportData) (*ReportData, error) {
+	if data == nil || len(data.Alerts) == 0 {
+		return nil, errors.New("AlertReport.Render()", "data is empty")
+	}
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: time.Now().Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	report.Body = &Item{}
+
+	for _, alert := range data.Alerts {
+		if alert == nil {
+			continue
+		}
+
+		title := "ТРЕВОГА"
+		if alert.IsResolved {
+			title = "РЕШЕНО"
+		}
+
+		item := &Item{Block: true, Children: []*Item{
+			{Text: title + ": " + alert.Rule, Bold: true, Block: true},
+			{Text: "Маршрут " + itoa(alert.RouteID), Block: true},
+			{Text: fmt.Sprintf("%s %s %s, сейчас %s", alert.Metric, alert.Operator, r.formatValue(alert.Threshold, alert.IsDuration), r.formatValue(alert.Value, alert.IsDuration)), Block: true},
+		}}
+		if alert.IsResolved {
+			item.AddChild(&Item{Text: "Длительность: " + r.formatDuration(alert.Time.Sub(alert.Since))})
+		} else {
+			item.AddChild(&Item{Text: "с " + alert.Since.Format("02.01 15:04")})
+		}
+		report.Body.AddChild(item)
+	}
+
+	return report, nil
+}
+
+func (r *AlertReport) formatValue(v float64, isDuration bool) string {
+	if isDuration {
+		return r.formatDuration(time.Duration(v) * time.Millisecond)
+	}
+	return ftoa(v)
+}
+
+func (r *AlertReport) formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
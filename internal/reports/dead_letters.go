@@ -0,0 +1,75 @@
+package reports
+
+import (
+	"strconv"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+type DeadLetterData struct {
+	ID          uint64
+	Destination int64
+	Source      string
+	Attempts    int
+	LastError   string
+	CreatedAt   time.Time
+	Preview     string // beginning of the message text
+}
+
+type DeadLettersReportData struct {
+	Pending int // messages waiting for delivery
+	Total   int // all dead letters, only the last of them may be listed
+	Letters []*DeadLetterData
+}
+
+type DeadLettersReport struct{}
+
+func (r *DeadLettersReport) Render(data *DeadLettersReportData) (*ReportData, error) {
+	if data == nil {
+		return nil, errors.New("DeadLettersReport.Render()", "data is empty")
+	}
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: time.Now().Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	report.Body = &Item{
+		Children: []*Item{
+			{Text: "НЕДОСТАВЛЕННЫЕ СООБЩЕНИЯ", Bold: true, Block: true},
+			{Text: "В очереди:", Bold: true, Block: true}, {Text: itoa(data.Pending)},
+			{Text: "Недоставлено:", Bold: true, Block: true}, {Text: itoa(data.Total)},
+			{Block: true},
+		},
+	}
+
+	for _, letter := range data.Letters {
+		if letter == nil {
+			continue
+		}
+
+		item := &Item{Block: true, Children: []*Item{
+			{Text: "#" + strconv.FormatUint(letter.ID, 10), Bold: true, Block: true},
+			{Text: "Чат:", Bold: true, Block: true}, {Text: strconv.FormatInt(letter.Destination, 10)},
+			{Text: "Источник:", Bold: true, Block: true}, {Text: letter.Source},
+			{Text: "Создано:", Bold: true, Block: true}, {Text: letter.CreatedAt.Format("02.01 15:04:05")},
+			{Text: "Попыток:", Bold: true, Block: true}, {Text: itoa(letter.Attempts)},
+		}}
+		if letter.LastError != "" {
+			item.Children = append(item.Children,
+				&Item{Text: "Ошибка:", Bold: true, Block: true}, &Item{Text: letter.LastError, Code: true},
+			)
+		}
+		if letter.Preview != "" {
+			item.Children = append(item.Children,
+				&Item{Text: "Текст:", Bold: true, Block: true}, &Item{Text: letter.Preview, Code: true},
+			)
+		}
+		report.Body.AddChild(item)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,102 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+type DriverPerformanceDriverData struct {
+	DriverID      string
+	DriverName    string
+	VehicleNumber []string
+	Flights       int
+	FlightsOpened int
+
+	BarcodesDelivered int
+	Tare              int
+	TareShipped       int
+	TareReturned      int
+	Fine              float64
+
+	WaySheetsInterval time.Duration // average interval between openings of the way sheets
+
+	AddressesTotal      int
+	AddressesCompleted  int
+	AddressesInSequence int // completed in the planned sequence
+}
+
+type DriverPerformanceReportData struct {
+	DateStart time.Time
+	DateEnd   time.Time
+	Drivers   []*DriverPerformanceDriverData
+}
+
+type DriverPerformanceReport struct{}
+
+func (r *DriverPerformanceReport) Render(data *DriverPerformanceReportData) (*ReportData, error) {
+	if data == nil {
+		return nil, errors.New("DriverPerformanceReport.Render()", "data is empty")
+	}
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: time.Now().Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	report.Body = &Item{
+		Children: []*Item{
+			{Text: "ВОДИТЕЛИ", Bold: true, Block: true},
+			{Block: true},
+			{Text: "Начало:", Bold: true, Block: true}, {Text: data.DateStart.Format("02.01.2006 15:04")},
+			{Text: "Конец:", Bold: true, Block: true}, {Text: data.DateEnd.Format("02.01.2006 15:04")},
+			{Text: "Водителей:", Bold: true, Block: true}, {Text: itoa(len(data.Drivers))},
+			{Block: true},
+		},
+	}
+
+	for _, driver := range data.Drivers {
+		if driver == nil {
+			continue
+		}
+
+		name := driver.DriverName
+		if name == "" {
+			name = driver.DriverID
+		}
+
+		item := &Item{Block: true, Children: []*Item{
+			{Text: name, Bold: true, Block: true},
+			{Text: strings.Join(driver.VehicleNumber, ", ")},
+			{Text: "Рейсы:", Block: true}, {Text: itoa(driver.Flights)},
+			{Text: "Незавершенные:", Block: true}, {Text: itoa(driver.FlightsOpened)},
+			{Text: "ШК доставлено:", Block: true}, {Text: itoa(driver.BarcodesDelivered)},
+			{Text: "Тара отгружено/возврат:", Block: true}, {Text: fmt.Sprintf("%d/%d", driver.TareShipped, driver.TareReturned)},
+			{Text: "Штраф:", Block: true}, {Text: fmt.Sprintf("%.2f р.", driver.Fine)},
+			{Text: "Интервал ПЛ:", Block: true}, {Text: r.formatInterval(driver.WaySheetsInterval)},
+		}}
+
+		if driver.AddressesTotal > 0 {
+			item.AddChild(&Item{Text: "Адреса выполнено:", Block: true})
+			item.AddChild(&Item{Text: fmt.Sprintf("%d/%d (%.0f%%)", driver.AddressesCompleted, driver.AddressesTotal, float64(driver.AddressesCompleted)/float64(driver.AddressesTotal)*100)})
+			item.AddChild(&Item{Text: "По порядку:", Block: true})
+			item.AddChild(&Item{Text: fmt.Sprintf("%d/%d", driver.AddressesInSequence, driver.AddressesCompleted)})
+		}
+
+		report.Body.AddChild(item)
+		report.Body.AddChild(&Item{Block: true})
+	}
+
+	return report, nil
+}
+
+func (r *DriverPerformanceReport) formatInterval(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%dч %02dм", int(d.Hours()), int(d.Minutes())%60)
+}
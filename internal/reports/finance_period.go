@@ -0,0 +1,157 @@
+package reports
+
+import (
+	"fmt"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+// FinancePeriodGroupData Finance of one route or one driver over the period
+type FinancePeriodGroupData struct {
+	RouteID         int
+	DriverName      string
+	Flights         int
+	BarcodesShipped int
+	Income          float64
+	Fine            float64
+	SalaryRate      float64
+	Margin          float64
+	MarginPerFlight float64
+}
+
+type FinancePeriodReportData struct {
+	Title              string
+	DateStart          time.Time
+	DateEnd            time.Time
+	Days               int
+	Flights            int
+	FlightsOpened      int
+	BarcodesShipped    int
+	Tare               int
+	TareShipped        int
+	TareReturned       int
+	Income             float64
+	IncomeReturn       float64
+	Fine               float64
+	SalaryRate         float64
+	ExtendedSalaryRate float64
+	Defect             float64
+	PercentDefect      float64
+	Tax                float64
+	PercentTax         float64
+	Margin             float64
+	Expenses           float64
+	TotalMargin        float64
+
+	AverageBarcodesPerFlight float64
+	AverageIncomePerFlight   float64
+	AverageMarginPerFlight   float64
+
+	BestRoutes  []*FinancePeriodGroupData
+	WorstRoutes []*FinancePeriodGroupData
+	Routes      []*FinancePeriodGroupData
+	Drivers     []*FinancePeriodGroupData
+}
+
+type FinancePeriodReport struct{}
+
+func (r *FinancePeriodReport) Render(data *FinancePeriodReportData) (*ReportData, error) {
+	if data == nil {
+		return nil, errors.New("FinancePeriodReport.Render()", "data is empty")
+	}
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: time.Now().Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	report.Body = &Item{
+		Children: []*Item{
+			{Text: data.Title, Bold: true, Block: true},
+			{Block: true},
+			{Text: "Начало:", Bold: true, Block: true}, {Text: data.DateStart.Format("02.01.2006 15:04")},
+			{Text: "Конец:", Bold: true, Block: true}, {Text: data.DateEnd.Format("02.01.2006 15:04")},
+			{Text: "Дней:", Bold: true, Block: true}, {Text: itoa(data.Days)},
+			{Text: "Рейсы:", Bold: true, Block: true}, {Text: itoa(data.Flights)},
+			{Text: "Незавершенные рейсы:", Bold: true, Block: true}, {Text: itoa(data.FlightsOpened)},
+			{Text: "ШК отгружено:", Bold: true, Block: true}, {Text: itoa(data.BarcodesShipped)},
+			{Text: "Тара:", Bold: true, Block: true}, {Text: itoa(data.Tare)},
+			{Text: "Тара доставлено:", Bold: true, Block: true}, {Text: itoa(data.TareShipped)},
+			{Text: "Тара возврат:", Bold: true, Block: true}, {Text: itoa(data.TareReturned)},
+			{Text: "Задание:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.Income)},
+			{Text: "Возврат:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.IncomeReturn)},
+			{Text: "Штраф:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.Fine)},
+			{Text: "Брак:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р. (%.2f%%)", data.Defect, data.PercentDefect)},
+			{Text: "Налог:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р. (%.2f%%)", data.Tax, data.PercentTax)},
+			{Text: "Ставка:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.SalaryRate)},
+			{Text: "Ставка+:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.ExtendedSalaryRate)},
+			{Text: "Маржа:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.Margin)},
+			{Text: "Расходы:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.Expenses)},
+			{Text: "Итого:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.TotalMargin)},
+			{Block: true},
+			{Text: "В СРЕДНЕМ ЗА РЕЙС", Bold: true, Block: true},
+			{Text: "ШК:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.1f", data.AverageBarcodesPerFlight)},
+			{Text: "Задание:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.AverageIncomePerFlight)},
+			{Text: "Маржа:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f р.", data.AverageMarginPerFlight)},
+			{Block: true},
+		},
+	}
+
+	r.renderRoutes(report.Body, "ЛУЧШИЕ МАРШРУТЫ ПО МАРЖЕ", data.BestRoutes)
+	r.renderRoutes(report.Body, "ХУДШИЕ МАРШРУТЫ ПО МАРЖЕ", data.WorstRoutes)
+	r.renderRoutes(report.Body, "МАРШРУТЫ", data.Routes)
+	r.renderDrivers(report.Body, "ВОДИТЕЛИ", data.Drivers)
+
+	return report, nil
+}
+
+func (r *FinancePeriodReport) renderRoutes(body *Item, title string, routes []*FinancePeriodGroupData) {
+	if len(routes) == 0 {
+		return
+	}
+
+	body.AddChild(&Item{Text: title, Bold: true, Block: true})
+	list := &Item{Block: true, Children: make([]*Item, 0, len(routes)*6)}
+	for _, route := range routes {
+		if route == nil {
+			continue
+		}
+		list.Children = append(list.Children,
+			&Item{Text: "Маршрут " + itoa(route.RouteID) + ":", Bold: true, Block: true},
+			&Item{Text: "рейсы " + itoa(route.Flights)},
+			&Item{Text: "ШК " + itoa(route.BarcodesShipped)},
+			&Item{Text: fmt.Sprintf("задание %.2f р.", route.Income)},
+			&Item{Text: fmt.Sprintf("маржа %.2f р.", route.Margin)},
+			&Item{Text: fmt.Sprintf("за рейс %.2f р.", route.MarginPerFlight)},
+		)
+	}
+	body.AddChild(list)
+	body.AddChild(&Item{Block: true})
+}
+
+func (r *FinancePeriodReport) renderDrivers(body *Item, title string, drivers []*FinancePeriodGroupData) {
+	if len(drivers) == 0 {
+		return
+	}
+
+	body.AddChild(&Item{Text: title, Bold: true, Block: true})
+	list := &Item{Block: true, Children: make([]*Item, 0, len(drivers)*6)}
+	for _, driver := range drivers {
+		if driver == nil {
+			continue
+		}
+		list.Children = append(list.Children,
+			&Item{Text: driver.DriverName + ":", Bold: true, Block: true},
+			&Item{Text: "рейсы " + itoa(driver.Flights)},
+			&Item{Text: "ШК " + itoa(driver.BarcodesShipped)},
+			&Item{Text: fmt.Sprintf("штраф %.2f р.", driver.Fine)},
+			&Item{Text: fmt.Sprintf("маржа %.2f р.", driver.Margin)},
+			&Item{Text: fmt.Sprintf("за рейс %.2f р.", driver.MarginPerFlight)},
+		)
+	}
+	body.AddChild(list)
+	body.AddChild(&Item{Block: true})
+}
@@ -0,0 +1,60 @@
+package reports
+
+import (
+	"fmt"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+// RatingDropData Drop of one rating component of the route within the window
+type RatingDropData struct {
+	RouteID   int
+	Component string
+	From      float64
+	To        float64
+	FromTime  time.Time
+	ToTime    time.Time
+}
+
+type RatingAlertReportData struct {
+	Threshold float64
+	Drops     []*RatingDropData
+}
+
+type RatingAlertReport struct{}
+
+func (r *RatingAlertReport) Render(data *RatingAlertReportData) (*ReportData, error) {
+	if data == nil || len(data.Drops) == 0 {
+		return nil, errors.New("RatingAlertReport.Render()", "data is empty")
+	}
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: time.Now().Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	report.Body = &Item{
+		Children: []*Item{
+			{Text: "ПАДЕНИЕ РЕЙТИНГА", Bold: true, Block: true},
+			{Text: fmt.Sprintf("Порог: %.2f", data.Threshold), Block: true},
+			{Block: true},
+		},
+	}
+
+	for _, drop := range data.Drops {
+		if drop == nil {
+			continue
+		}
+		report.Body.AddChild(&Item{Block: true, Children: []*Item{
+			{Text: "Маршрут " + itoa(drop.RouteID) + ":", Bold: true, Block: true},
+			{Text: drop.Component},
+			{Text: fmt.Sprintf("%.2f → %.2f (-%.2f)", drop.From, drop.To, drop.From-drop.To)},
+			{Text: "с " + drop.FromTime.Format("02.01 15:04")},
+		}})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,126 @@
+package reports
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+type RoutesStatusReportData struct {
+	Time   time.Time
+	Routes []*GeneralRoutesReportData
+}
+
+// RoutesStatusReport Short summary of all routes of the last general routes report
+type RoutesStatusReport struct{}
+
+func (r *RoutesStatusReport) Render(data *RoutesStatusReportData) (*ReportData, error) {
+	if data == nil || len(data.Routes) == 0 {
+		return nil, errors.New("RoutesStatusReport.Render()", "data is empty")
+	}
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: data.Time.Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	report.Body = &Item{
+		Children: []*Item{
+			{Text: "МАРШРУТЫ", Bold: true, Block: true},
+			{Text: "Маршрутов:", Bold: true, Block: true}, {Text: itoa(len(data.Routes))},
+			{Block: true},
+		},
+	}
+
+	routes := make([]*GeneralRoutesReportData, len(data.Routes))
+	copy(routes, data.Routes)
+	sort.Slice(routes, func(i, j int) bool { return routes[i].RouteID < routes[j].RouteID })
+
+	list := &Item{Code: true, Block: true, Children: make([]*Item, 0, len(routes))}
+	for _, route := range routes {
+		if route == nil {
+			continue
+		}
+		list.AddChild(&Item{Block: true, Text: fmt.Sprintf("%-8d П%-4d ШК %-5d Ост %-5d Р %.2f",
+			route.RouteID, route.Parking, route.Barcodes, route.RemainsBarcodes, route.Rating)})
+	}
+	report.Body.AddChild(list)
+
+	return report, nil
+}
+
+type RouteStatusReportData struct {
+	Time  time.Time
+	Route *GeneralRoutesReportData
+}
+
+// RouteStatusReport Details of one route of the last general routes report
+type RouteStatusReport struct{}
+
+func (r *RouteStatusReport) Render(data *RouteStatusReportData) (*ReportData, error) {
+	if data == nil || data.Route == nil {
+		return nil, errors.New("RouteStatusReport.Render()", "data is empty")
+	}
+	route := data.Route
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: data.Time.Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	report.Body = &Item{
+		Children: []*Item{
+			{Text: "Маршрут:", Bold: true, Block: true}, {Text: itoa(route.RouteID)},
+			{Text: "Парковка:", Bold: true, Block: true}, {Text: itoa(route.Parking)},
+			{Text: "Тара:", Bold: true, Block: true}, {Text: itoa(route.Tares)},
+			{Text: "ШК:", Bold: true, Block: true}, {Text: itoa(route.Barcodes)},
+			{Text: "ШК изменение:", Bold: true, Block: true}, {Text: itoa(route.ChangeBarcodes)},
+			{Text: "ШК остаток:", Bold: true, Block: true}, {Text: itoa(route.RemainsBarcodes)},
+			{Text: "Объем:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.0f / %.0f л (%.1f%%)", route.VolumeLiters, route.VolumeNormativeLiters, route.VolumeNormativeLitersPercent)},
+			{Text: "Рейтинг:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.2f", route.Rating)},
+			{Block: true},
+		},
+	}
+
+	if route.ShipmentID != 0 {
+		report.Body.Children = append(report.Body.Children,
+			&Item{Text: "Отгрузка:", Bold: true, Block: true}, &Item{Text: itoa(route.ShipmentID),
+				Link: "https://logistics.wildberries.ru/external-logistics/shipments-shell/shipments/" + itoa(route.ShipmentID)},
+			&Item{Text: "Открытие:", Bold: true, Block: true}, &Item{Text: r.formatTime(route.ShipmentCreateDate)},
+			&Item{Text: "Закрытие:", Bold: true, Block: true}, &Item{Text: r.formatTime(route.ShipmentCloseDate)},
+			&Item{Block: true},
+		)
+	}
+
+	if route.WaySheetID != 0 {
+		report.Body.Children = append(report.Body.Children,
+			&Item{Text: "Путевой лист:", Bold: true, Block: true}, &Item{Text: itoa(route.WaySheetID),
+				Link: "https://ol.wildberries.ru/#/layout/external-waysheet/" + itoa(route.WaySheetID)},
+			&Item{Text: "Последняя операция:", Bold: true, Block: true}, &Item{Text: r.formatTime(route.WaySheetDateLastOperation)},
+			&Item{Text: "Адреса:", Bold: true, Block: true}, &Item{Text: itoa(route.WaySheetCurrentAddresses) + " / " + itoa(route.WaySheetTotalAddresses)},
+			&Item{Text: "Возврат тары:", Bold: true, Block: true}, &Item{Text: itoa(route.WaySheetCurrentReturnedTares) + " / " + itoa(route.WaySheetTotalReturnedTares)},
+		)
+		if route.WaySheetsInterval != 0 {
+			report.Body.Children = append(report.Body.Children,
+				&Item{Text: "Интервал ПЛ:", Bold: true, Block: true},
+				&Item{Text: fmt.Sprintf("%02d:%02d", int(route.WaySheetsInterval.Hours()), int(route.WaySheetsInterval.Minutes())%60)},
+			)
+		}
+	}
+
+	return report, nil
+}
+
+func (r *RouteStatusReport) formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("02.01 15:04")
+}
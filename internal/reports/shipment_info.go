@@ -0,0 +1,68 @@
+package reports
+
+import (
+	"strings"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+type ShipmentInfoReportData struct {
+	ShipmentID         int
+	RouteID            int
+	WaySheetID         int
+	DateCreate         time.Time
+	DateClose          time.Time
+	DriverName         string
+	VehicleNumberPlate string
+	SupplierName       string
+	SrcOfficeName      string
+	DstOfficeNames     []string
+	Tares              int
+	Barcodes           int
+}
+
+type ShipmentInfoReport struct{}
+
+func (r *ShipmentInfoReport) Render(data *ShipmentInfoReportData) (*ReportData, error) {
+	if data == nil {
+		return nil, errors.New("ShipmentInfoReport.Render()", "data is empty")
+	}
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: time.Now().Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	dateClose := "открыта"
+	if !data.DateClose.IsZero() {
+		dateClose = data.DateClose.Format("02.01.2006 15:04")
+	}
+
+	report.Body = &Item{
+		Children: []*Item{
+			{Text: "Отгрузка:", Bold: true, Block: true}, {Text: itoa(data.ShipmentID),
+				Link: "https://logistics.wildberries.ru/external-logistics/shipments-shell/shipments/" + itoa(data.ShipmentID)},
+			{Text: "Маршрут:", Bold: true, Block: true}, {Text: itoa(data.RouteID)},
+			{Text: "Путевой лист:", Bold: true, Block: true}, {Text: itoa(data.WaySheetID),
+				Link: "https://ol.wildberries.ru/#/layout/external-waysheet/" + itoa(data.WaySheetID)},
+			{Text: "Открытие:", Bold: true, Block: true}, {Text: data.DateCreate.Format("02.01.2006 15:04")},
+			{Text: "Закрытие:", Bold: true, Block: true}, {Text: dateClose},
+			{Text: "Водитель:", Bold: true, Block: true}, {Text: data.DriverName},
+			{Text: "Автомобиль:", Bold: true, Block: true}, {Text: data.VehicleNumberPlate},
+			{Text: "Перевозчик:", Bold: true, Block: true}, {Text: data.SupplierName},
+			{Text: "Склад:", Bold: true, Block: true}, {Text: data.SrcOfficeName},
+			{Text: "Тара:", Bold: true, Block: true}, {Text: itoa(data.Tares)},
+			{Text: "ШК:", Bold: true, Block: true}, {Text: itoa(data.Barcodes)},
+		},
+	}
+
+	if len(data.DstOfficeNames) > 0 {
+		report.Body.AddChild(&Item{Text: "МХ:", Bold: true, Block: true})
+		report.Body.AddChild(&Item{Text: strings.Join(data.DstOfficeNames, ", ")})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,104 @@
+package reports
+
+import (
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+type TaskStatusData struct {
+	Name          string
+	IsRunning     bool
+	LastStart     time.Time
+	LastDuration  time.Duration
+	LastError     string
+	Attempts      int
+	Runs          int
+	NextRun       time.Time
+	IsPaused      bool
+	Interval      time.Duration // 0 for not periodic tasks
+	Schedule      string        // empty for not cron tasks
+	IsCircuitOpen bool          // runs are stopped by the circuit breaker after the failures
+}
+
+type TaskStatusReportData struct {
+	Tasks []*TaskStatusData
+}
+
+type TaskStatusReport struct{}
+
+func (r *TaskStatusReport) Render(data *TaskStatusReportData) (*ReportData, error) {
+	if data == nil {
+		return nil, errors.New("TaskStatusReport.Render()", "data is empty")
+	}
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: time.Now().Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	report.Body = &Item{
+		Children: []*Item{
+			{Text: "СТАТУС", Bold: true, Block: true},
+			{Text: "Задач:", Bold: true, Block: true}, {Text: itoa(len(data.Tasks))},
+			{Block: true},
+		},
+	}
+
+	for _, task := range data.Tasks {
+		if task == nil {
+			continue
+		}
+
+		state := "ожидает"
+		if task.IsRunning {
+			state = "выполняется"
+		} else if task.IsPaused {
+			state = "приостановлена"
+		} else if task.IsCircuitOpen {
+			state = "остановлена после ошибок"
+		}
+
+		item := &Item{Block: true, Children: []*Item{
+			{Text: task.Name, Bold: true, Block: true},
+			{Text: "Состояние:", Bold: true, Block: true}, {Text: state},
+			{Text: "Запусков:", Bold: true, Block: true}, {Text: itoa(task.Runs)},
+		}}
+		if task.Interval > 0 {
+			item.Children = append(item.Children,
+				&Item{Text: "Интервал:", Bold: true, Block: true}, &Item{Text: task.Interval.String()},
+			)
+		}
+		if task.Schedule != "" {
+			item.Children = append(item.Children,
+				&Item{Text: "Расписание:", Bold: true, Block: true}, &Item{Text: task.Schedule},
+			)
+		}
+		if !task.LastStart.IsZero() {
+			item.Children = append(item.Children,
+				&Item{Text: "Последний запуск:", Bold: true, Block: true}, &Item{Text: task.LastStart.Format("02.01 15:04:05")},
+				&Item{Text: "Попытка:", Bold: true, Block: true}, &Item{Text: itoa(task.Attempts)},
+			)
+		}
+		if !task.IsRunning && !task.LastStart.IsZero() {
+			item.Children = append(item.Children,
+				&Item{Text: "Длительность:", Bold: true, Block: true}, &Item{Text: task.LastDuration.Round(time.Millisecond).String()},
+			)
+		}
+		if !task.NextRun.IsZero() && !task.IsPaused {
+			item.Children = append(item.Children,
+				&Item{Text: "Следующий запуск:", Bold: true, Block: true}, &Item{Text: task.NextRun.Format("02.01 15:04:05")},
+			)
+		}
+		if task.LastError != "" {
+			item.Children = append(item.Children,
+				&Item{Text: "Ошибка:", Bold: true, Block: true}, &Item{Text: task.LastError, Code: true},
+			)
+		}
+		report.Body.AddChild(item)
+	}
+
+	return report, nil
+}
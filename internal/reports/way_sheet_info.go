@@ -0,0 +1,88 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+type WaySheetInfoAddressData struct {
+	Name         string
+	Sequence     string
+	SequenceFact string
+}
+
+type WaySheetInfoReportData struct {
+	WaySheetID    int
+	RouteID       string
+	RouteName     string
+	DateOpen      time.Time
+	DateClose     time.Time
+	DriverNames   []string
+	VehicleNumber string
+	Barcodes      int
+	Volume        float64
+	Tares         int
+	TaresReturned int
+	Addresses     []*WaySheetInfoAddressData
+}
+
+type WaySheetInfoReport struct{}
+
+func (r *WaySheetInfoReport) Render(data *WaySheetInfoReportData) (*ReportData, error) {
+	if data == nil {
+		return nil, errors.New("WaySheetInfoReport.Render()", "data is empty")
+	}
+
+	report := NewReportData()
+
+	report.Header = &Item{
+		Children: []*Item{
+			{Text: time.Now().Format("02.01.2006 15:04 -07"), Quote: true},
+		},
+	}
+
+	dateClose := "открыт"
+	if !data.DateClose.IsZero() {
+		dateClose = data.DateClose.Format("02.01.2006 15:04")
+	}
+
+	report.Body = &Item{
+		Children: []*Item{
+			{Text: "Путевой лист:", Bold: true, Block: true}, {Text: itoa(data.WaySheetID),
+				Link: "https://ol.wildberries.ru/#/layout/external-waysheet/" + itoa(data.WaySheetID)},
+			{Text: "Маршрут:", Bold: true, Block: true}, {Text: data.RouteID + " " + data.RouteName},
+			{Text: "Открытие:", Bold: true, Block: true}, {Text: data.DateOpen.Format("02.01.2006 15:04")},
+			{Text: "Закрытие:", Bold: true, Block: true}, {Text: dateClose},
+			{Text: "Водитель:", Bold: true, Block: true}, {Text: strings.Join(data.DriverNames, ", ")},
+			{Text: "Автомобиль:", Bold: true, Block: true}, {Text: data.VehicleNumber},
+			{Text: "ШК:", Bold: true, Block: true}, {Text: itoa(data.Barcodes)},
+			{Text: "Объем:", Bold: true, Block: true}, {Text: fmt.Sprintf("%.1f", data.Volume)},
+			{Text: "Тара:", Bold: true, Block: true}, {Text: itoa(data.Tares)},
+			{Text: "Тара возврат:", Bold: true, Block: true}, {Text: itoa(data.TaresReturned)},
+		},
+	}
+
+	if len(data.Addresses) > 0 {
+		addresses := &Item{
+			HiddenQuote: true,
+			Block:       true,
+			Children:    make([]*Item, 0, len(data.Addresses)),
+		}
+		for _, address := range data.Addresses {
+			if address == nil {
+				continue
+			}
+			status := "ожидает"
+			if address.SequenceFact != "" && address.SequenceFact != "0" {
+				status = "пройден " + address.SequenceFact
+			}
+			addresses.AddChild(&Item{Text: address.Sequence + ") " + address.Name + " - " + status, Block: true})
+		}
+		report.Body.AddChild(&Item{Block: true})
+		report.Body.AddChild(addresses)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // runs are allowed
+	CircuitOpen                         // runs are skipped until the cooldown passes
+	CircuitHalfOpen                     // one trial run is allowed, its result closes or opens the circuit again
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerConfig Stops running the task after the consecutive failed runs, a run fails when all its attempts fail
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // failed runs in a row which open the circuit
+	Cooldown         time.Duration // time in the open state before the trial run
+	// OnStateChange Called on every transition, it must not block
+	OnStateChange func(task Task, from, to CircuitState)
+}
+
+type circuitBreaker struct {
+	mu       sync.Mutex
+	cfg      *CircuitBreakerConfig
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	onChange func(from, to CircuitState)
+}
+
+// newCircuitBreaker Returns nil if the config is nil or disabled, all methods of the nil breaker allow runs
+func newCircuitBreaker(cfg *CircuitBreakerConfig, onChange func(from, to CircuitState)) *circuitBreaker {
+	if cfg == nil || cfg.FailureThreshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{cfg: cfg, onChange: onChange}
+}
+
+func (b *circuitBreaker) State() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// allow Whether the run may start, after the cooldown the open circuit lets the trial run through
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.cfg.Cooldown {
+			return false
+		}
+		b.setState(CircuitHalfOpen)
+		return true
+	case CircuitHalfOpen:
+		return false // the trial run is in progress
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.setState(CircuitClosed)
+}
+
+func (b *circuitBreaker) failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.openedAt = time.Now()
+		b.setState(CircuitOpen)
+	}
+}
+
+// release Returns the half-open circuit to open if the trial run was cancelled before the result
+func (b *circuitBreaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitHalfOpen {
+		b.setState(CircuitOpen)
+	}
+}
+
+func (b *circuitBreaker) setState(state CircuitState) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	if b.onChange != nil {
+		b.onChange(from, state)
+	}
+}
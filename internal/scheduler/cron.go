@@ -0,0 +1,259 @@
+package scheduler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+// Schedule Calendar schedule of the task
+type Schedule interface {
+	// Next Returns the first run time after t, zero if there are no more runs
+	Next(t time.Time) time.Time
+	String() string
+}
+
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// CronSchedule Standard cron expression of 5 fields: minute hour day-of-month month day-of-week.
+// Fields support '*', lists 'a,b', ranges 'a-b' and steps '*/n', 'a-b/n', day of week 0 and 7 are Sunday
+type CronSchedule struct {
+	expr     string
+	minute   uint64 // bit sets of the allowed values
+	hour     uint64
+	dom      uint64
+	month    uint64
+	dow      uint64
+	isDomAll bool
+	isDowAll bool
+	location *time.Location
+}
+
+func ParseCron(expr string, location *time.Location) (*CronSchedule, error) {
+	if location == nil {
+		location = time.Local
+	}
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[spec]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.Newf("scheduler.ParseCron()", "cron expression '%s' must have 5 fields, got %d", expr, len(fields))
+	}
+
+	s := &CronSchedule{expr: expr, location: location}
+	bounds := []struct {
+		set      *uint64
+		min, max int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 7},
+	}
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scheduler.ParseCron()", "invalid cron expression '%s'", expr)
+		}
+		*bounds[i].set = set
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 // 7 is Sunday too
+	}
+	s.isDomAll = fields[2] == "*"
+	s.isDowAll = fields[4] == "*"
+	return s, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, errors.Newf("scheduler.parseCronField()", "invalid step in '%s'", part)
+			}
+			part = part[:i]
+		}
+
+		from, to := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, errors.Newf("scheduler.parseCronField()", "invalid value '%s'", part)
+			}
+			to = from
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, errors.Newf("scheduler.parseCronField()", "invalid range '%s'", part)
+				}
+			} else if step > 1 {
+				to = max // 'a/n' means from a to the end
+			}
+		}
+		if from < min || to > max || from > to {
+			return 0, errors.Newf("scheduler.parseCronField()", "value '%s' is out of range %d-%d", part, min, max)
+		}
+
+		for v := from; v <= to; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.isDayMatch(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// isDayMatch If both day fields are restricted, the day matches either of them, as in cron
+func (s *CronSchedule) isDayMatch(t time.Time) bool {
+	isDom := s.dom&(1<<uint(t.Day())) != 0
+	isDow := s.dow&(1<<uint(t.Weekday())) != 0
+	if !s.isDomAll && !s.isDowAll {
+		return isDom || isDow
+	}
+	return isDom && isDow
+}
+
+func (s *CronSchedule) String() string {
+	return s.expr + " " + s.location.String()
+}
+
+// DailySchedule Runs every day at the times of day
+type DailySchedule struct {
+	times    []string
+	crons    []*CronSchedule
+	location *time.Location
+}
+
+// ParseDaily Times of day are in the format HH:MM
+func ParseDaily(times []string, location *time.Location) (*DailySchedule, error) {
+	if len(times) == 0 {
+		return nil, errors.New("scheduler.ParseDaily()", "times are empty")
+	}
+	if location == nil {
+		location = time.Local
+	}
+
+	s := &DailySchedule{times: times, location: location}
+	for _, at := range times {
+		offset, err := parseTimeOfDay(at)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scheduler.ParseDaily()", "invalid time '%s'", at)
+		}
+		cron, err := ParseCron(strconv.Itoa(int(offset/time.Minute)%60)+" "+strconv.Itoa(int(offset/time.Hour))+" * * *", location)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scheduler.ParseDaily()", "invalid time '%s'", at)
+		}
+		s.crons = append(s.crons, cron)
+	}
+	return s, nil
+}
+
+func (s *DailySchedule) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, cron := range s.crons {
+		if n := cron.Next(t); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+func (s *DailySchedule) String() string {
+	return strings.Join(s.times, ", ") + " " + s.location.String()
+}
+
+// TimeWindow Time of day interval in which the task is allowed to run, it may cross midnight, e.g. 22:00-06:00
+type TimeWindow struct {
+	from     time.Duration // offset from midnight
+	to       time.Duration
+	location *time.Location
+}
+
+// ParseTimeWindow Times of day are in the format HH:MM, the window includes 'from' and excludes 'to'
+func ParseTimeWindow(from, to string, location *time.Location) (*TimeWindow, error) {
+	if location == nil {
+		location = time.Local
+	}
+	fromOffset, err := parseTimeOfDay(from)
+	if err != nil {
+		return nil, errors.Wrap(err, "scheduler.ParseTimeWindow()", "invalid 'from'")
+	}
+	toOffset, err := parseTimeOfDay(to)
+	if err != nil {
+		return nil, errors.Wrap(err, "scheduler.ParseTimeWindow()", "invalid 'to'")
+	}
+	return &TimeWindow{from: fromOffset, to: toOffset, location: location}, nil
+}
+
+// Contains The nil window contains any time
+func (w *TimeWindow) Contains(t time.Time) bool {
+	if w == nil || w.from == w.to {
+		return true
+	}
+	t = t.In(w.location)
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.from < w.to {
+		return offset >= w.from && offset < w.to
+	}
+	return offset >= w.from || offset < w.to
+}
+
+// Start Returns the opening time of the window period which contains t, zero if the window is nil or always open
+func (w *TimeWindow) Start(t time.Time) time.Time {
+	if w == nil || w.from == w.to {
+		return time.Time{}
+	}
+	t = t.In(w.location)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, w.location).Add(w.from)
+	if start.After(t) {
+		start = start.AddDate(0, 0, -1) // the window crosses midnight and was opened yesterday
+	}
+	return start
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, errors.Newf("scheduler.parseTimeOfDay()", "invalid time of day '%s', format is HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
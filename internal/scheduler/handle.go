@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// scheduledTask Handle of the scheduled task for the per-task control
+type scheduledTask struct {
+	task       Task
+	cfg        TaskConfig
+	ctx        context.Context
+	cancel     context.CancelFunc
+	isPeriodic bool     // periodic or cron task
+	schedule   Schedule // only cron tasks
+	breaker    *circuitBreaker
+
+	mu       sync.Mutex
+	interval time.Duration // only periodic tasks
+	isPaused bool          // only periodic and cron tasks
+	nextRun  time.Time
+
+	trigger        chan struct{} // run out of turn, nil for the tasks which are not waiting
+	intervalUpdate chan struct{} // interval has been changed, only periodic tasks
+}
+
+func (t *scheduledTask) Interval() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.interval
+}
+
+func (t *scheduledTask) IsPaused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.isPaused
+}
+
+func (t *scheduledTask) NextRun() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.nextRun
+}
+
+func (t *scheduledTask) setPaused(isPaused bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.isPaused = isPaused
+}
+
+func (t *scheduledTask) setNextRun(nextRun time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextRun = nextRun
+}
+
+func (t *scheduledTask) setInterval(interval time.Duration) {
+	t.mu.Lock()
+	t.interval = interval
+	t.mu.Unlock()
+	notify(t.intervalUpdate)
+}
+
+func (t *scheduledTask) triggerNow() {
+	notify(t.trigger)
+}
+
+// notify Non-blocking send, repeated signals are merged into one
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+	"wb_logistic_assistant/internal/errors"
+)
+
+// RetryPolicy Delays between the attempts of the task run and the errors worth retrying
+type RetryPolicy interface {
+	// Backoff Returns the delay after the failed attempt, attempts start from 1
+	Backoff(attempt int) time.Duration
+	IsRetryable(err error) bool
+}
+
+var defaultRetryPolicy = DefaultRetryPolicy()
+
+// ExponentialRetryPolicy Backoff grows by the multiplier from the base up to the max and deviates randomly by the jitter
+type ExponentialRetryPolicy struct {
+	BaseBackoff time.Duration        // delay after the first attempt
+	MaxBackoff  time.Duration        // if 0, no limit
+	Multiplier  float64              // if < 1, 2 is used
+	Jitter      float64              // fraction of the random deviation from 0 to 1
+	Retryable   func(err error) bool // if nil, any error except the cancellation is retryable
+}
+
+// DefaultRetryPolicy 2s, 4s, 8s... up to 1m with 20% jitter
+func DefaultRetryPolicy() *ExponentialRetryPolicy {
+	return &ExponentialRetryPolicy{
+		BaseBackoff: 2 * time.Second,
+		MaxBackoff:  time.Minute,
+		Multiplier:  2,
+		Jitter:      0.2,
+	}
+}
+
+func (p *ExponentialRetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	backoff := float64(p.BaseBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		backoff *= 1 + jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(backoff)
+}
+
+func (p *ExponentialRetryPolicy) IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return true
+}
@@ -2,19 +2,32 @@ package scheduler
 
 import (
 	"context"
-	"math"
 	"sync"
 	"sync/atomic"
 	"time"
 	"wb_logistic_assistant/internal/errors"
 	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/metrics"
 )
 
 type Scheduler interface {
-	ScheduleNow(task Task, cfg ...TaskConfig)
-	ScheduleAsync(task Task, cfg ...TaskConfig)
-	ScheduleAfter(task Task, delay time.Duration, cfg ...TaskConfig)
-	SchedulePeriodic(task Task, interval time.Duration, cfg ...TaskConfig)
+	// ScheduleNow, ScheduleAsync, ScheduleAfter and SchedulePeriodic return the id of the task for the per-task control
+	ScheduleNow(task Task, cfg ...TaskConfig) uint64
+	ScheduleAsync(task Task, cfg ...TaskConfig) uint64
+	ScheduleAfter(task Task, delay time.Duration, cfg ...TaskConfig) uint64
+	SchedulePeriodic(task Task, interval time.Duration, cfg ...TaskConfig) uint64
+	// ScheduleCron Runs the task at the times of the calendar schedule
+	ScheduleCron(task Task, schedule Schedule, cfg ...TaskConfig) uint64
+	// Cancel Unschedules the task, the running execution gets the cancelled context
+	Cancel(id uint64) error
+	// Pause and Resume are applicable only to the periodic and cron tasks, UpdateInterval only to the periodic ones
+	Pause(id uint64) error
+	Resume(id uint64) error
+	// TriggerNow Runs the periodic or the delayed task out of turn, paused tasks are run too
+	TriggerNow(id uint64) error
+	UpdateInterval(id uint64, interval time.Duration) error
+	// List Returns states of the scheduled tasks ordered by task id
+	List() []TaskState
 	Reset()
 }
 
@@ -25,6 +38,9 @@ type BaseScheduler struct {
 	wg                    sync.WaitGroup
 	workerPool            chan struct{}
 	defaultRetryTaskLimit int
+	states                *taskStates
+	tasksMtx              sync.Mutex
+	tasks                 map[uint64]*scheduledTask // task id -> handle, until the task is done or cancelled
 }
 
 func NewBaseScheduler(maxWorkers, defaultRetryTaskLimit int) *BaseScheduler {
@@ -37,102 +53,333 @@ func NewBaseScheduler(maxWorkers, defaultRetryTaskLimit int) *BaseScheduler {
 		cancelFunc:            cancel,
 		workerPool:            make(chan struct{}, maxWorkers),
 		defaultRetryTaskLimit: defaultRetryTaskLimit,
+		states:                newTaskStates(),
+		tasks:                 map[uint64]*scheduledTask{},
 	}
 }
 
-func (s *BaseScheduler) ScheduleNow(task Task, cfg ...TaskConfig) {
-	s.runTaskAsync(task, cfgOrDefault(cfg), "Now")
+func (s *BaseScheduler) ScheduleNow(task Task, cfg ...TaskConfig) uint64 {
+	st := s.add(task, cfgOrDefault(cfg))
+	s.runOnceAsync(st, "Now")
+	return task.ID()
 }
 
-func (s *BaseScheduler) ScheduleAsync(task Task, cfg ...TaskConfig) {
+func (s *BaseScheduler) ScheduleAsync(task Task, cfg ...TaskConfig) uint64 {
+	st := s.add(task, cfgOrDefault(cfg))
 	safeRunGoroutine(func() {
-		s.runTaskAsync(task, cfgOrDefault(cfg), "Async")
+		s.runOnceAsync(st, "Async")
 	})
+	return task.ID()
 }
 
-func (s *BaseScheduler) ScheduleAfter(task Task, delay time.Duration, cfg ...TaskConfig) {
+func (s *BaseScheduler) ScheduleAfter(task Task, delay time.Duration, cfg ...TaskConfig) uint64 {
+	st := s.add(task, cfgOrDefault(cfg))
+	st.trigger = make(chan struct{}, 1)
+	st.setNextRun(time.Now().Add(delay))
 	safeRunGoroutine(func() {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
 		select {
-		case <-time.After(delay):
-			s.runTaskAsync(task, cfgOrDefault(cfg), "After")
-		case <-s.ctx.Done():
+		case <-timer.C:
+			s.runOnceAsync(st, "After")
+		case <-st.trigger:
+			s.runOnceAsync(st, "Trigger")
+		case <-st.ctx.Done():
 		}
 	})
+	return task.ID()
 }
 
-func (s *BaseScheduler) SchedulePeriodic(task Task, interval time.Duration, cfg ...TaskConfig) {
-	config := cfgOrDefault(cfg)
-	if config.IsIntervalAfterFinish {
-		s.schedulePeriodicSequential(task, interval, config)
+func (s *BaseScheduler) SchedulePeriodic(task Task, interval time.Duration, cfg ...TaskConfig) uint64 {
+	st := s.add(task, cfgOrDefault(cfg))
+	st.isPeriodic = true
+	st.interval = interval
+	st.trigger = make(chan struct{}, 1)
+	st.intervalUpdate = make(chan struct{}, 1)
+
+	if st.cfg.IsIntervalAfterFinish {
+		s.schedulePeriodicSequential(st)
 	} else {
-		s.schedulePeriodicTicker(task, interval, config)
+		s.schedulePeriodicTicker(st)
 	}
+	return task.ID()
 }
 
 // fixed interval mode
-func (s *BaseScheduler) schedulePeriodicTicker(task Task, interval time.Duration, cfg TaskConfig) {
+func (s *BaseScheduler) schedulePeriodicTicker(st *scheduledTask) {
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		ticker := time.NewTicker(interval)
+		ticker := time.NewTicker(st.Interval())
 		defer ticker.Stop()
+		st.setNextRun(time.Now().Add(st.Interval()))
 
-		var mtx sync.Mutex
-		var isRunning bool
+		run := s.periodicRunner(st)
 
 		for {
 			select {
-			case <-s.ctx.Done():
+			case <-st.ctx.Done():
 				return
-			case <-ticker.C:
-				if cfg.IsWaitForPrevious {
-					mtx.Lock()
-					if isRunning {
-						mtx.Unlock()
-						continue
-					}
-					isRunning = true
-					mtx.Unlock()
-
-					s.wg.Add(1)
-					go func() {
-						defer s.wg.Done()
-						s.runTask(task, cfg, "Periodic")
-						mtx.Lock()
-						isRunning = false
-						mtx.Unlock()
-					}()
-				} else {
-					s.runTaskAsync(task, cfg, "Periodic")
+			case <-st.intervalUpdate:
+				ticker.Reset(st.Interval())
+				st.setNextRun(time.Now().Add(st.Interval()))
+			case <-st.trigger:
+				run("Trigger")
+			case now := <-ticker.C:
+				st.setNextRun(now.Add(st.Interval()))
+				if st.IsPaused() || !st.cfg.Window.Contains(now) {
+					continue
 				}
+				run("Periodic")
 			}
 		}
 	}()
 }
 
 // "interval after completion" mode
-func (s *BaseScheduler) schedulePeriodicSequential(task Task, interval time.Duration, cfg TaskConfig) {
+func (s *BaseScheduler) schedulePeriodicSequential(st *scheduledTask) {
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
+		isTriggered := false
 		for {
 			select {
-			case <-s.ctx.Done():
+			case <-st.ctx.Done():
 				return
 			default:
-				s.runTask(task, cfg, "PeriodicSeq")
+				if isTriggered {
+					s.runTask(st, "Trigger")
+				} else if !st.IsPaused() && st.cfg.Window.Contains(time.Now()) {
+					s.runTask(st, "PeriodicSeq")
+				}
 
-				timer := time.NewTimer(interval)
-				select {
-				case <-timer.C:
-				case <-s.ctx.Done():
-					timer.Stop()
+				var ok bool
+				if isTriggered, ok = s.waitInterval(st); !ok {
 					return
 				}
+			}
+		}
+	}()
+}
+
+// ScheduleCron Skips the runs outside the window of the task config too
+func (s *BaseScheduler) ScheduleCron(task Task, schedule Schedule, cfg ...TaskConfig) uint64 {
+	st := s.add(task, cfgOrDefault(cfg))
+	st.isPeriodic = true
+	st.schedule = schedule
+	st.trigger = make(chan struct{}, 1)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		run := s.periodicRunner(st)
+
+		for {
+			next := schedule.Next(time.Now())
+			if next.IsZero() {
+				logger.Logf(logger.WARN, "BaseScheduler.ScheduleCron()", "task %d '%s' has no more runs in schedule %s", task.ID(), task.Name(), schedule)
+				st.setNextRun(time.Time{})
+				<-st.ctx.Done() // stays in the list, it may be triggered or cancelled
+				return
+			}
+			st.setNextRun(next)
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-st.ctx.Done():
+				timer.Stop()
+				return
+			case <-st.trigger:
 				timer.Stop()
+				run("Trigger")
+			case now := <-timer.C:
+				if st.IsPaused() || !st.cfg.Window.Contains(now) {
+					continue
+				}
+				run("Cron")
 			}
 		}
 	}()
+	return task.ID()
+}
+
+// periodicRunner Returns the function which runs the periodic task asynchronously,
+// with IsWaitForPrevious the run is skipped while the previous one is not completed
+func (s *BaseScheduler) periodicRunner(st *scheduledTask) func(source string) {
+	var mtx sync.Mutex
+	var isRunning bool
+
+	return func(source string) {
+		if !st.cfg.IsWaitForPrevious {
+			s.runTaskAsync(st, source)
+			return
+		}
+
+		mtx.Lock()
+		if isRunning {
+			mtx.Unlock()
+			return
+		}
+		isRunning = true
+		mtx.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runTask(st, source)
+			mtx.Lock()
+			isRunning = false
+			mtx.Unlock()
+		}()
+	}
+}
+
+// waitInterval Waits for the interval of the task or its trigger, returns false if the task was cancelled
+func (s *BaseScheduler) waitInterval(st *scheduledTask) (isTriggered bool, ok bool) {
+	for {
+		timer := time.NewTimer(st.Interval())
+		st.setNextRun(time.Now().Add(st.Interval()))
+		select {
+		case <-timer.C:
+			return false, true
+		case <-st.trigger:
+			timer.Stop()
+			return true, true
+		case <-st.intervalUpdate:
+			timer.Stop() // the countdown starts over with the new interval
+		case <-st.ctx.Done():
+			timer.Stop()
+			return false, false
+		}
+	}
+}
+
+func (s *BaseScheduler) Cancel(id uint64) error {
+	s.tasksMtx.Lock()
+	st, ok := s.tasks[id]
+	delete(s.tasks, id)
+	s.tasksMtx.Unlock()
+	if !ok {
+		return errors.Newf("BaseScheduler.Cancel()", "task %d not found", id)
+	}
+
+	st.cancel()
+	s.states.remove(id)
+	logger.Logf(logger.INFO, "BaseScheduler.Cancel()", "task %d '%s' cancelled", id, st.task.Name())
+	return nil
+}
+
+// Pause Skips the periodic runs of the task until Resume
+func (s *BaseScheduler) Pause(id uint64) error {
+	st, err := s.periodic(id)
+	if err != nil {
+		return errors.Wrap(err, "BaseScheduler.Pause()", "")
+	}
+	st.setPaused(true)
+	logger.Logf(logger.INFO, "BaseScheduler.Pause()", "task %d '%s' paused", id, st.task.Name())
+	return nil
+}
+
+func (s *BaseScheduler) Resume(id uint64) error {
+	st, err := s.periodic(id)
+	if err != nil {
+		return errors.Wrap(err, "BaseScheduler.Resume()", "")
+	}
+	st.setPaused(false)
+	logger.Logf(logger.INFO, "BaseScheduler.Resume()", "task %d '%s' resumed", id, st.task.Name())
+	return nil
+}
+
+func (s *BaseScheduler) TriggerNow(id uint64) error {
+	st, ok := s.get(id)
+	if !ok {
+		return errors.Newf("BaseScheduler.TriggerNow()", "task %d not found", id)
+	}
+	if st.trigger == nil {
+		return errors.Newf("BaseScheduler.TriggerNow()", "task %d '%s' is not waiting for the run", id, st.task.Name())
+	}
+	st.triggerNow()
+	logger.Logf(logger.INFO, "BaseScheduler.TriggerNow()", "task %d '%s' triggered", id, st.task.Name())
+	return nil
+}
+
+// UpdateInterval Changes the interval of the periodic task, the countdown starts over
+func (s *BaseScheduler) UpdateInterval(id uint64, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.Newf("BaseScheduler.UpdateInterval()", "interval must be positive, got %v", interval)
+	}
+	st, err := s.periodic(id)
+	if err != nil {
+		return errors.Wrap(err, "BaseScheduler.UpdateInterval()", "")
+	}
+	if st.schedule != nil {
+		return errors.Newf("BaseScheduler.UpdateInterval()", "task %d '%s' runs by schedule %s", id, st.task.Name(), st.schedule)
+	}
+	st.setInterval(interval)
+	logger.Logf(logger.INFO, "BaseScheduler.UpdateInterval()", "task %d '%s' interval changed to %v", id, st.task.Name(), interval)
+	return nil
+}
+
+func (s *BaseScheduler) List() []TaskState {
+	list := s.states.list()
+	s.tasksMtx.Lock()
+	defer s.tasksMtx.Unlock()
+	for i := range list {
+		if st, ok := s.tasks[list[i].ID]; ok {
+			list[i].IsPaused = st.IsPaused()
+			list[i].Interval = st.Interval()
+			list[i].NextRun = st.NextRun()
+			list[i].IsPeriodic = st.isPeriodic
+			list[i].Circuit = st.breaker.State()
+			if st.schedule != nil {
+				list[i].Schedule = st.schedule.String()
+			}
+		}
+	}
+	return list
+}
+
+// add Registers the handle and the state of the task, the context of the task is derived from the scheduler one
+func (s *BaseScheduler) add(task Task, cfg TaskConfig) *scheduledTask {
+	ctx, cancel := context.WithCancel(s.ctx)
+	st := &scheduledTask{task: task, cfg: cfg, ctx: ctx, cancel: cancel}
+	st.breaker = newCircuitBreaker(cfg.CircuitBreaker, func(from, to CircuitState) {
+		s.onCircuitStateChange(st, from, to)
+	})
+
+	s.states.register(task)
+	s.tasksMtx.Lock()
+	s.tasks[task.ID()] = st
+	s.tasksMtx.Unlock()
+	return st
+}
+
+// done Removes the handle of the completed one-time task, its state stays in the list
+func (s *BaseScheduler) done(st *scheduledTask) {
+	s.tasksMtx.Lock()
+	if s.tasks[st.task.ID()] == st {
+		delete(s.tasks, st.task.ID())
+	}
+	s.tasksMtx.Unlock()
+	st.cancel()
+}
+
+func (s *BaseScheduler) get(id uint64) (*scheduledTask, bool) {
+	s.tasksMtx.Lock()
+	defer s.tasksMtx.Unlock()
+	st, ok := s.tasks[id]
+	return st, ok
+}
+
+func (s *BaseScheduler) periodic(id uint64) (*scheduledTask, error) {
+	st, ok := s.get(id)
+	if !ok {
+		return nil, errors.Newf("BaseScheduler.periodic()", "task %d not found", id)
+	}
+	if !st.isPeriodic {
+		return nil, errors.Newf("BaseScheduler.periodic()", "task %d '%s' is not periodic", id, st.task.Name())
+	}
+	return st, nil
 }
 
 func (s *BaseScheduler) Reset() {
@@ -147,97 +394,159 @@ func (s *BaseScheduler) Reset() {
 		s.ctx = newCtx
 		s.cancelFunc = newCancel
 
+		s.states.clear()
+		s.tasksMtx.Lock()
+		clear(s.tasks)
+		s.tasksMtx.Unlock()
 		s.isCanceled.Store(false)
 
 		logger.Log(logger.INFO, "BaseScheduler.Reset()", "scheduler context reset")
 	}
 }
 
-func (s *BaseScheduler) runTask(task Task, cfg TaskConfig, source string) {
+func (s *BaseScheduler) runTask(st *scheduledTask, source string) {
 	s.wg.Add(1)
 	defer s.wg.Done()
 
 	select {
 	case s.workerPool <- struct{}{}:
 		defer func() { <-s.workerPool }()
-		s.executeWithRetry(task, cfg, source)
-	case <-s.ctx.Done():
-		logger.Logf(logger.ERROR, "BaseScheduler.runTask()", "%s task %d '%s' skipped due to shutdown", source, task.ID(), task.Name())
+		s.executeWithRetry(st, source)
+	case <-st.ctx.Done():
+		logger.Logf(logger.ERROR, "BaseScheduler.runTask()", "%s task %d '%s' skipped due to shutdown", source, st.task.ID(), st.task.Name())
 	}
 }
 
-func (s *BaseScheduler) runTaskAsync(task Task, cfg TaskConfig, source string) {
+func (s *BaseScheduler) runTaskAsync(st *scheduledTask, source string) {
 	s.wg.Add(1)
 	safeRunGoroutine(func() {
 		defer s.wg.Done()
+		s.runTaskPooled(st, source)
+	})
+}
 
-		select {
-		case s.workerPool <- struct{}{}:
-			defer func() { <-s.workerPool }()
-			s.executeWithRetry(task, cfg, source)
-		case <-s.ctx.Done():
-			logger.Logf(logger.ERROR, "BaseScheduler.runTaskAsync()", "%s task '%s' skipped due to shutdown, because scheduler has been cancelled", source, task.Name())
-		}
+// runOnceAsync Runs the one-time task and releases its handle
+func (s *BaseScheduler) runOnceAsync(st *scheduledTask, source string) {
+	s.wg.Add(1)
+	safeRunGoroutine(func() {
+		defer s.wg.Done()
+		defer s.done(st)
+		s.runTaskPooled(st, source)
 	})
 }
 
-func (s *BaseScheduler) executeWithRetry(task Task, cfg TaskConfig, source string) {
+func (s *BaseScheduler) runTaskPooled(st *scheduledTask, source string) {
+	select {
+	case s.workerPool <- struct{}{}:
+		defer func() { <-s.workerPool }()
+		s.executeWithRetry(st, source)
+	case <-st.ctx.Done():
+		logger.Logf(logger.ERROR, "BaseScheduler.runTaskAsync()", "%s task '%s' skipped due to shutdown, because scheduler has been cancelled", source, st.task.Name())
+	}
+}
+
+func (s *BaseScheduler) executeWithRetry(st *scheduledTask, source string) {
+	task, cfg := st.task, st.cfg
 	retries := cfg.RetryTaskLimit
 	if retries <= 0 {
 		retries = s.defaultRetryTaskLimit
 	}
+	policy := cfg.RetryPolicy
+	if policy == nil {
+		policy = defaultRetryPolicy
+	}
+
+	log := logger.With(logger.Task(task.Name()), logger.TaskID(task.ID()))
+
+	if !st.breaker.allow() {
+		log.Logf(logger.WARN, "BaseScheduler", "%s task %d '%s' skipped, circuit breaker is open", source, task.ID(), task.Name())
+		return
+	}
 
 	for attempt := 1; attempt <= retries; attempt++ {
-		if s.ctx.Err() != nil {
-			logger.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' cancelled before start", source, task.ID(), task.Name())
+		if st.ctx.Err() != nil {
+			log.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' cancelled before start", source, task.ID(), task.Name())
+			st.breaker.release()
 			return
 		}
 
 		start := time.Now()
-		logger.Logf(logger.INFO, "BaseScheduler", "%s task %d '%s' started (attempt %d)", source, task.ID(), task.Name(), attempt)
+		log.Logf(logger.INFO, "BaseScheduler", "%s task %d '%s' started (attempt %d)", source, task.ID(), task.Name(), attempt)
 
-		runCtx := s.ctx
+		runCtx := st.ctx
 		var cancel context.CancelFunc
 		if cfg.Timeout > 0 {
-			runCtx, cancel = context.WithTimeout(s.ctx, cfg.Timeout)
+			runCtx, cancel = context.WithTimeout(st.ctx, cfg.Timeout)
 		}
 
+		s.states.start(task, attempt)
 		err := safeRunTask(task, runCtx)
+		s.states.finish(task, err)
+		metrics.SchedulerTaskRuns.With(task.Name()).Inc()
+		metrics.SchedulerTaskDuration.With(task.Name()).Observe(metrics.Since(start))
 
 		if cancel != nil {
 			cancel()
 		}
 
 		if err == nil {
-			logger.Logf(logger.INFO, "BaseScheduler", "%s task %d '%s' finished in %v", source, task.ID(), task.Name(), time.Since(start))
+			log.Logf(logger.INFO, "BaseScheduler", "%s task %d '%s' finished in %v", source, task.ID(), task.Name(), time.Since(start))
+			st.breaker.success()
 			return
 		}
 
-		if errors.Is(err, context.Canceled) {
-			logger.Logf(logger.INFO, "BaseScheduler", "%s task %d '%s' cancelled", source, task.ID(), task.Name())
+		// the task or the whole scheduler was cancelled, it is not a failure of the task
+		if st.ctx.Err() != nil {
+			log.Logf(logger.INFO, "BaseScheduler", "%s task %d '%s' cancelled", source, task.ID(), task.Name())
+			st.breaker.release()
 			return
 		}
+		metrics.SchedulerTaskFailures.With(task.Name()).Inc()
 
 		if errors.Is(err, context.DeadlineExceeded) {
-			logger.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' timed out", source, task.ID(), task.Name())
-			return
+			log.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' timed out at attempt %d", source, task.ID(), task.Name(), attempt)
+		} else {
+			log.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' failed attempt %d: %v", source, task.ID(), task.Name(), attempt, err)
 		}
 
-		logger.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' failed attempt %d: %v", source, task.ID(), task.Name(), attempt, err)
+		if !policy.IsRetryable(err) {
+			log.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' failed with not retryable error", source, task.ID(), task.Name())
+			st.breaker.failure()
+			return
+		}
 
 		if attempt == retries {
-			logger.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' permanently failed after %d attempts", source, task.ID(), task.Name(), attempt)
+			log.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' permanently failed after %d attempts", source, task.ID(), task.Name(), attempt)
+			st.breaker.failure()
 			return
 		}
 
-		backoff := time.Duration(math.Pow(2, float64(attempt))) * time.Second
-		logger.Logf(logger.INFO, "BaseScheduler", "%s task %d '%s' retrying in %v...", source, task.ID(), task.Name(), backoff)
+		backoff := policy.Backoff(attempt)
+		log.Logf(logger.INFO, "BaseScheduler", "%s task %d '%s' retrying in %v...", source, task.ID(), task.Name(), backoff.Round(time.Millisecond))
 
+		timer := time.NewTimer(backoff)
 		select {
-		case <-time.After(backoff):
-		case <-s.ctx.Done():
-			logger.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' retry aborted by cancellation", source, task.ID(), task.Name())
+		case <-timer.C:
+		case <-st.ctx.Done():
+			timer.Stop()
+			log.Logf(logger.ERROR, "BaseScheduler", "%s task %d '%s' retry aborted by cancellation", source, task.ID(), task.Name())
+			st.breaker.release()
 			return
 		}
 	}
 }
+
+// onCircuitStateChange Logs the transition and calls the hook of the task config apart from the breaker lock
+func (s *BaseScheduler) onCircuitStateChange(st *scheduledTask, from, to CircuitState) {
+	level := logger.WARN
+	if to == CircuitClosed {
+		level = logger.INFO
+	}
+	logger.Logf(level, "BaseScheduler", "task %d '%s' circuit breaker %s -> %s", st.task.ID(), st.task.Name(), from, to)
+
+	if hook := st.cfg.CircuitBreaker.OnStateChange; hook != nil {
+		safeRunGoroutine(func() {
+			hook(st.task, from, to)
+		})
+	}
+}
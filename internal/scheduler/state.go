@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// TaskState State of the scheduled task at the moment of the call
+type TaskState struct {
+	ID           uint64
+	Name         string
+	IsRunning    bool
+	LastStart    time.Time
+	LastDuration time.Duration
+	LastError    error
+	LastSuccess  time.Time // finish of the last successful attempt, zero if there was none
+	ScheduledAt  time.Time
+	Attempts     int // attempts of the last run
+	Runs         int
+	NextRun      time.Time     // zero if the task is not waiting for the run
+	IsPeriodic   bool          // periodic or cron task
+	IsPaused     bool          // only periodic and cron tasks
+	Interval     time.Duration // only periodic tasks
+	Schedule     string        // only cron tasks
+	Circuit      CircuitState  // closed if the task has no circuit breaker
+}
+
+type taskStates struct {
+	mu     sync.Mutex
+	states map[uint64]*TaskState // task id -> state
+}
+
+func newTaskStates() *taskStates {
+	return &taskStates{states: map[uint64]*TaskState{}}
+}
+
+func (s *taskStates) register(task Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.states[task.ID()]; !ok {
+		s.states[task.ID()] = &TaskState{ID: task.ID(), Name: task.Name(), ScheduledAt: time.Now()}
+	}
+}
+
+func (s *taskStates) start(task Task, attempt int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[task.ID()]
+	if !ok {
+		return // cancelled
+	}
+	state.IsRunning = true
+	state.LastStart = time.Now()
+	state.Attempts = attempt
+	if attempt == 1 {
+		state.Runs++
+	}
+}
+
+func (s *taskStates) finish(task Task, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[task.ID()]
+	if !ok {
+		return // cancelled
+	}
+	state.IsRunning = false
+	state.LastDuration = time.Since(state.LastStart)
+	state.LastError = err
+	if err == nil {
+		state.LastSuccess = time.Now()
+	}
+}
+
+func (s *taskStates) remove(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, id)
+}
+
+func (s *taskStates) list() []TaskState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]TaskState, 0, len(s.states))
+	for _, state := range s.states {
+		list = append(list, *state)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (s *taskStates) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.states)
+}
@@ -8,10 +8,13 @@ import (
 )
 
 type TaskConfig struct {
-	RetryTaskLimit        int           // how many times to repeat; if 0, the default value of the scheduler is used
-	Timeout               time.Duration // maximum execution time; if 0, no timeouts
-	IsWaitForPrevious     bool          // do  need to wait for the completion of the previous task
-	IsIntervalAfterFinish bool          // "wait for interval after task completion" mode
+	RetryTaskLimit        int                   // how many times to repeat; if 0, the default value of the scheduler is used
+	Timeout               time.Duration         // maximum execution time; if 0, no timeouts
+	IsWaitForPrevious     bool                  // do  need to wait for the completion of the previous task
+	IsIntervalAfterFinish bool                  // "wait for interval after task completion" mode
+	Window                *TimeWindow           // periodic and cron runs outside the window are skipped; if nil, no restrictions
+	RetryPolicy           RetryPolicy           // if nil, DefaultRetryPolicy is used
+	CircuitBreaker        *CircuitBreakerConfig // if nil, the task has no circuit breaker
 }
 
 type Task interface {
@@ -7,11 +7,13 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+	"wb_logistic_assistant/internal/metrics"
 
 	"golang.org/x/sync/singleflight"
 )
 
 type Cache[T interface{}] struct {
+	name      string // label of the metrics
 	mtx       sync.RWMutex
 	value     T
 	expiresAt time.Time
@@ -20,8 +22,8 @@ type Cache[T interface{}] struct {
 	group     singleflight.Group
 }
 
-func NewCache[T interface{}](ttl time.Duration, loader func(ctx context.Context) (T, error)) *Cache[T] {
-	return &Cache[T]{ttl: ttl, loader: loader}
+func NewCache[T interface{}](name string, ttl time.Duration, loader func(ctx context.Context) (T, error)) *Cache[T] {
+	return &Cache[T]{name: name, ttl: ttl, loader: loader}
 }
 
 func (c *Cache[T]) Get(ctx context.Context) (T, error) {
@@ -34,9 +36,11 @@ func (c *Cache[T]) Get(ctx context.Context) (T, error) {
 	if time.Now().Before(c.expiresAt) {
 		val := c.value
 		c.mtx.RUnlock()
+		metrics.CacheRequests.With(c.name, metrics.CacheHit).Inc()
 		return val, nil
 	}
 	c.mtx.RUnlock()
+	metrics.CacheRequests.With(c.name, metrics.CacheMiss).Inc()
 
 	val, err, _ := c.group.Do("load", func() (interface{}, error) {
 		c.mtx.RLock()
@@ -78,6 +82,7 @@ type cachedItem[V interface{}] struct {
 }
 
 type GenericMapCache[K comparable, V interface{}] struct {
+	name           string // label of the metrics
 	mtx            sync.RWMutex
 	data           map[K]cachedItem[V]
 	ttl            time.Duration
@@ -87,10 +92,12 @@ type GenericMapCache[K comparable, V interface{}] struct {
 }
 
 func NewGenericMapCache[K comparable, V interface{}](
+	name string,
 	ttl time.Duration,
 	loader func(ctx context.Context, key K) (V, error),
 ) *GenericMapCache[K, V] {
 	return &GenericMapCache[K, V]{
+		name:   name,
 		data:   make(map[K]cachedItem[V]),
 		ttl:    ttl,
 		loader: loader,
@@ -110,9 +117,11 @@ func (c *GenericMapCache[K, V]) Get(ctx context.Context, key K) (V, error) {
 	if ok && time.Now().Before(item.expiresAt) {
 		val := item.value
 		c.mtx.RUnlock()
+		metrics.CacheRequests.With(c.name, metrics.CacheHit).Inc()
 		return val, nil
 	}
 	c.mtx.RUnlock()
+	metrics.CacheRequests.With(c.name, metrics.CacheMiss).Inc()
 
 	stringKey := c.keyToString(key)
 	val, err, _ := c.group.Do(stringKey, func() (interface{}, error) {
@@ -1,7 +1,18 @@
 package services
 
+import (
+	"wb_logistic_assistant/internal/alerts"
+	"wb_logistic_assistant/internal/history"
+	"wb_logistic_assistant/internal/outbox"
+)
+
 type Container struct {
 	GoogleSheetsService GoogleSheetsService
 	WBLogisticService   WBLogisticService
 	TelegramBotService  TelegramBotService
+	RouteHistory        history.RouteStore
+	FinanceLedger       history.FinanceLedger
+	RatingHistory       history.RatingStore
+	Alerts              *alerts.Engine
+	Outbox              outbox.Outbox
 }
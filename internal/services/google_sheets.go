@@ -1,10 +1,13 @@
 package services
 
 import (
+	"time"
+
 	"google.golang.org/api/sheets/v4"
 	"wb_logistic_assistant/external/google_sheets_api"
 	"wb_logistic_assistant/external/google_sheets_api/auth"
 	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/metrics"
 )
 
 type GoogleSheetsService interface {
@@ -74,7 +77,9 @@ func (s *BaseGoogleSheetsService) UpdateValues(id, name, cellRange string, value
 		inputOption = google_sheets_api.ValueInputOptionRaw
 	}
 
+	start := time.Now()
 	err := s.client.UpdateValues(s.actor, id, name, cellRange, inputOption, values)
+	metrics.GoogleSheetsLatency.With("update").Observe(metrics.Since(start))
 	if err != nil {
 		return errors.Wrapf(err, "BaseGoogleSheetsService.UpdateValues()", "error to update text %s in sheet on range [%s]", inputOption, cellRange)
 	}
@@ -93,7 +98,9 @@ func (s *BaseGoogleSheetsService) AppendValues(id, name, cellRange string, value
 		insertDataOption = google_sheets_api.InsertDataOptionOverwrite
 	}
 
+	start := time.Now()
 	err := s.client.AppendValues(s.actor, id, name, cellRange, inputOption, insertDataOption, values)
+	metrics.GoogleSheetsLatency.With("append").Observe(metrics.Since(start))
 	if err != nil {
 		return errors.Wrapf(err, "BaseGoogleSheetsService.AppendValues()", "Error to append %s text in sheet on range [%s] with insert option %s", inputOption, cellRange, insertDataOption)
 	}
@@ -105,7 +112,9 @@ func (s *BaseGoogleSheetsService) AppendValues(id, name, cellRange string, value
 //	Range format: A1:D1 or A:B, etc.
 //	If need clearing all values cellRange: A:Z
 func (s *BaseGoogleSheetsService) ClearValues(id, sheetName, cellRange string) error {
+	start := time.Now()
 	err := s.client.ClearValues(s.actor, id, sheetName, cellRange)
+	metrics.GoogleSheetsLatency.With("clear").Observe(metrics.Since(start))
 	if err != nil {
 		return errors.Wrapf(err, "BaseGoogleSheetsService.ClearValues()", "failed to clear values sheet to range [%s]", cellRange)
 	}
@@ -2,8 +2,11 @@ package services
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/metrics"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -19,6 +22,21 @@ type TelegramBotService interface {
 	HandleCommands(updates []tgbotapi.Update, handlers map[string]func(update tgbotapi.Update)) error
 }
 
+// TelegramRetryAfter Returns the delay demanded by Telegram in the 'Too Many Requests' error, 0 if there is none
+func TelegramRetryAfter(err error) time.Duration {
+	var tgErr *tgbotapi.Error
+	if errors.As(err, &tgErr) && tgErr.RetryAfter > 0 {
+		return time.Duration(tgErr.RetryAfter) * time.Second
+	}
+	return 0
+}
+
+// IsTelegramPermanentError Telegram refused the message itself, e.g. invalid markup or the bot was blocked in the chat, so resending does not help
+func IsTelegramPermanentError(err error) bool {
+	var tgErr *tgbotapi.Error
+	return errors.As(err, &tgErr) && (tgErr.Code == http.StatusBadRequest || tgErr.Code == http.StatusForbidden)
+}
+
 type TelegramBotAPIService struct {
 	bot *tgbotapi.BotAPI
 }
@@ -46,6 +64,7 @@ func (s *TelegramBotAPIService) SendMessage(chatID int64, message string, parseM
 		msg.ParseMode = parseMode
 
 		_, err := s.bot.Send(msg)
+		observeTelegramSend("message", err)
 		if err != nil {
 			return errors.Wrap(err, "TelegramBotAPIService.SendMessage()", "failed send message part")
 		}
@@ -58,6 +77,7 @@ func (s *TelegramBotAPIService) SendPhotoFile(chatID int64, photoPath, caption s
 	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath(photoPath))
 	photo.Caption = caption
 	_, err := s.bot.Send(photo)
+	observeTelegramSend("photo", err)
 	if err != nil {
 		return errors.Wrap(err, "TelegramBotAPIService.SendPhotoFile()", "failed send photo file")
 	}
@@ -68,6 +88,7 @@ func (s *TelegramBotAPIService) SendDocumentFile(chatID int64, filePath, caption
 	doc := tgbotapi.NewDocument(chatID, tgbotapi.FilePath(filePath))
 	doc.Caption = caption
 	_, err := s.bot.Send(doc)
+	observeTelegramSend("document", err)
 	if err != nil {
 		return errors.Wrap(err, "TelegramBotAPIService.SendDocumentFile()", "failed send document file")
 	}
@@ -118,3 +139,11 @@ func (s *TelegramBotAPIService) HandleCommands(updates []tgbotapi.Update, handle
 
 	return nil
 }
+
+func observeTelegramSend(method string, err error) {
+	status := metrics.StatusSuccess
+	if err != nil {
+		status = metrics.StatusFailure
+	}
+	metrics.TelegramSends.With(method, status).Inc()
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"wb_logistic_assistant/internal/config"
+	"wb_logistic_assistant/internal/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TelegramBotAccessService Passes to the handlers only the commands allowed by the roles of the chat and the user.
+// In a group the chat limits the listed user, e.g. a finance user gets no finance commands in a driver chat.
+// Admin has all roles, any role allows viewer commands
+type TelegramBotAccessService struct {
+	TelegramBotService
+	chatRoles            map[int64]map[string]struct{} // chat id -> roles
+	userRoles            map[int64]map[string]struct{} // user id -> roles
+	commandRoles         map[string]string             // command -> required role
+	adminChatID          int64
+	isReportUnauthorized bool
+}
+
+func NewTelegramBotAccessService(service TelegramBotService, access *config.TelegramBotAccess, commandRoles map[string]string) *TelegramBotAccessService {
+	return &TelegramBotAccessService{
+		TelegramBotService:   service,
+		chatRoles:            rolesToSets(access.Chats()),
+		userRoles:            rolesToSets(access.Users()),
+		commandRoles:         commandRoles,
+		adminChatID:          access.AdminChatID(),
+		isReportUnauthorized: access.IsReportUnauthorized(),
+	}
+}
+
+func (s *TelegramBotAccessService) HandleCommands(updates []tgbotapi.Update, handlers map[string]func(update tgbotapi.Update)) error {
+	allowed := make([]tgbotapi.Update, 0, len(updates))
+	for _, update := range updates {
+		if update.Message == nil || !update.Message.IsCommand() {
+			continue
+		}
+		if s.isAllowed(update.Message) {
+			allowed = append(allowed, update)
+			continue
+		}
+		s.handleUnauthorized(update.Message)
+	}
+	return s.TelegramBotService.HandleCommands(allowed, handlers)
+}
+
+func (s *TelegramBotAccessService) isAllowed(message *tgbotapi.Message) bool {
+	if message.Chat == nil {
+		return false
+	}
+	required, ok := s.commandRoles[strings.ToLower(message.Command())]
+	if !ok {
+		required = config.TelegramBotRoleViewer // unknown commands are answered by the handler itself
+	}
+
+	var userRoles map[string]struct{}
+	if message.From != nil {
+		userRoles = s.userRoles[message.From.ID]
+	}
+	if message.Chat.IsPrivate() {
+		return hasRole(s.chatRoles[message.Chat.ID], required) || hasRole(userRoles, required)
+	}
+	if !hasRole(s.chatRoles[message.Chat.ID], required) {
+		return false
+	}
+	return userRoles == nil || hasRole(userRoles, required)
+}
+
+// isKnown The chat or the user has any role
+func (s *TelegramBotAccessService) isKnown(message *tgbotapi.Message) bool {
+	if message.Chat != nil && len(s.chatRoles[message.Chat.ID]) > 0 {
+		return true
+	}
+	return message.From != nil && len(s.userRoles[message.From.ID]) > 0
+}
+
+// hasRole Admin has all roles, any role allows viewer commands
+func hasRole(roles map[string]struct{}, required string) bool {
+	if len(roles) == 0 {
+		return false
+	}
+	if _, ok := roles[config.TelegramBotRoleAdmin]; ok {
+		return true
+	}
+	if required == config.TelegramBotRoleViewer {
+		return true
+	}
+	_, ok := roles[required]
+	return ok
+}
+
+func (s *TelegramBotAccessService) handleUnauthorized(message *tgbotapi.Message) {
+	var chatID, userID int64
+	var userName string
+	if message.Chat != nil {
+		chatID = message.Chat.ID
+	}
+	if message.From != nil {
+		userID = message.From.ID
+		userName = message.From.UserName
+	}
+	command := strings.ToLower(message.Command())
+
+	logger.Logf(logger.WARN, "TelegramBotAccessService.HandleCommands()", "unauthorized command /%s from user %d (%s) in chat %d", command, userID, userName, chatID)
+
+	// the known chats and users are told about the lack of rights, the others get no answer
+	if s.isKnown(message) {
+		if err := s.SendMessage(chatID, "Недостаточно прав для команды /"+html.EscapeString(command), "HTML"); err != nil {
+			logger.Logf(logger.ERROR, "TelegramBotAccessService.handleUnauthorized()", "failed send reply to chat %d: %v", chatID, err)
+		}
+	}
+
+	if s.isReportUnauthorized && s.adminChatID != 0 {
+		report := fmt.Sprintf("<b>Попытка доступа</b>\nКоманда: /%s\nПользователь: %d @%s\nЧат: %d",
+			html.EscapeString(command), userID, html.EscapeString(userName), chatID)
+		if err := s.SendMessage(s.adminChatID, report, "HTML"); err != nil {
+			logger.Logf(logger.ERROR, "TelegramBotAccessService.handleUnauthorized()", "failed send report to admin chat %d: %v", s.adminChatID, err)
+		}
+	}
+}
+
+func rolesToSets(values map[int64][]string) map[int64]map[string]struct{} {
+	sets := make(map[int64]map[string]struct{}, len(values))
+	for id, roles := range values {
+		set := make(map[string]struct{}, len(roles))
+		for _, role := range roles {
+			set[role] = struct{}{}
+		}
+		sets[id] = set
+	}
+	return sets
+}
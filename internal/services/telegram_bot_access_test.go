@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"wb_logistic_assistant/internal/config"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestTelegramBotAccessIsAllowed(t *testing.T) {
+	const (
+		financeChat  = -100
+		driverChat   = -200
+		unknownChat  = -300
+		financeUser  = 10
+		viewerUser   = 20
+		adminUser    = 30
+		unlistedUser = 40
+	)
+	s := &TelegramBotAccessService{
+		chatRoles: rolesToSets(map[int64][]string{
+			financeChat: {config.TelegramBotRoleFinance},
+			driverChat:  {config.TelegramBotRoleDispatcher},
+		}),
+		userRoles: rolesToSets(map[int64][]string{
+			financeUser: {config.TelegramBotRoleFinance},
+			viewerUser:  {config.TelegramBotRoleViewer},
+			adminUser:   {config.TelegramBotRoleAdmin},
+		}),
+		commandRoles: map[string]string{
+			"status":  config.TelegramBotRoleViewer,
+			"routes":  config.TelegramBotRoleDispatcher,
+			"finance": config.TelegramBotRoleFinance,
+			"pause":   config.TelegramBotRoleAdmin,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		chatID  int64
+		private bool
+		userID  int64
+		command string
+		allowed bool
+	}{
+		{"finance user in finance chat", financeChat, false, financeUser, "finance", true},
+		{"finance user in driver chat", driverChat, false, financeUser, "finance", false},
+		{"finance user in unknown chat", unknownChat, false, financeUser, "finance", false},
+		{"unlisted user in finance chat", financeChat, false, unlistedUser, "finance", true},
+		{"viewer user in finance chat", financeChat, false, viewerUser, "finance", false},
+		{"viewer user in finance chat views", financeChat, false, viewerUser, "status", true},
+		{"admin user in driver chat", driverChat, false, adminUser, "routes", true},
+		{"admin user in driver chat pauses", driverChat, false, adminUser, "pause", false},
+		{"unlisted user in driver chat", driverChat, false, unlistedUser, "routes", true},
+		{"unlisted user in unknown chat", unknownChat, false, unlistedUser, "status", false},
+		{"finance user in private chat", financeUser, true, financeUser, "finance", true},
+		{"admin user in private chat", adminUser, true, adminUser, "pause", true},
+		{"unlisted user in private chat", unlistedUser, true, unlistedUser, "status", false},
+		{"unknown command in finance chat", financeChat, false, unlistedUser, "help", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatType := "supergroup"
+			if tt.private {
+				chatType = "private"
+			}
+			text := "/" + tt.command
+			message := &tgbotapi.Message{
+				Text:     text,
+				Chat:     &tgbotapi.Chat{ID: tt.chatID, Type: chatType},
+				From:     &tgbotapi.User{ID: tt.userID},
+				Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(text)}},
+			}
+			if got := s.isAllowed(message); got != tt.allowed {
+				t.Fatalf("isAllowed() = %v, want %v", got, tt.allowed)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"time"
 	"wb_logistic_assistant/external/wb_logistic_api"
+	wb_errors "wb_logistic_assistant/external/wb_logistic_api/errors"
 	wb_models "wb_logistic_assistant/external/wb_logistic_api/models"
 	wb_logistic_session "wb_logistic_assistant/external/wb_logistic_api/session"
 	"wb_logistic_assistant/internal/errors"
@@ -13,6 +15,7 @@ type WBLogisticService interface {
 	SetClient(client *wb_logistic_api.Client) error
 	SetSession(session *wb_logistic_session.Session) error
 	IsSessionExpired() bool
+	SessionTokenExpiresAt() time.Time
 	GetUserInfo(ctx context.Context) (*wb_models.UserInfo, error)
 	GetRemainsLastMileReports(ctx context.Context) (wb_models.RemainsLastMileReports, error)
 	GetRemainsLastMileReportByOfficeID(ctx context.Context, officeID int) (*wb_models.RemainsLastMileReport, error)
@@ -26,6 +29,18 @@ type WBLogisticService interface {
 	GetWaySheetInfo(ctx context.Context, id int) (*wb_models.WaySheetInfo, error)
 	GetWaySheetFinanceDetails(ctx context.Context, waySheetID int) (*wb_models.WaySheetFinanceDetails, error)
 }
+
+// IsWBLogisticUnauthorized WB logistic rejected the session, the reports fail until it is restored
+func IsWBLogisticUnauthorized(err error) bool {
+	return wb_errors.IsUnauthorized(err)
+}
+
+// IsWBLogisticRetryable The failed WB logistic request may succeed later, e.g. 429 or 5xx. The errors which are not
+// from WB logistic are retryable
+func IsWBLogisticRetryable(err error) bool {
+	return !wb_errors.IsUnauthorized(err) && wb_errors.IsRetryable(err)
+}
+
 type BaseWBLogisticService struct {
 	client                               *wb_logistic_api.Client
 	session                              *wb_logistic_session.Session
@@ -45,21 +60,21 @@ func NewBaseWBLogisticService(client *wb_logistic_api.Client, session *wb_logist
 	s = &BaseWBLogisticService{
 		client:  client,
 		session: session,
-		cacheUserInfo: NewCache[*wb_models.UserInfo](ttl.UserInfo, func(ctx context.Context) (*wb_models.UserInfo, error) {
+		cacheUserInfo: NewCache[*wb_models.UserInfo]("user_info", ttl.UserInfo, func(ctx context.Context) (*wb_models.UserInfo, error) {
 			res, err := client.GetUserInfo(ctx, s.session)
 			if err != nil {
 				return nil, errors.Wrap(err, "BaseWBLogisticService.GetUserInfo()", "")
 			}
 			return res.Data, nil
 		}),
-		cacheRemainsLastMileReports: NewCache[wb_models.RemainsLastMileReports](ttl.RemainsLastMileReports, func(ctx context.Context) (wb_models.RemainsLastMileReports, error) {
+		cacheRemainsLastMileReports: NewCache[wb_models.RemainsLastMileReports]("remains_last_mile_reports", ttl.RemainsLastMileReports, func(ctx context.Context) (wb_models.RemainsLastMileReports, error) {
 			res, err := client.GetRemainsLastMileReports(ctx, s.session)
 			if err != nil {
 				return nil, errors.Wrap(err, "BaseWBLogisticService.GetRemainsLastMileReports()", "")
 			}
 			return res.Data, nil
 		}),
-		cacheRemainsLastMileReport: NewGenericMapCache[int, *wb_models.RemainsLastMileReport](ttl.RemainsLastMileReports, func(ctx context.Context, officeID int) (*wb_models.RemainsLastMileReport, error) {
+		cacheRemainsLastMileReport: NewGenericMapCache[int, *wb_models.RemainsLastMileReport]("remains_last_mile_report", ttl.RemainsLastMileReports, func(ctx context.Context, officeID int) (*wb_models.RemainsLastMileReport, error) {
 			reports, err := s.GetRemainsLastMileReports(ctx)
 			if err != nil {
 				return nil, errors.Wrap(err, "BaseWBLogisticService.GetRemainsLastMileReportByOfficeID()", "")
@@ -73,14 +88,14 @@ func NewBaseWBLogisticService(client *wb_logistic_api.Client, session *wb_logist
 
 			return nil, errors.New("BaseWBLogisticService.GetRemainsLastMileReportByOfficeID()", "route report not found")
 		}),
-		cacheRemainsLastMileReportsRouteInfo: NewGenericMapCache[int, []*wb_models.RemainsLastMileReportsRouteInfo](ttl.RemainsLastMileReportsRouteInfo, func(ctx context.Context, routeID int) ([]*wb_models.RemainsLastMileReportsRouteInfo, error) {
+		cacheRemainsLastMileReportsRouteInfo: NewGenericMapCache[int, []*wb_models.RemainsLastMileReportsRouteInfo]("remains_last_mile_reports_route_info", ttl.RemainsLastMileReportsRouteInfo, func(ctx context.Context, routeID int) ([]*wb_models.RemainsLastMileReportsRouteInfo, error) {
 			info, err := client.GetRemainsLastMileReportsRouteInfo(ctx, s.session, routeID)
 			if err != nil {
 				return nil, errors.Wrap(err, "BaseWBLogisticService.GetRemainsLastMileReportsRouteInfo()", "")
 			}
 			return info.Data, nil
 		}),
-		cacheJobsScheduling: NewCache[*wb_models.JobsScheduling](ttl.JobsScheduling, func(ctx context.Context) (*wb_models.JobsScheduling, error) {
+		cacheJobsScheduling: NewCache[*wb_models.JobsScheduling]("jobs_scheduling", ttl.JobsScheduling, func(ctx context.Context) (*wb_models.JobsScheduling, error) {
 			res, err := s.client.GetJobsScheduling(ctx, s.session)
 			if err != nil {
 				return nil, errors.Wrap(err, "BaseWBLogisticService.GetJobsScheduling()", "")
@@ -90,28 +105,28 @@ func NewBaseWBLogisticService(client *wb_logistic_api.Client, session *wb_logist
 			}
 			return res.Data, nil
 		}),
-		cacheShipmentInfo: NewGenericMapCache[int, *wb_models.ShipmentInfo](ttl.ShipmentInfo, func(ctx context.Context, shipmentID int) (*wb_models.ShipmentInfo, error) {
+		cacheShipmentInfo: NewGenericMapCache[int, *wb_models.ShipmentInfo]("shipment_info", ttl.ShipmentInfo, func(ctx context.Context, shipmentID int) (*wb_models.ShipmentInfo, error) {
 			info, err := client.GetShipmentInfo(ctx, s.session, shipmentID)
 			if err != nil {
 				return nil, errors.Wrap(err, "BaseWBLogisticService.GetShipmentInfo()", "")
 			}
 			return info.Data, nil
 		}),
-		cacheShipmentTransfers: NewGenericMapCache[int, *wb_models.ShipmentTransfers](ttl.ShipmentTransfers, func(ctx context.Context, shipmentID int) (*wb_models.ShipmentTransfers, error) {
+		cacheShipmentTransfers: NewGenericMapCache[int, *wb_models.ShipmentTransfers]("shipment_transfers", ttl.ShipmentTransfers, func(ctx context.Context, shipmentID int) (*wb_models.ShipmentTransfers, error) {
 			info, err := client.GetShipmentTransfers(ctx, s.session, shipmentID)
 			if err != nil {
 				return nil, errors.Wrap(err, "BaseWBLogisticService.GetShipmentTransfers()", "")
 			}
 			return info.Data, nil
 		}),
-		cacheWaySheetInfo: NewGenericMapCache[int, *wb_models.WaySheetInfo](ttl.WaySheetInfo, func(ctx context.Context, id int) (*wb_models.WaySheetInfo, error) {
+		cacheWaySheetInfo: NewGenericMapCache[int, *wb_models.WaySheetInfo]("way_sheet_info", ttl.WaySheetInfo, func(ctx context.Context, id int) (*wb_models.WaySheetInfo, error) {
 			info, err := client.GetWaySheetInfo(ctx, s.session, id)
 			if err != nil {
 				return nil, errors.Wrap(err, "BaseWBLogisticService.GetWaySheetInfo()", "")
 			}
 			return info.Data.WaySheet, nil
 		}),
-		cacheWaySheetFinanceDetails: NewGenericMapCache[int, *wb_models.WaySheetFinanceDetails](ttl.WaySheetFinanceDetails, func(ctx context.Context, waySheetID int) (*wb_models.WaySheetFinanceDetails, error) {
+		cacheWaySheetFinanceDetails: NewGenericMapCache[int, *wb_models.WaySheetFinanceDetails]("way_sheet_finance_details", ttl.WaySheetFinanceDetails, func(ctx context.Context, waySheetID int) (*wb_models.WaySheetFinanceDetails, error) {
 			res, err := client.GetWaySheetFinanceDetails(ctx, s.session, waySheetID)
 			if err != nil {
 				return nil, errors.Wrap(err, "BaseWBLogisticService.GetWaySheetFinanceDetails()", "")
@@ -152,6 +167,14 @@ func (s *BaseWBLogisticService) IsSessionExpired() bool {
 	return s.session.SessionTokenExpired()
 }
 
+// SessionTokenExpiresAt Returns zero time if there is no session token
+func (s *BaseWBLogisticService) SessionTokenExpiresAt() time.Time {
+	if s.session == nil || s.session.SessionToken() == nil {
+		return time.Time{}
+	}
+	return time.Unix(s.session.SessionTokenExpiresIn(), 0)
+}
+
 func (s *BaseWBLogisticService) clearCache() {
 	s.cacheUserInfo.Invalidate()
 	s.cacheRemainsLastMileReports.Invalidate()
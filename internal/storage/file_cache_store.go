@@ -1,12 +1,19 @@
 package storage
 
-import "sync"
+import (
+	"encoding/json"
+	"sync"
+)
 
 type FileCache struct {
 	mtx  sync.RWMutex
 	data map[string]string
 }
 
+type fileCache struct {
+	Data map[string]string `json:"data" xml:"data"`
+}
+
 func NewFileCache() *FileCache {
 	return &FileCache{
 		data: make(map[string]string),
@@ -56,3 +63,25 @@ func (c *FileCache) Clear() {
 	c.data = make(map[string]string)
 	c.mtx.Unlock()
 }
+
+func (c *FileCache) MarshalJSON() ([]byte, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return json.Marshal(&fileCache{
+		Data: c.data,
+	})
+}
+
+func (c *FileCache) UnmarshalJSON(data []byte) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	temp := &fileCache{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	c.data = temp.Data
+	if c.data == nil {
+		c.data = make(map[string]string) // storage saved before the cache was persisted
+	}
+	return nil
+}
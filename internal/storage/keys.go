@@ -3,3 +3,10 @@ package storage
 const (
 	StoragePassword = "storage_password"
 )
+
+// Keys of the cache store
+const (
+	CacheShipmentCloseReporter = "shipment_close_reporter"
+	CacheFinanceRoutesReporter = "finance_routes_reporter"
+	CacheFinanceDailyReporter  = "finance_daily_reporter"
+)
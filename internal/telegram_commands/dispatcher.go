@@ -0,0 +1,664 @@
+package telegram_commands
+
+import (
+	"context"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+	"wb_logistic_assistant/internal/config"
+	"wb_logistic_assistant/internal/errors"
+	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/prompters"
+	"wb_logistic_assistant/internal/report_renderers"
+	"wb_logistic_assistant/internal/reporters"
+	"wb_logistic_assistant/internal/reports"
+	"wb_logistic_assistant/internal/scheduler"
+	"wb_logistic_assistant/internal/services"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	CommandStart    = "start"
+	CommandHelp     = "help"
+	CommandStatus   = "status"
+	CommandRoutes   = "routes"
+	CommandRoute    = "route"
+	CommandFinance  = "finance"
+	CommandShipment = "shipment"
+	CommandWaySheet = "waysheet"
+	CommandPause    = "pause"
+	CommandResume   = "resume"
+	CommandRun      = "run"
+	CommandInterval = "interval"
+	CommandDead     = "deadletters"
+	CommandReplay   = "replay"
+	CommandAuth     = "auth"
+)
+
+// deadLettersLimit Count of the last dead letters listed by the command
+const (
+	deadLettersLimit  = 20
+	deadLetterPreview = 100 // runes of the message text
+	replayAllArgument = "all"
+)
+
+// reportTaskSuffix Suffix of the scheduler task names of the reports, may be omitted in the commands
+const reportTaskSuffix = "_report"
+
+// CommandRoles Role required for the command when the access control is enabled. Without it the admin and
+// finance commands are answered only in the admin chat
+var CommandRoles = map[string]string{
+	CommandStart:    config.TelegramBotRoleViewer,
+	CommandHelp:     config.TelegramBotRoleViewer,
+	CommandStatus:   config.TelegramBotRoleViewer,
+	CommandRoutes:   config.TelegramBotRoleDispatcher,
+	CommandRoute:    config.TelegramBotRoleDispatcher,
+	CommandShipment: config.TelegramBotRoleDispatcher,
+	CommandWaySheet: config.TelegramBotRoleDispatcher,
+	CommandFinance:  config.TelegramBotRoleFinance,
+	CommandPause:    config.TelegramBotRoleAdmin,
+	CommandResume:   config.TelegramBotRoleAdmin,
+	CommandRun:      config.TelegramBotRoleAdmin,
+	CommandInterval: config.TelegramBotRoleAdmin,
+	CommandDead:     config.TelegramBotRoleAdmin,
+	CommandReplay:   config.TelegramBotRoleAdmin,
+	CommandAuth:     config.TelegramBotRoleAdmin,
+}
+
+const helpMessage = `<b>Команды:</b>
+/status - состояние задач
+/routes - текущие маршруты
+/route &lt;id&gt; - маршрут
+/finance &lt;дд.мм.гггг&gt; - финансы за день, по умолчанию сегодня
+/shipment &lt;id&gt; - отгрузка
+/waysheet &lt;id&gt; - путевой лист
+/pause &lt;отчет&gt; - приостановить отчет
+/resume &lt;отчет&gt; - возобновить отчет
+/run &lt;отчет&gt; - запустить отчет сейчас
+/interval &lt;отчет&gt; &lt;интервал&gt; - изменить интервал отчета, например 15m
+/deadletters - недоставленные сообщения
+/replay &lt;id|all&gt; - повторно отправить недоставленные сообщения
+/auth [код] - авторизация WB logistic в чате администратора`
+
+type commandHandler func(ctx context.Context, update tgbotapi.Update)
+
+// Dispatcher Long polling loop which answers the bot commands with the reports
+type Dispatcher struct {
+	config    *config.Config
+	services  *services.Container
+	scheduler scheduler.Scheduler
+	prompter  prompters.TelegramCommandsPrompter
+
+	routes  reporters.GeneralRoutesSnapshotter
+	finance reporters.DayRenderer
+	wbAuth  *WBAuthPrompter // nil if the admin chat is not set
+	reauth  func() bool     // starts the WB logistic authorization unless it is already running
+
+	isAccessEnabled bool  // the commands are checked by the roles before the dispatcher
+	adminChatID     int64 // 0 if not set
+
+	rendererTG     report_renderers.ReportRenderer[[]string]
+	reportStatus   *reports.TaskStatusReport
+	reportRoutes   *reports.RoutesStatusReport
+	reportRoute    *reports.RouteStatusReport
+	reportShipment *reports.ShipmentInfoReport
+	reportWaySheet *reports.WaySheetInfoReport
+	reportDead     *reports.DeadLettersReport
+
+	pollingTimeout time.Duration
+	pollingLimit   int
+	retryInterval  time.Duration
+	offset         int
+
+	handlers map[string]commandHandler
+}
+
+func NewDispatcher(
+	config *config.Config,
+	services *services.Container,
+	scheduler scheduler.Scheduler,
+	routes reporters.GeneralRoutesSnapshotter,
+	finance reporters.DayRenderer,
+	wbAuth *WBAuthPrompter,
+	prompter prompters.TelegramCommandsPrompter,
+) *Dispatcher {
+	d := &Dispatcher{
+		config:    config,
+		services:  services,
+		scheduler: scheduler,
+		prompter:  prompter,
+
+		routes:  routes,
+		finance: finance,
+		wbAuth:  wbAuth,
+
+		isAccessEnabled: config.Telegram().Access().IsEnabled(),
+		adminChatID:     config.Telegram().Access().AdminChatID(),
+
+		rendererTG:     &report_renderers.TelegramBotRenderer{Mode: report_renderers.TelegramBotRenderHTML},
+		reportStatus:   &reports.TaskStatusReport{},
+		reportRoutes:   &reports.RoutesStatusReport{},
+		reportRoute:    &reports.RouteStatusReport{},
+		reportShipment: &reports.ShipmentInfoReport{},
+		reportWaySheet: &reports.WaySheetInfoReport{},
+		reportDead:     &reports.DeadLettersReport{},
+
+		pollingTimeout: config.Telegram().Commands().PollingTimeout(),
+		pollingLimit:   config.Telegram().Commands().PollingLimit(),
+		retryInterval:  config.Telegram().Commands().RetryInterval(),
+	}
+	d.handlers = map[string]commandHandler{
+		CommandStart:    d.handleHelp,
+		CommandHelp:     d.handleHelp,
+		CommandStatus:   d.handleStatus,
+		CommandRoutes:   d.handleRoutes,
+		CommandRoute:    d.handleRoute,
+		CommandFinance:  d.handleFinance,
+		CommandShipment: d.handleShipment,
+		CommandWaySheet: d.handleWaySheet,
+		CommandPause:    d.handlePause,
+		CommandResume:   d.handleResume,
+		CommandRun:      d.handleRun,
+		CommandInterval: d.handleInterval,
+		CommandDead:     d.handleDeadLetters,
+		CommandReplay:   d.handleReplay,
+		CommandAuth:     d.handleAuth,
+	}
+	return d
+}
+
+// SetReauthHandler The handler starts the WB logistic authorization even if the session looks valid and returns false if it is already being restored
+func (d *Dispatcher) SetReauthHandler(reauth func() bool) {
+	d.reauth = reauth
+}
+
+// Run Polls updates until the context is done
+func (d *Dispatcher) Run(ctx context.Context) error {
+	bot, err := d.services.TelegramBotService.GetBotInfo()
+	if err != nil {
+		return errors.Wrap(err, "Dispatcher.Run()", "failed get bot info")
+	}
+	d.prompter.PromptStart(bot.UserName)
+	logger.Logf(logger.INFO, "Dispatcher.Run()", "start polling commands of bot %s", bot.UserName)
+
+	for ctx.Err() == nil {
+		if err = d.poll(ctx); err != nil {
+			d.prompter.PromptError("Failed polling updates")
+			logger.Logf(logger.ERROR, "Dispatcher.Run()", "failed polling updates: %v", err)
+
+			select {
+			case <-time.After(d.retryInterval):
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	logger.Log(logger.INFO, "Dispatcher.Run()", "stop polling commands")
+	return nil
+}
+
+func (d *Dispatcher) poll(ctx context.Context) error {
+	updates, err := d.services.TelegramBotService.GetUpdates(d.offset, d.pollingLimit, int(d.pollingTimeout/time.Second))
+	if err != nil {
+		return errors.Wrap(err, "Dispatcher.poll()", "")
+	}
+	// the updates are not confirmed by the offset, so they will be received again after the restart
+	if len(updates) == 0 || ctx.Err() != nil {
+		return nil
+	}
+
+	handlers := make(map[string]func(update tgbotapi.Update), len(d.handlers))
+	for command, handler := range d.handlers {
+		handlers[command] = func(update tgbotapi.Update) {
+			d.prompter.PromptCommand(command, update.Message.Chat.ID)
+			logger.Logf(logger.INFO, "Dispatcher.poll()", "command /%s from chat %d", command, update.Message.Chat.ID)
+			if d.isRestricted(command, update.Message.Chat.ID) {
+				logger.Logf(logger.WARN, "Dispatcher.poll()", "command /%s refused in chat %d, access control is disabled", command, update.Message.Chat.ID)
+				d.replyText(update.Message.Chat.ID, "Команда /"+command+" доступна только в чате администратора")
+				return
+			}
+			handler(ctx, update)
+		}
+	}
+
+	err = d.services.TelegramBotService.HandleCommands(updates, handlers)
+
+	for _, update := range updates {
+		if update.UpdateID >= d.offset {
+			d.offset = update.UpdateID + 1
+		}
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "Dispatcher.poll()", "failed handle commands")
+	}
+	return nil
+}
+
+// isRestricted Without the access control the admin and finance commands are answered only in the admin chat,
+// so any chat which sees the bot cannot control the reports, restore the WB session or read the finance
+func (d *Dispatcher) isRestricted(command string, chatID int64) bool {
+	if d.isAccessEnabled {
+		return false
+	}
+	switch CommandRoles[command] {
+	case config.TelegramBotRoleAdmin, config.TelegramBotRoleFinance:
+		return d.adminChatID == 0 || chatID != d.adminChatID
+	}
+	return false
+}
+
+func (d *Dispatcher) handleHelp(_ context.Context, update tgbotapi.Update) {
+	d.replyText(update.Message.Chat.ID, helpMessage)
+}
+
+func (d *Dispatcher) handleStatus(_ context.Context, update tgbotapi.Update) {
+	states := d.scheduler.List()
+	data := &reports.TaskStatusReportData{Tasks: make([]*reports.TaskStatusData, 0, len(states))}
+	for _, state := range states {
+		task := &reports.TaskStatusData{
+			Name:          state.Name,
+			IsRunning:     state.IsRunning,
+			LastStart:     state.LastStart,
+			LastDuration:  state.LastDuration,
+			Attempts:      state.Attempts,
+			Runs:          state.Runs,
+			NextRun:       state.NextRun,
+			IsPaused:      state.IsPaused,
+			Interval:      state.Interval,
+			Schedule:      state.Schedule,
+			IsCircuitOpen: state.Circuit == scheduler.CircuitOpen,
+		}
+		if state.LastError != nil {
+			task.LastError = html.EscapeString(state.LastError.Error())
+		}
+		data.Tasks = append(data.Tasks, task)
+	}
+
+	report, err := d.reportStatus.Render(data)
+	d.replyReport(update.Message.Chat.ID, report, err)
+}
+
+func (d *Dispatcher) handleRoutes(_ context.Context, update tgbotapi.Update) {
+	t, routes, ok := d.routesSnapshot(update.Message.Chat.ID)
+	if !ok {
+		return
+	}
+
+	report, err := d.reportRoutes.Render(&reports.RoutesStatusReportData{Time: t, Routes: routes})
+	d.replyReport(update.Message.Chat.ID, report, err)
+}
+
+func (d *Dispatcher) handleRoute(_ context.Context, update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	routeID, ok := d.argumentID(update)
+	if !ok {
+		d.replyText(chatID, "Укажите номер маршрута: /route &lt;id&gt;")
+		return
+	}
+
+	t, routes, ok := d.routesSnapshot(chatID)
+	if !ok {
+		return
+	}
+
+	for _, route := range routes {
+		if route.RouteID == routeID {
+			report, err := d.reportRoute.Render(&reports.RouteStatusReportData{Time: t, Route: route})
+			d.replyReport(chatID, report, err)
+			return
+		}
+	}
+	d.replyText(chatID, "Маршрут "+strconv.Itoa(routeID)+" не найден")
+}
+
+func (d *Dispatcher) handleFinance(ctx context.Context, update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+
+	date := time.Now().UTC()
+	if arg := strings.TrimSpace(update.Message.CommandArguments()); arg != "" {
+		var err error
+		date, err = parseDate(arg)
+		if err != nil {
+			d.replyText(chatID, "Неверная дата, формат: дд.мм.гггг")
+			return
+		}
+	}
+
+	d.replyText(chatID, "Формирую отчет за "+date.Format("02.01.2006")+"...")
+	report, err := d.finance.RenderDay(ctx, date)
+	d.replyReport(chatID, report, err)
+}
+
+func (d *Dispatcher) handleShipment(ctx context.Context, update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	shipmentID, ok := d.argumentID(update)
+	if !ok {
+		d.replyText(chatID, "Укажите номер отгрузки: /shipment &lt;id&gt;")
+		return
+	}
+
+	info, err := d.services.WBLogisticService.GetShipmentInfo(ctx, shipmentID)
+	if err != nil {
+		d.replyReport(chatID, nil, errors.Wrapf(err, "Dispatcher.handleShipment()", "failed load shipment %d", shipmentID))
+		return
+	}
+	if info == nil {
+		d.replyText(chatID, "Отгрузка "+strconv.Itoa(shipmentID)+" не найдена")
+		return
+	}
+
+	data := &reports.ShipmentInfoReportData{
+		ShipmentID:         info.ID,
+		RouteID:            info.RouteID,
+		WaySheetID:         info.WaySheetID,
+		DateCreate:         info.CreateDt,
+		DateClose:          info.CloseDt,
+		DriverName:         info.DriverName,
+		VehicleNumberPlate: info.VehicleNumberPlate,
+		SupplierName:       info.SupplierName,
+		SrcOfficeName:      info.SrcOfficeName,
+	}
+	for _, office := range info.DestinationOfficesInfo {
+		if office != nil {
+			data.DstOfficeNames = append(data.DstOfficeNames, office.DstOfficeName)
+		}
+	}
+
+	transfers, err := d.services.WBLogisticService.GetShipmentTransfers(ctx, shipmentID)
+	if err != nil {
+		logger.Logf(logger.WARN, "Dispatcher.handleShipment()", "failed load transfers of shipment %d: %v", shipmentID, err)
+	} else if transfers != nil {
+		for _, box := range transfers.TransferBoxes {
+			if box != nil {
+				data.Tares++
+				data.Barcodes += box.CountBarcodes
+			}
+		}
+	}
+
+	report, err := d.reportShipment.Render(data)
+	d.replyReport(chatID, report, err)
+}
+
+func (d *Dispatcher) handleWaySheet(ctx context.Context, update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	waySheetID, ok := d.argumentID(update)
+	if !ok {
+		d.replyText(chatID, "Укажите номер путевого листа: /waysheet &lt;id&gt;")
+		return
+	}
+
+	info, err := d.services.WBLogisticService.GetWaySheetInfo(ctx, waySheetID)
+	if err != nil {
+		d.replyReport(chatID, nil, errors.Wrapf(err, "Dispatcher.handleWaySheet()", "failed load way sheet %d", waySheetID))
+		return
+	}
+	if info == nil {
+		d.replyText(chatID, "Путевой лист "+strconv.Itoa(waySheetID)+" не найден")
+		return
+	}
+
+	data := &reports.WaySheetInfoReportData{
+		WaySheetID: waySheetID,
+		DateOpen:   info.DateOpen,
+		DateClose:  info.DateClose,
+		Barcodes:   info.TotalBarcodesCount,
+		Volume:     info.TotalVolumeCount,
+	}
+	if info.Route != nil {
+		data.RouteID = info.Route.RouteCarID
+		data.RouteName = info.Route.RouteCarName
+	}
+	if info.Vehicles != nil {
+		data.VehicleNumber = info.Vehicles.ShippingCarNumber
+	}
+	for _, driver := range info.Drivers {
+		if driver != nil {
+			data.DriverNames = append(data.DriverNames, driver.DriverName)
+		}
+	}
+	for _, tare := range info.Tares {
+		if tare == nil {
+			continue
+		}
+		data.Tares++
+		if tare.IsReturn {
+			data.TaresReturned++
+		}
+	}
+	for _, office := range info.DstOffices {
+		if office != nil {
+			data.Addresses = append(data.Addresses, &reports.WaySheetInfoAddressData{
+				Name:         office.Name,
+				Sequence:     office.Sequence,
+				SequenceFact: office.SequenceFact,
+			})
+		}
+	}
+
+	report, err := d.reportWaySheet.Render(data)
+	d.replyReport(chatID, report, err)
+}
+
+func (d *Dispatcher) handlePause(_ context.Context, update tgbotapi.Update) {
+	d.controlTask(update, "Укажите отчет: /pause &lt;отчет&gt;", "приостановлен", d.scheduler.Pause)
+}
+
+func (d *Dispatcher) handleResume(_ context.Context, update tgbotapi.Update) {
+	d.controlTask(update, "Укажите отчет: /resume &lt;отчет&gt;", "возобновлен", d.scheduler.Resume)
+}
+
+func (d *Dispatcher) handleRun(_ context.Context, update tgbotapi.Update) {
+	d.controlTask(update, "Укажите отчет: /run &lt;отчет&gt;", "запущен", d.scheduler.TriggerNow)
+}
+
+func (d *Dispatcher) handleInterval(_ context.Context, update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	args := strings.Fields(update.Message.CommandArguments())
+	if len(args) != 2 {
+		d.replyText(chatID, "Укажите отчет и интервал: /interval &lt;отчет&gt; &lt;интервал&gt;, например 15m")
+		return
+	}
+
+	interval, err := time.ParseDuration(args[1])
+	if err != nil || interval <= 0 {
+		d.replyText(chatID, "Неверный интервал, например: 30s, 15m, 1h30m")
+		return
+	}
+
+	d.controlTask(update, "", "изменен интервал на "+interval.String(), func(id uint64) error {
+		return d.scheduler.UpdateInterval(id, interval)
+	})
+}
+
+func (d *Dispatcher) handleDeadLetters(_ context.Context, update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	if d.services.Outbox == nil {
+		d.replyText(chatID, "Очередь сообщений отключена")
+		return
+	}
+
+	letters := d.services.Outbox.DeadLetters()
+	data := &reports.DeadLettersReportData{
+		Pending: len(d.services.Outbox.Pending()),
+		Total:   len(letters),
+	}
+	if len(letters) > deadLettersLimit {
+		letters = letters[len(letters)-deadLettersLimit:]
+	}
+	for _, letter := range letters {
+		preview := []rune(letter.Text)
+		if len(preview) > deadLetterPreview {
+			preview = append(preview[:deadLetterPreview], '…')
+		}
+		data.Letters = append(data.Letters, &reports.DeadLetterData{
+			ID:          letter.ID,
+			Destination: letter.Destination,
+			Source:      html.EscapeString(letter.Source),
+			Attempts:    letter.Attempts,
+			LastError:   html.EscapeString(letter.LastError),
+			CreatedAt:   letter.CreatedAt,
+			Preview:     html.EscapeString(string(preview)),
+		})
+	}
+
+	report, err := d.reportDead.Render(data)
+	d.replyReport(chatID, report, err)
+}
+
+func (d *Dispatcher) handleReplay(_ context.Context, update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	if d.services.Outbox == nil {
+		d.replyText(chatID, "Очередь сообщений отключена")
+		return
+	}
+
+	arg := strings.ToLower(strings.TrimSpace(update.Message.CommandArguments()))
+	if arg == replayAllArgument {
+		count, err := d.services.Outbox.ReplayAll()
+		if err != nil {
+			d.prompter.PromptError("Failed replay dead letters")
+			logger.Logf(logger.ERROR, "Dispatcher.handleReplay()", "failed replay dead letters: %v", err)
+			d.replyText(chatID, "Не удалось выполнить команду: "+html.EscapeString(err.Error()))
+			return
+		}
+		d.replyText(chatID, "Повторно отправляется сообщений: "+strconv.Itoa(count))
+		return
+	}
+
+	id, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil || id == 0 {
+		d.replyText(chatID, "Укажите номер сообщения или all: /replay &lt;id|all&gt;, список: /deadletters")
+		return
+	}
+	if err = d.services.Outbox.Replay(id); err != nil {
+		d.replyText(chatID, "Сообщение #"+strconv.FormatUint(id, 10)+" не найдено среди недоставленных")
+		return
+	}
+	d.replyText(chatID, "Сообщение #"+strconv.FormatUint(id, 10)+" отправляется повторно")
+}
+
+// handleAuth Answers the awaited step of the WB logistic authorization, otherwise starts the authorization
+func (d *Dispatcher) handleAuth(_ context.Context, update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	if d.wbAuth == nil || chatID != d.wbAuth.ChatID() {
+		d.replyText(chatID, "Авторизация WB logistic доступна только в чате администратора")
+		return
+	}
+	if d.wbAuth.Answer(update.Message.CommandArguments()) {
+		return
+	}
+
+	if d.reauth == nil || !d.reauth() {
+		d.replyText(chatID, "Сессия WB logistic уже восстанавливается")
+	}
+}
+
+// controlTask Applies the action to the periodic scheduler tasks of the report named in the first argument
+func (d *Dispatcher) controlTask(update tgbotapi.Update, usage, done string, action func(id uint64) error) {
+	chatID := update.Message.Chat.ID
+	args := strings.Fields(update.Message.CommandArguments())
+	if len(args) == 0 {
+		d.replyText(chatID, usage)
+		return
+	}
+
+	name := taskName(args[0])
+	ids := d.taskIDs(name)
+	if len(ids) == 0 {
+		d.replyText(chatID, "Отчет "+html.EscapeString(args[0])+" не найден, список задач: /status")
+		return
+	}
+	for _, id := range ids {
+		if err := action(id); err != nil {
+			d.prompter.PromptError("Failed control task")
+			logger.Logf(logger.ERROR, "Dispatcher.controlTask()", "failed control task %d '%s': %v", id, name, err)
+			d.replyText(chatID, "Не удалось выполнить команду: "+html.EscapeString(err.Error()))
+			return
+		}
+	}
+	d.replyText(chatID, "Отчет <b>"+html.EscapeString(name)+"</b> "+done)
+}
+
+// taskIDs Returns ids of the periodic and cron scheduler tasks with the name
+func (d *Dispatcher) taskIDs(name string) []uint64 {
+	var ids []uint64
+	for _, state := range d.scheduler.List() {
+		if state.Name == name && state.IsPeriodic {
+			ids = append(ids, state.ID)
+		}
+	}
+	return ids
+}
+
+// routesSnapshot Returns the last general routes report data, replies to the chat if there is none
+func (d *Dispatcher) routesSnapshot(chatID int64) (time.Time, []*reports.GeneralRoutesReportData, bool) {
+	if d.routes == nil || !d.config.Reports().GeneralRoutes().IsEnabled() {
+		d.replyText(chatID, "Отчет по маршрутам отключен")
+		return time.Time{}, nil, false
+	}
+	t, routes := d.routes.Snapshot()
+	if len(routes) == 0 {
+		d.replyText(chatID, "Отчет по маршрутам еще не сформирован")
+		return time.Time{}, nil, false
+	}
+	return t, routes, true
+}
+
+func (d *Dispatcher) argumentID(update tgbotapi.Update) (int, bool) {
+	id, err := strconv.Atoi(strings.TrimSpace(update.Message.CommandArguments()))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+func (d *Dispatcher) replyReport(chatID int64, report *reports.ReportData, err error) {
+	if err != nil {
+		d.prompter.PromptError("Failed prepare reply")
+		logger.Logf(logger.ERROR, "Dispatcher.replyReport()", "failed prepare reply to chat %d: %v", chatID, err)
+		d.replyText(chatID, "Не удалось сформировать ответ")
+		return
+	}
+
+	messages, err := d.rendererTG.Render(report)
+	if err != nil {
+		d.prompter.PromptError("Failed render reply")
+		logger.Logf(logger.ERROR, "Dispatcher.replyReport()", "failed render reply to chat %d: %v", chatID, err)
+		d.replyText(chatID, "Не удалось сформировать ответ")
+		return
+	}
+	for _, message := range messages {
+		if message != "" {
+			d.replyText(chatID, message)
+		}
+	}
+}
+
+func (d *Dispatcher) replyText(chatID int64, message string) {
+	if err := d.services.TelegramBotService.SendMessage(chatID, message, "HTML"); err != nil {
+		d.prompter.PromptError("Failed send reply")
+		logger.Logf(logger.ERROR, "Dispatcher.replyText()", "failed send reply to chat %d: %v", chatID, err)
+	}
+}
+
+// taskName Returns the scheduler task name of the report, e.g. general_routes -> general_routes_report
+func taskName(report string) string {
+	report = strings.ToLower(strings.TrimSpace(report))
+	if !strings.HasSuffix(report, reportTaskSuffix) {
+		report += reportTaskSuffix
+	}
+	return report
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{"02.01.2006", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Newf("telegram_commands.parseDate()", "invalid date %s", s)
+}
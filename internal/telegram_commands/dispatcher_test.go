@@ -0,0 +1,34 @@
+package telegram_commands
+
+import "testing"
+
+func TestDispatcherIsRestricted(t *testing.T) {
+	const adminChat = -100
+	tests := []struct {
+		name            string
+		isAccessEnabled bool
+		adminChatID     int64
+		command         string
+		chatID          int64
+		restricted      bool
+	}{
+		{"access enabled", true, 0, CommandPause, -200, false},
+		{"viewer command", false, 0, CommandStatus, -200, false},
+		{"dispatcher command", false, 0, CommandRoutes, -200, false},
+		{"admin command without admin chat", false, 0, CommandPause, -200, true},
+		{"auth without admin chat", false, 0, CommandAuth, adminChat, true},
+		{"admin command in other chat", false, adminChat, CommandRun, -200, true},
+		{"admin command in admin chat", false, adminChat, CommandRun, adminChat, false},
+		{"finance command in other chat", false, adminChat, CommandFinance, -200, true},
+		{"finance command in admin chat", false, adminChat, CommandFinance, adminChat, false},
+		{"unknown command", false, 0, "unknown", -200, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Dispatcher{isAccessEnabled: tt.isAccessEnabled, adminChatID: tt.adminChatID}
+			if got := d.isRestricted(tt.command, tt.chatID); got != tt.restricted {
+				t.Fatalf("isRestricted() = %v, want %v", got, tt.restricted)
+			}
+		})
+	}
+}
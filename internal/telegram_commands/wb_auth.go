@@ -0,0 +1,174 @@
+package telegram_commands
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"wb_logistic_assistant/internal/logger"
+	"wb_logistic_assistant/internal/services"
+)
+
+// Answers of PromptWBLogisticRequestAuthCode which are not the code, see the WB logistic initializer
+const (
+	wbAuthRepeatCodeRequest = 1
+	wbAuthExit              = 3
+)
+
+// Arguments of the /auth command besides the code
+const (
+	wbAuthResendArgument = "resend"
+	wbAuthCancelArgument = "cancel"
+)
+
+// WBAuthPrompter Drives the WB logistic authorization from the admin chat: the steps are sent to the chat and
+// answered by the /auth command. Implements prompters.InitializeWBLogisticPrompter
+type WBAuthPrompter struct {
+	services *services.Container
+	chatID   int64
+	timeout  time.Duration
+	login    func() string // login from the storage, if empty it is asked in the chat
+
+	mtx     sync.Mutex
+	answers chan string // not nil while the answer is awaited
+}
+
+func NewWBAuthPrompter(services *services.Container, chatID int64, timeout time.Duration, login func() string) *WBAuthPrompter {
+	return &WBAuthPrompter{
+		services: services,
+		chatID:   chatID,
+		timeout:  timeout,
+		login:    login,
+	}
+}
+
+// ChatID Admin chat which answers the authorization steps
+func (p *WBAuthPrompter) ChatID() int64 { return p.chatID }
+
+// Answer Passes the argument of the /auth command to the awaited step, false if no step is awaited
+func (p *WBAuthPrompter) Answer(answer string) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.answers == nil {
+		return false
+	}
+	p.answers <- strings.TrimSpace(answer)
+	p.answers = nil // the next answers are not accepted until the next step
+	return true
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticAuthStart() {
+	p.send("Отчеты WB logistic приостановлены. Начата повторная авторизация")
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticQuestionAuthNewUser() bool {
+	return false
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticRequestAuthLogin() string {
+	if login := p.login(); login != "" {
+		return login
+	}
+	login, err := p.request("Отправьте логин WB logistic: /auth 79991112233")
+	if err != nil {
+		return ""
+	}
+	login = strings.ReplaceAll(login, " ", "")
+	return strings.TrimPrefix(login, "+")
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticInvalidAuthLogin() {
+	p.send("Невалидный логин WB logistic. Пример: /auth 79991112233")
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticRequestAuthCodeFailed(err string) {
+	p.send("Не удалось получить код доступа WB logistic: " + html.EscapeString(err))
+}
+
+// PromptWBLogisticRequestAuthCode Cancel if the answer is not received in time
+func (p *WBAuthPrompter) PromptWBLogisticRequestAuthCode(method string, seconds int) int {
+	answer, err := p.request(fmt.Sprintf("Отправлено %s уведомление с кодом WB logistic. Повторная отправка через %d сек.\n"+
+		"/auth &lt;код&gt; - отправить код\n/auth %s - повторить отправку кода\n/auth %s - отменить авторизацию",
+		html.EscapeString(method), seconds, wbAuthResendArgument, wbAuthCancelArgument))
+	if err != nil {
+		return wbAuthExit
+	}
+
+	switch strings.ToLower(answer) {
+	case wbAuthResendArgument:
+		return wbAuthRepeatCodeRequest
+	case wbAuthCancelArgument:
+		return wbAuthExit
+	}
+	code, err := strconv.Atoi(answer)
+	if err != nil || code <= wbAuthExit {
+		p.PromptWBLogisticInvalidAuthCode()
+		return wbAuthRepeatCodeRequest
+	}
+	return code
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticInvalidAuthCode() {
+	p.send("Невалидный код авторизации WB logistic")
+}
+
+// PromptWBLogisticRequestAccessTokenData The manual token is not offered in the chat
+func (p *WBAuthPrompter) PromptWBLogisticRequestAccessTokenData() string {
+	return ""
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticInvalidAccessTokenData() {
+	p.send("Невалидные данные токена доступа WB logistic")
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticAuthFailed() {
+	p.send("Не удалось провести авторизацию WB logistic, отчеты остаются приостановленными. Повторить: /auth")
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticAuthStorageFailed() {
+	logger.Log(logger.WARN, "WBAuthPrompter.PromptWBLogisticAuthStorageFailed()", "WB logistic authorization using storage failed")
+}
+
+func (p *WBAuthPrompter) PromptWBLogisticAuthSuccessful(login, username string) {
+	p.send(fmt.Sprintf("Сессия WB logistic восстановлена: %s (%s). Отчеты возобновляются", html.EscapeString(username), html.EscapeString(login)))
+}
+
+// request Sends the step to the chat and waits for the /auth answer until the timeout
+func (p *WBAuthPrompter) request(message string) (string, error) {
+	answers := make(chan string, 1)
+	p.mtx.Lock()
+	p.answers = answers
+	p.mtx.Unlock()
+	defer func() {
+		p.mtx.Lock()
+		if p.answers == answers {
+			p.answers = nil
+		}
+		p.mtx.Unlock()
+	}()
+
+	if err := p.services.TelegramBotService.SendMessage(p.chatID, message, "HTML"); err != nil {
+		logger.Logf(logger.ERROR, "WBAuthPrompter.request()", "failed send auth step to admin chat %d: %v", p.chatID, err)
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	select {
+	case answer := <-answers:
+		return answer, nil
+	case <-ctx.Done():
+		logger.Logf(logger.WARN, "WBAuthPrompter.request()", "no answer from admin chat %d in %s", p.chatID, p.timeout)
+		p.send("Время ожидания ответа истекло")
+		return "", ctx.Err()
+	}
+}
+
+func (p *WBAuthPrompter) send(message string) {
+	if err := p.services.TelegramBotService.SendMessage(p.chatID, message, "HTML"); err != nil {
+		logger.Logf(logger.ERROR, "WBAuthPrompter.send()", "failed send message to admin chat %d: %v", p.chatID, err)
+	}
+}